@@ -0,0 +1,441 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// AddonConfig is addon config.cpp with inline material and surface classes.
+//
+// Only CfgMaterials and CfgSurfaces are interpreted; all other config text
+// is kept as is and written back unchanged by Format.
+type AddonConfig struct {
+	Materials []AddonMaterial // Material classes from CfgMaterials
+	Surfaces  []AddonSurface  // Surface classes from CfgSurfaces
+	source    []byte          // Config text; derapified text for binary input
+	Rapified  bool            // Input was rapified (binarized) config
+}
+
+// AddonMaterial is material class defined inline in CfgMaterials.
+//
+// Inherited values from Base are not merged into Material.
+type AddonMaterial struct {
+	Material *Material // Material parsed from class body
+	Name     string    // Class name
+	Base     string    // Base class name
+	indent   string    // Leading whitespace of class line
+	original []byte    // Formatted parsed material, detects edits
+	Line     int       // Line of class keyword in config text
+	open     int       // Offset of body opening brace
+	close    int       // Offset of body closing brace
+}
+
+// AddonSurface is surface class defined in CfgSurfaces.
+type AddonSurface struct {
+	Fields map[string]string // Scalar assignments such as files or friction
+	Name   string            // Class name
+	Base   string            // Base class name
+	Line   int               // Line of class keyword in config text
+}
+
+// configClass is class statement located in config text.
+type configClass struct {
+	name     string        // Class name
+	base     string        // Base class name
+	children []configClass // Nested class statements
+	line     int           // Line of class keyword
+	start    int           // Offset of class keyword
+	open     int           // Offset of opening brace; -1 for declarations
+	close    int           // Offset of closing brace
+}
+
+// ParseAddonConfig parses config.cpp text or rapified config bytes.
+//
+// Material bodies are parsed with the regular RVMAT parser, so preprocessor
+// macros are only resolved when opt enables expression evaluation.
+func ParseAddonConfig(data []byte, opt *ParseOptions) (*AddonConfig, error) {
+	popt := opt.normalize()
+	cfg := &AddonConfig{source: data}
+	if isRapified(data) {
+		text, err := derapify(data)
+		if err != nil {
+			return nil, err
+		}
+		cfg.source = text
+		cfg.Rapified = true
+	}
+
+	sc := &configScanner{src: cfg.source, line: 1}
+	classes, err := sc.body(0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cls := range classes {
+		switch {
+		case strings.EqualFold(cls.name, "CfgMaterials"):
+			for _, child := range cls.children {
+				if child.open < 0 {
+					continue
+				}
+
+				m, err := parseMaterialBytes(cfg.source[child.open+1:child.close], popt)
+				if err != nil {
+					return nil, fmt.Errorf("CfgMaterials/%s (line %d): %w", child.name, child.line, err)
+				}
+				original, err := Format(m, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				cfg.Materials = append(cfg.Materials, AddonMaterial{
+					Material: m,
+					Name:     child.name,
+					Base:     child.base,
+					Line:     child.line,
+					indent:   lineIndent(cfg.source, child.start),
+					original: original,
+					open:     child.open,
+					close:    child.close,
+				})
+			}
+		case strings.EqualFold(cls.name, "CfgSurfaces"):
+			for _, child := range cls.children {
+				if child.open < 0 {
+					continue
+				}
+
+				fields, err := parseSurfaceFields(cfg.source[child.open+1:child.close], popt)
+				if err != nil {
+					return nil, fmt.Errorf("CfgSurfaces/%s (line %d): %w", child.name, child.line, err)
+				}
+				cfg.Surfaces = append(cfg.Surfaces, AddonSurface{
+					Name:   child.name,
+					Base:   child.base,
+					Line:   child.line,
+					Fields: fields,
+				})
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// DecodeAddonConfigFile parses config.cpp or config.bin file.
+func DecodeAddonConfigFile(path string, opt *ParseOptions) (*AddonConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseAddonConfig(b, opt)
+}
+
+// Material returns inline material by class name (case-insensitive).
+func (c *AddonConfig) Material(name string) (*AddonMaterial, bool) {
+	for i := range c.Materials {
+		if strings.EqualFold(c.Materials[i].Name, name) {
+			return &c.Materials[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Format writes config text with edited materials replaced in place.
+//
+// Bodies of unchanged materials and all other config text are copied
+// verbatim. Rapified input is written back as config text.
+func (c *AddonConfig) Format(opt *FormatOptions) ([]byte, error) {
+	fopt := opt.normalize()
+	order := make([]*AddonMaterial, 0, len(c.Materials))
+	for i := range c.Materials {
+		order = append(order, &c.Materials[i])
+	}
+	slices.SortFunc(order, func(a, b *AddonMaterial) int { return a.open - b.open })
+
+	var out bytes.Buffer
+	out.Grow(len(c.source))
+	last := 0
+	for _, am := range order {
+		current, err := Format(am.Material, nil)
+		if err != nil {
+			return nil, fmt.Errorf("CfgMaterials/%s: %w", am.Name, err)
+		}
+		if bytes.Equal(current, am.original) {
+			continue
+		}
+
+		body, err := Format(am.Material, &fopt)
+		if err != nil {
+			return nil, fmt.Errorf("CfgMaterials/%s: %w", am.Name, err)
+		}
+
+		out.Write(c.source[last : am.open+1])
+		out.WriteByte('\n')
+		for line := range strings.Lines(string(body)) {
+			if strings.TrimSpace(line) != "" {
+				out.WriteString(am.indent + fopt.Indent)
+			}
+			out.WriteString(line)
+		}
+		out.WriteString(am.indent)
+		last = am.close
+	}
+	out.Write(c.source[last:])
+
+	return out.Bytes(), nil
+}
+
+// EncodeFile writes formatted config text to path.
+func (c *AddonConfig) EncodeFile(path string, opt *FormatOptions) error {
+	b, err := c.Format(opt)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// parseSurfaceFields collects scalar assignments of surface class body.
+func parseSurfaceFields(body []byte, popt ParseOptions) (map[string]string, error) {
+	p := newParser(bytes.NewReader(body), popt)
+	fields := make(map[string]string)
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Type == tokEOF {
+			return fields, nil
+		}
+
+		n, err := p.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		an, ok := n.(assignNode)
+		if !ok || an.IsArray {
+			continue
+		}
+
+		switch an.Value.Kind {
+		case valueNumber:
+			fields[an.Name] = formatFloatPrecision(an.Value.Num, -1)
+		case valueString, valueIdent:
+			fields[an.Name] = an.Value.Str
+		}
+	}
+}
+
+// lineIndent returns leading whitespace of line containing offset.
+func lineIndent(src []byte, offset int) string {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	end := start
+	for end < offset && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+
+	return string(src[start:end])
+}
+
+// configScanner locates class statements in config text.
+//
+// It only tracks braces, strings, comments, and preprocessor lines, so
+// statements outside material classes may use any config syntax.
+type configScanner struct {
+	src  []byte // Config text
+	pos  int    // Current offset
+	line int    // Current line
+}
+
+// body scans statements until closing brace or end of input.
+func (s *configScanner) body(depth int) ([]configClass, error) {
+	if depth > rapMaxDepth {
+		return nil, s.errorf("class nesting too deep")
+	}
+
+	var classes []configClass
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.src) {
+			if depth > 0 {
+				return nil, s.errorf("unexpected end of config")
+			}
+			return classes, nil
+		}
+		if s.src[s.pos] == '}' {
+			if depth == 0 {
+				return nil, s.errorf("unexpected '}'")
+			}
+			return classes, nil
+		}
+
+		start, line := s.pos, s.line
+		if word := s.word(); word != "class" {
+			if err := s.skipStatement(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		cls := configClass{start: start, line: line, open: -1}
+		s.skipSpace()
+		if cls.name = s.word(); cls.name == "" {
+			return nil, s.errorf("expected class name")
+		}
+		s.skipSpace()
+		if s.pos < len(s.src) && s.src[s.pos] == ':' {
+			s.pos++
+			s.skipSpace()
+			cls.base = s.word()
+			s.skipSpace()
+		}
+
+		switch {
+		case s.pos < len(s.src) && s.src[s.pos] == ';':
+			// Forward declaration: class Name;
+			s.pos++
+		case s.pos < len(s.src) && s.src[s.pos] == '{':
+			cls.open = s.pos
+			s.pos++
+			children, err := s.body(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			cls.children = children
+			cls.close = s.pos
+			s.pos++
+			s.skipSpace()
+			if s.pos < len(s.src) && s.src[s.pos] == ';' {
+				s.pos++
+			}
+		default:
+			return nil, s.errorf("expected '{' or ';' after class %s", cls.name)
+		}
+
+		classes = append(classes, cls)
+	}
+}
+
+// skipStatement skips non-class statement up to ';' outside braces.
+func (s *configScanner) skipStatement() error {
+	depth := 0
+	for s.pos < len(s.src) {
+		s.skipSpace()
+		if s.pos >= len(s.src) {
+			break
+		}
+
+		switch c := s.src[s.pos]; c {
+		case '"', '\'':
+			if err := s.skipString(c); err != nil {
+				return err
+			}
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				// Missing semicolon before class end; let caller handle brace.
+				return nil
+			}
+			depth--
+		case ';':
+			if depth == 0 {
+				s.pos++
+				return nil
+			}
+		}
+		s.pos++
+	}
+
+	return nil
+}
+
+// skipString skips quoted string with doubled-quote escapes.
+func (s *configScanner) skipString(quote byte) error {
+	line := s.line
+	for s.pos++; s.pos < len(s.src); s.pos++ {
+		switch s.src[s.pos] {
+		case '\n':
+			s.line++
+		case quote:
+			if s.pos+1 < len(s.src) && s.src[s.pos+1] == quote {
+				s.pos++
+				continue
+			}
+			s.pos++
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: line %d: unterminated string", ErrInvalidAddonConfig, line)
+}
+
+// skipSpace skips whitespace, comments, and preprocessor lines.
+func (s *configScanner) skipSpace() {
+	lineStart := s.pos == 0 || s.src[s.pos-1] == '\n'
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == '\n':
+			s.line++
+			s.pos++
+			lineStart = true
+		case c == ' ' || c == '\t' || c == '\r':
+			s.pos++
+		case c == '#' && lineStart:
+			// Preprocessor directive with backslash line continuations.
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				if s.src[s.pos] == '\\' && s.pos+1 < len(s.src) && s.src[s.pos+1] == '\n' {
+					s.line++
+					s.pos++
+				}
+				s.pos++
+			}
+		case c == '/' && s.pos+1 < len(s.src) && s.src[s.pos+1] == '/':
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				s.pos++
+			}
+		case c == '/' && s.pos+1 < len(s.src) && s.src[s.pos+1] == '*':
+			end := bytes.Index(s.src[s.pos+2:], []byte("*/"))
+			if end < 0 {
+				end = len(s.src) - s.pos - 2
+			} else {
+				end += 2
+			}
+			s.line += bytes.Count(s.src[s.pos:s.pos+2+end], []byte("\n"))
+			s.pos += 2 + end
+		default:
+			return
+		}
+	}
+}
+
+// word reads identifier-like word.
+func (s *configScanner) word() string {
+	start := s.pos
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		if c != '_' && !isDigit(c) && !isASCIIAlpha(c) {
+			break
+		}
+		s.pos++
+	}
+
+	return string(s.src[start:s.pos])
+}
+
+// errorf returns addon config error at current line.
+func (s *configScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidAddonConfig, s.line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// rapSignature starts rapified (binarized) config files.
+	rapSignature = "\x00raP"
+
+	// rapHeaderSize is signature, two reserved words, and enum offset;
+	// root class body follows the header.
+	rapHeaderSize = 16
+
+	// rapMaxDepth limits class and array nesting to reject garbage input.
+	rapMaxDepth = 64
+)
+
+// isRapified reports binary config signature.
+func isRapified(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(rapSignature))
+}
+
+// rapReader decodes rapified config into config text.
+type rapReader struct {
+	seen   map[uint32]struct{} // Decoded class body offsets
+	indent string              // Indentation unit
+	data   []byte              // Rapified input
+	out    bytes.Buffer        // Config text output
+}
+
+// derapify converts rapified config data into equivalent config text.
+func derapify(data []byte) ([]byte, error) {
+	if !isRapified(data) || len(data) < rapHeaderSize {
+		return nil, fmt.Errorf("%w: missing raP header", ErrInvalidAddonConfig)
+	}
+
+	r := &rapReader{data: data, indent: "    ", seen: make(map[uint32]struct{})}
+	if err := r.classBody(rapHeaderSize, 0); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddonConfig, err)
+	}
+	if err := r.enums(binary.LittleEndian.Uint32(data[12:16])); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddonConfig, err)
+	}
+
+	return r.out.Bytes(), nil
+}
+
+// classBody writes class entries stored at offset.
+func (r *rapReader) classBody(offset uint32, depth int) error {
+	if depth > rapMaxDepth {
+		return errors.New("class nesting too deep")
+	}
+	// Each body is written once; shared or cyclic offsets are corrupt data.
+	if _, ok := r.seen[offset]; ok {
+		return fmt.Errorf("class body at %d is referenced twice", offset)
+	}
+	r.seen[offset] = struct{}{}
+
+	pos := int(offset)
+	// Inherited class name is part of class header written by caller.
+	if _, err := r.asciiz(&pos); err != nil {
+		return err
+	}
+	count, err := r.compressed(&pos)
+	if err != nil {
+		return err
+	}
+
+	prefix := string(bytes.Repeat([]byte(r.indent), depth))
+	for range count {
+		kind, err := r.byte(&pos)
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case 0: // class
+			name, err := r.asciiz(&pos)
+			if err != nil {
+				return err
+			}
+			body, err := r.uint32(&pos)
+			if err != nil {
+				return err
+			}
+
+			bodyPos := int(body)
+			base, err := r.asciiz(&bodyPos)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + "class " + name)
+			if base != "" {
+				r.out.WriteString(": " + base)
+			}
+			r.out.WriteString("\n" + prefix + "{\n")
+			if err := r.classBody(body, depth+1); err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + "};\n")
+		case 1: // scalar value
+			sub, err := r.byte(&pos)
+			if err != nil {
+				return err
+			}
+			name, err := r.asciiz(&pos)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + name + "=")
+			if err := r.scalar(&pos, sub); err != nil {
+				return err
+			}
+			r.out.WriteString(";\n")
+		case 2, 5: // array, array with += / -= flag
+			op := "="
+			if kind == 5 {
+				flag, err := r.uint32(&pos)
+				if err != nil {
+					return err
+				}
+				switch flag {
+				case 1:
+					op = "+="
+				case 2:
+					op = "-="
+				}
+			}
+			name, err := r.asciiz(&pos)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + name + "[]" + op)
+			if err := r.array(&pos, depth); err != nil {
+				return err
+			}
+			r.out.WriteString(";\n")
+		case 3: // extern class declaration
+			name, err := r.asciiz(&pos)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + "class " + name + ";\n")
+		case 4: // delete class
+			name, err := r.asciiz(&pos)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(prefix + "delete " + name + ";\n")
+		default:
+			return fmt.Errorf("unknown entry type %d at %d", kind, pos-1)
+		}
+	}
+
+	return nil
+}
+
+// array writes one {...} array value.
+func (r *rapReader) array(pos *int, depth int) error {
+	if depth > rapMaxDepth {
+		return errors.New("array nesting too deep")
+	}
+
+	count, err := r.compressed(pos)
+	if err != nil {
+		return err
+	}
+
+	r.out.WriteByte('{')
+	for i := range count {
+		if i > 0 {
+			r.out.WriteByte(',')
+		}
+		kind, err := r.byte(pos)
+		if err != nil {
+			return err
+		}
+		if kind == 3 {
+			if err := r.array(pos, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.scalar(pos, kind); err != nil {
+			return err
+		}
+	}
+	r.out.WriteByte('}')
+
+	return nil
+}
+
+// scalar writes string, float, or integer value of given subtype.
+func (r *rapReader) scalar(pos *int, sub byte) error {
+	switch sub {
+	case 0, 4: // string, evaluated expression
+		s, err := r.asciiz(pos)
+		if err != nil {
+			return err
+		}
+		r.out.WriteString(quoteConfigString(s))
+	case 1: // float32
+		v, err := r.uint32(pos)
+		if err != nil {
+			return err
+		}
+		f := math.Float32frombits(v)
+		r.out.WriteString(strconv.FormatFloat(float64(f), 'f', -1, 32))
+	case 2: // int32
+		v, err := r.uint32(pos)
+		if err != nil {
+			return err
+		}
+		r.out.WriteString(strconv.FormatInt(int64(int32(v)), 10))
+	case 6: // int64
+		if *pos+8 > len(r.data) {
+			return errors.New("truncated int64")
+		}
+		v := binary.LittleEndian.Uint64(r.data[*pos:])
+		*pos += 8
+		r.out.WriteString(strconv.FormatInt(int64(v), 10))
+	default:
+		return fmt.Errorf("unknown value type %d at %d", sub, *pos-1)
+	}
+
+	return nil
+}
+
+// enums writes enum block stored at offset; zero offset means none.
+func (r *rapReader) enums(offset uint32) error {
+	if offset == 0 || int(offset) == len(r.data) {
+		return nil
+	}
+
+	pos := int(offset)
+	count, err := r.uint32(&pos)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	r.out.WriteString("enum\n{\n")
+	for i := range count {
+		name, err := r.asciiz(&pos)
+		if err != nil {
+			return err
+		}
+		v, err := r.uint32(&pos)
+		if err != nil {
+			return err
+		}
+		r.out.WriteString(r.indent + name + "=" + strconv.FormatInt(int64(int32(v)), 10))
+		if i+1 < count {
+			r.out.WriteByte(',')
+		}
+		r.out.WriteByte('\n')
+	}
+	r.out.WriteString("};\n")
+
+	return nil
+}
+
+// byte reads one byte.
+func (r *rapReader) byte(pos *int) (byte, error) {
+	if *pos < 0 || *pos >= len(r.data) {
+		return 0, fmt.Errorf("unexpected end of data at %d", *pos)
+	}
+	b := r.data[*pos]
+	*pos++
+
+	return b, nil
+}
+
+// uint32 reads little-endian uint32.
+func (r *rapReader) uint32(pos *int) (uint32, error) {
+	if *pos < 0 || *pos+4 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of data at %d", *pos)
+	}
+	v := binary.LittleEndian.Uint32(r.data[*pos:])
+	*pos += 4
+
+	return v, nil
+}
+
+// compressed reads 7-bit variable-length count.
+func (r *rapReader) compressed(pos *int) (int, error) {
+	v := 0
+	for shift := 0; shift < 28; shift += 7 {
+		b, err := r.byte(pos)
+		if err != nil {
+			return 0, err
+		}
+		v |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if v > len(r.data) {
+				return 0, fmt.Errorf("entry count %d exceeds data size", v)
+			}
+			return v, nil
+		}
+	}
+
+	return 0, errors.New("compressed integer too long")
+}
+
+// asciiz reads NUL-terminated string.
+func (r *rapReader) asciiz(pos *int) (string, error) {
+	if *pos < 0 || *pos > len(r.data) {
+		return "", fmt.Errorf("unexpected end of data at %d", *pos)
+	}
+	end := bytes.IndexByte(r.data[*pos:], 0)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at %d", *pos)
+	}
+	s := string(r.data[*pos : *pos+end])
+	*pos += end + 1
+
+	return s, nil
+}
@@ -0,0 +1,206 @@
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testAddonConfig = `#include "basicDefines.hpp"
+#define SPEC 40
+
+class CfgPatches
+{
+	class Test_Data
+	{
+		units[]={};
+		requiredAddons[]={"DZ_Data"};
+	};
+};
+class CfgVehicles
+{
+	class HouseNoDestruct;
+	class Test_Crate: HouseNoDestruct
+	{
+		hiddenSelections[]+={"camo"};
+	};
+};
+class CfgMaterials
+{
+	class Test_Metal
+	{
+		ambient[]={1,1,1,1};
+		diffuse[]={1,1,1,1};
+		PixelShaderID="Super";
+		class Stage1
+		{
+			texture="test\data\metal_nohq.paa";
+			uvSource="tex";
+		};
+	};
+	class Test_Rust: Test_Metal
+	{
+		specularPower=SPEC; // macro, kept verbatim
+	};
+};
+class CfgSurfaces
+{
+	class DZ_SurfacesInt;
+	class test_gravel: DZ_SurfacesInt
+	{
+		files="test_gravel_*";
+		friction=0.85;
+		soundEnviron="gravel";
+		isLiquid=0;
+	};
+};
+`
+
+func TestParseAddonConfig(t *testing.T) {
+	cfg, err := ParseAddonConfig([]byte(testAddonConfig), &ParseOptions{
+		EvalExpressions:     true,
+		ExpressionConstants: map[string]float64{"SPEC": 40},
+	})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if len(cfg.Materials) != 2 {
+		t.Fatalf("expected 2 materials, got %d", len(cfg.Materials))
+	}
+	metal, ok := cfg.Material("test_metal")
+	if !ok || metal.Line != 22 || metal.Material.PixelShaderID != "Super" || len(metal.Material.Stages) != 1 {
+		t.Fatalf("unexpected Test_Metal: %+v", metal)
+	}
+	rust := cfg.Materials[1]
+	if rust.Base != "Test_Metal" || rust.Material.SpecularPower == nil || *rust.Material.SpecularPower != 40 {
+		t.Fatalf("unexpected Test_Rust: %+v", rust)
+	}
+
+	if len(cfg.Surfaces) != 1 {
+		t.Fatalf("expected 1 surface, got %d", len(cfg.Surfaces))
+	}
+	surf := cfg.Surfaces[0]
+	if surf.Name != "test_gravel" || surf.Fields["files"] != "test_gravel_*" || surf.Fields["friction"] != "0.85" {
+		t.Fatalf("unexpected surface: %+v", surf)
+	}
+
+	// Unchanged config is written back byte-exact.
+	out, err := cfg.Format(nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if string(out) != testAddonConfig {
+		t.Fatalf("unchanged config differs:\n%s", out)
+	}
+}
+
+func TestAddonConfigWriteBack(t *testing.T) {
+	// Unknown macros stay unresolved and are written back verbatim.
+	cfg, err := ParseAddonConfig([]byte(testAddonConfig), &ParseOptions{EvalExpressions: true})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	metal, _ := cfg.Material("Test_Metal")
+	metal.Material.Stages[0].UVSource = ""
+	metal.Material.Stages[0].TexGen = "0"
+	out, err := cfg.Format(&FormatOptions{Indent: "\t"})
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	text := string(out)
+	if !strings.Contains(text, "\tclass Test_Metal\n\t{\n\t\tambient[]={1, 1, 1, 1};\n") ||
+		!strings.Contains(text, "\t\t\ttexGen=\"0\";\n") ||
+		!strings.Contains(text, "\t\t};\n\t};\n\tclass Test_Rust: Test_Metal\n") {
+		t.Fatalf("material not replaced in place:\n%s", text)
+	}
+	if !strings.Contains(text, "specularPower=SPEC; // macro, kept verbatim") {
+		t.Fatalf("unchanged material was rewritten:\n%s", text)
+	}
+
+	again, err := ParseAddonConfig(out, &ParseOptions{EvalExpressions: true})
+	if err != nil {
+		t.Fatalf("reparse: %v", err)
+	}
+	if got, _ := again.Material("Test_Metal"); got.Material.Stages[0].TexGen != "0" {
+		t.Fatalf("edit lost after reparse")
+	}
+}
+
+func TestParseAddonConfigRapified(t *testing.T) {
+	cfg, err := ParseAddonConfig(testRapConfig(), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !cfg.Rapified || len(cfg.Materials) != 1 {
+		t.Fatalf("expected 1 rapified material, got %+v", cfg)
+	}
+
+	m := cfg.Materials[0]
+	if m.Name != "Bin_Mat" || m.Base != "Base_Mat" || m.Material.PixelShaderID != "Normal" {
+		t.Fatalf("unexpected material %+v", m)
+	}
+	if len(m.Material.Ambient) != 2 || m.Material.Ambient[0] != 0.5 || m.Material.Ambient[1] != 1 {
+		t.Fatalf("unexpected ambient %v", m.Material.Ambient)
+	}
+
+	if _, err := ParseAddonConfig([]byte("\x00raP\x00\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00\x00\x00\x05"), nil); !errors.Is(err, ErrInvalidAddonConfig) {
+		t.Fatalf("expected ErrInvalidAddonConfig for truncated data, got %v", err)
+	}
+}
+
+// testRapConfig builds rapified CfgMaterials { class Bin_Mat: Base_Mat {...}; }.
+func testRapConfig() []byte {
+	var b bytes.Buffer
+	u32 := func(v uint32) { _ = binary.Write(&b, binary.LittleEndian, v) }
+	str := func(s string) { b.WriteString(s); b.WriteByte(0) }
+	patch := func(at int, v uint32) { binary.LittleEndian.PutUint32(b.Bytes()[at:], v) }
+
+	b.WriteString(rapSignature)
+	u32(0)
+	u32(8)
+	enumAt := b.Len()
+	u32(0)
+
+	// Root body: one class entry.
+	str("")
+	b.WriteByte(1)
+	b.WriteByte(0)
+	str("CfgMaterials")
+	cfgAt := b.Len()
+	u32(0)
+
+	// CfgMaterials body: one class entry.
+	patch(cfgAt, uint32(b.Len()))
+	str("")
+	b.WriteByte(1)
+	b.WriteByte(0)
+	str("Bin_Mat")
+	matAt := b.Len()
+	u32(0)
+
+	// Bin_Mat body: string value and float array.
+	patch(matAt, uint32(b.Len()))
+	str("Base_Mat")
+	b.WriteByte(2)
+	b.WriteByte(1)
+	b.WriteByte(0)
+	str("PixelShaderID")
+	str("Normal")
+	b.WriteByte(2)
+	str("ambient")
+	b.WriteByte(2)
+	b.WriteByte(1)
+	u32(math.Float32bits(0.5))
+	b.WriteByte(2)
+	u32(1)
+
+	patch(enumAt, uint32(b.Len()))
+	u32(0)
+	return b.Bytes()
+}
@@ -0,0 +1,601 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+	"github.com/woozymasta/lintkit/linting"
+	"go.yaml.in/yaml/v3"
+)
+
+// projectConfigSectionKeys lists top-level keys merged between config layers.
+var projectConfigSectionKeys = []string{
+	"validate",
+	"texture_validate",
+	"normalize",
+	"format",
+	"generate",
+	"lint",
+}
+
+// projectConfigPathKeys lists section keys holding OS paths that are
+// resolved against directory of config file setting them.
+var projectConfigPathKeys = [][2]string{
+	{"validate", "game_root"},
+	{"generate", "output_path"},
+}
+
+// ParseProjectConfig parses project config YAML (JSON is accepted as YAML subset).
+//
+// Unknown keys are rejected to catch typos early.
+func ParseProjectConfig(data []byte) (*ProjectConfig, error) {
+	cfg := &ProjectConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProjectConfig, err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProjectConfig, err)
+	}
+
+	cfg.raw = projectConfigSections(raw)
+	rawOverrides, _ := raw["overrides"].([]any)
+	for i := range cfg.Overrides {
+		if len(cfg.Overrides[i].Files) == 0 {
+			return nil, fmt.Errorf("%w: overrides[%d].files is empty", ErrInvalidProjectConfig, i)
+		}
+
+		if _, err := linting.CompilePathRulesMatcher(cfg.Overrides[i].Files, projectPathRulesOptions); err != nil {
+			return nil, fmt.Errorf("%w: overrides[%d].files: %w", ErrInvalidProjectConfig, i, err)
+		}
+
+		var section map[string]any
+		if i < len(rawOverrides) {
+			section, _ = rawOverrides[i].(map[string]any)
+		}
+		cfg.rawOverrides = append(cfg.rawOverrides, projectConfigSections(section))
+	}
+
+	return cfg, nil
+}
+
+// LoadProjectConfig reads and parses one project config file.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project config: %w", err)
+	}
+
+	cfg, err := ParseProjectConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg.Path = path
+	return cfg, nil
+}
+
+// FindProjectConfigs returns config files from dir up to filesystem root.
+//
+// Result is ordered outermost first. Search stops at a config with root: true.
+// In one directory .rvmat.yaml has priority over .rvmat.yml.
+func FindProjectConfigs(dir string) ([]string, error) {
+	configs, err := findProjectConfigs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(configs))
+	for _, cfg := range configs {
+		paths = append(paths, cfg.Path)
+	}
+
+	return paths, nil
+}
+
+// LoadProjectSettings discovers config files for target file and merges them.
+//
+// Files are merged outermost first: inner directories override outer ones and
+// matching glob overrides apply after their file-wide sections. Missing
+// config files produce empty settings, not an error.
+func LoadProjectSettings(target string) (ProjectSettings, error) {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return ProjectSettings{}, fmt.Errorf("resolve target path: %w", err)
+	}
+
+	configs, err := findProjectConfigs(filepath.Dir(target))
+	if err != nil {
+		return ProjectSettings{}, err
+	}
+
+	return MergeProjectConfigs(target, configs...)
+}
+
+// findProjectConfigs loads config files from dir upward, outermost first.
+func findProjectConfigs(dir string) ([]*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve config dir: %w", err)
+	}
+
+	var found []*ProjectConfig
+	for {
+		for _, name := range []string{ProjectConfigFileName, ProjectConfigAltFileName} {
+			candidate := filepath.Join(dir, name)
+			info, err := os.Stat(candidate)
+			if err != nil || info.IsDir() {
+				continue
+			}
+
+			cfg, err := LoadProjectConfig(candidate)
+			if err != nil {
+				return nil, err
+			}
+
+			found = append(found, cfg)
+			if cfg.Root {
+				slices.Reverse(found)
+				return found, nil
+			}
+
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	slices.Reverse(found)
+	return found, nil
+}
+
+// Settings returns effective settings of this config for target file.
+func (c *ProjectConfig) Settings(target string) (ProjectSettings, error) {
+	return MergeProjectConfigs(target, c)
+}
+
+// MergeProjectConfigs merges configs (outermost first) for target file.
+//
+// Override globs are matched against target path relative to each config
+// file directory; configs without Path match target as given.
+// Keys set by later layers replace earlier values, lint rules are appended.
+// Relative validate.game_root and generate.output_path values are resolved
+// against directory of config file that sets them.
+func MergeProjectConfigs(target string, configs ...*ProjectConfig) (ProjectSettings, error) {
+	merged := make(map[string]any, len(projectConfigSectionKeys))
+	var settings ProjectSettings
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+
+		rel, ok := projectRelativePath(cfg.Path, target)
+		if settings.target == "" {
+			settings.target = rel
+		}
+		if cfg.Path != "" {
+			settings.Files = append(settings.Files, cfg.Path)
+		}
+
+		dir := ""
+		if cfg.Path != "" {
+			dir = filepath.Dir(cfg.Path)
+		}
+
+		mergeProjectValues(merged, anchorProjectPaths(cfg.raw, dir))
+		if !ok {
+			continue
+		}
+
+		for i, override := range cfg.Overrides {
+			matcher, err := linting.CompilePathRulesMatcher(override.Files, projectPathRulesOptions)
+			if err != nil {
+				return ProjectSettings{}, fmt.Errorf("%w: overrides[%d].files: %w", ErrInvalidProjectConfig, i, err)
+			}
+			if !matcher.Match(rel, false) || i >= len(cfg.rawOverrides) {
+				continue
+			}
+
+			mergeProjectValues(merged, anchorProjectPaths(cfg.rawOverrides[i], dir))
+		}
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return ProjectSettings{}, fmt.Errorf("%w: %w", ErrInvalidProjectConfig, err)
+	}
+	if err := yaml.Unmarshal(data, &settings.ProjectConfigSections); err != nil {
+		return ProjectSettings{}, fmt.Errorf("%w: %w", ErrInvalidProjectConfig, err)
+	}
+
+	return settings, nil
+}
+
+// MergeValidateOptions returns config validate options overlaid by explicit ones.
+//
+// Explicit non-zero fields win. Zero fields keep config values unless marked
+// in set (for example ValidateFields{DisableExtensionsCheck: true}), so
+// explicit false or 0 can override config; nil set marks no fields. Other
+// Merge*Options methods follow same rules. Nil result means neither side is set.
+func (s ProjectSettings) MergeValidateOptions(explicit *ValidateOptions, set *ValidateFields) *ValidateOptions {
+	return mergeExplicitOptions(s.Validate, explicit, set)
+}
+
+// MergeTextureValidateOptions returns config texture validate options overlaid by explicit ones.
+func (s ProjectSettings) MergeTextureValidateOptions(explicit *TextureValidateOptions, set *TextureValidateFields) *TextureValidateOptions {
+	return mergeExplicitOptions(s.TextureValidate, explicit, set)
+}
+
+// MergeNormalizeOptions returns config normalize options overlaid by explicit ones.
+//
+// Config normalize section replaces Normalize defaults entirely, same as
+// passing non-nil NormalizeOptions.
+func (s ProjectSettings) MergeNormalizeOptions(explicit *NormalizeOptions, set *NormalizeFields) *NormalizeOptions {
+	return mergeExplicitOptions(s.Normalize, explicit, set)
+}
+
+// MergeFormatOptions returns config format options overlaid by explicit ones.
+func (s ProjectSettings) MergeFormatOptions(explicit *FormatOptions, set *FormatFields) *FormatOptions {
+	return mergeExplicitOptions(s.Format, explicit, set)
+}
+
+// MergeGenerateSetOptions returns config generator defaults overlaid by explicit ones.
+//
+// TextureOverrides maps are merged by key with explicit entries winning.
+func (s ProjectSettings) MergeGenerateSetOptions(explicit *GenerateSetOptions, set *GenerateSetFields) *GenerateSetOptions {
+	return mergeExplicitOptions(s.Generate, explicit, set)
+}
+
+// LintPolicy builds lintkit run policy from lint section.
+//
+// Selectors are validated against registered rvmat rules.
+func (s ProjectSettings) LintPolicy() (linting.RunPolicy, error) {
+	if s.Lint == nil {
+		return linting.RunPolicy{}, nil
+	}
+
+	policy, err := s.Lint.Build(linting.PathRulesCompiler(projectPathRulesOptions))
+	if err != nil {
+		return linting.RunPolicy{}, fmt.Errorf("%w: lint: %w", ErrInvalidProjectConfig, err)
+	}
+
+	specs, err := projectRuleSpecs()
+	if err != nil {
+		return linting.RunPolicy{}, err
+	}
+	if err := policy.Validate(specs); err != nil {
+		return linting.RunPolicy{}, fmt.Errorf("%w: lint: %w", ErrInvalidProjectConfig, err)
+	}
+
+	return policy, nil
+}
+
+// ApplyLintPolicy filters diagnostics by lint section for settings target.
+//
+// Diagnostics of disabled rules are dropped and severities are overridden.
+// Excluded target returns no diagnostics. Unknown codes are kept as is.
+func (s ProjectSettings) ApplyLintPolicy(diagnostics []lint.Diagnostic) ([]lint.Diagnostic, error) {
+	if s.Lint == nil {
+		return diagnostics, nil
+	}
+
+	policy, err := s.LintPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if !policy.PathEnabled(s.target, false) {
+		return nil, nil
+	}
+
+	catalog, err := getDiagnosticCodeCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]lint.Diagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		code, ok := lint.ParsePublicCode(diagnostic.Code)
+		spec, known := catalog.ByCode(code)
+		if !ok || !known {
+			out = append(out, diagnostic)
+			continue
+		}
+
+		decision := policy.Resolve(catalog.RuleSpec(spec), s.target, false)
+		if !decision.Enabled {
+			continue
+		}
+
+		diagnostic.Severity = decision.Severity
+		out = append(out, diagnostic)
+	}
+
+	return out, nil
+}
+
+// projectPathRulesOptions matches config globs case-insensitively like game paths.
+var projectPathRulesOptions = linting.PathRulesCompilerOptions{CaseInsensitive: true}
+
+// projectRuleSpecs returns lint rule specs for all rvmat diagnostics.
+func projectRuleSpecs() ([]lint.RuleSpec, error) {
+	catalog, err := getDiagnosticCodeCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	specs := DiagnosticCatalog()
+	out := make([]lint.RuleSpec, 0, len(specs))
+	for _, spec := range specs {
+		out = append(out, catalog.RuleSpec(spec))
+	}
+
+	return out, nil
+}
+
+// projectConfigSections keeps only mergeable sections from decoded config map.
+func projectConfigSections(raw map[string]any) map[string]any {
+	out := make(map[string]any, len(projectConfigSectionKeys))
+	for _, key := range projectConfigSectionKeys {
+		if value, ok := raw[key]; ok && value != nil {
+			out[key] = value
+		}
+	}
+
+	return out
+}
+
+// anchorProjectPaths returns sections with relative path values joined to dir.
+//
+// Input maps are not modified; empty dir returns sections unchanged.
+func anchorProjectPaths(sections map[string]any, dir string) map[string]any {
+	if dir == "" {
+		return sections
+	}
+
+	out, cloned := sections, false
+	for _, key := range projectConfigPathKeys {
+		section, ok := out[key[0]].(map[string]any)
+		if !ok {
+			continue
+		}
+		value, ok := section[key[1]].(string)
+		if !ok || strings.TrimSpace(value) == "" || isAbsOSPath(value) {
+			continue
+		}
+
+		if !cloned {
+			out, cloned = maps.Clone(sections), true
+		}
+		section = maps.Clone(section)
+		section[key[1]] = filepath.Join(dir, normalizeOSPath(value))
+		out[key[0]] = section
+	}
+
+	return out
+}
+
+// isAbsOSPath reports whether path is absolute on current OS or has
+// Windows drive or root prefix (for example P:\ or \\server\share).
+func isAbsOSPath(path string) bool {
+	return filepath.IsAbs(path) || hasVolume(path) ||
+		strings.HasPrefix(path, `\`) || strings.HasPrefix(path, "/")
+}
+
+// projectRelativePath returns slash target path relative to config directory.
+//
+// ok is false when target is outside config directory.
+func projectRelativePath(configPath, target string) (string, bool) {
+	if configPath == "" {
+		return filepath.ToSlash(target), true
+	}
+
+	base, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+// mergeProjectValues deep-merges src config map into dst.
+//
+// Nested maps merge by key, "rules" lists are appended because later lint
+// entries override earlier ones, other values are replaced.
+func mergeProjectValues(dst, src map[string]any) {
+	for key, value := range src {
+		switch typed := value.(type) {
+		case map[string]any:
+			current, ok := dst[key].(map[string]any)
+			if !ok {
+				current = make(map[string]any, len(typed))
+				dst[key] = current
+			}
+			mergeProjectValues(current, typed)
+
+		case []any:
+			if current, ok := dst[key].([]any); ok && key == "rules" {
+				dst[key] = append(slices.Clone(current), typed...)
+				continue
+			}
+			dst[key] = slices.Clone(typed)
+
+		default:
+			dst[key] = value
+		}
+	}
+}
+
+// mergeExplicitOptions overlays explicit non-zero fields onto config options copy.
+//
+// Fields marked in set by same-named bool field are overlaid even when zero.
+func mergeExplicitOptions[T, F any](config, explicit *T, set *F) *T {
+	switch {
+	case config == nil && explicit == nil:
+		return nil
+	case config == nil:
+		out := *explicit
+		return &out
+	}
+
+	out := *config
+	if explicit == nil {
+		return &out
+	}
+
+	var marked reflect.Value
+	if set != nil {
+		marked = reflect.ValueOf(set).Elem()
+	}
+
+	dst := reflect.ValueOf(&out).Elem()
+	src := reflect.ValueOf(explicit).Elem()
+	for i := range dst.NumField() {
+		field := src.Field(i)
+		if !dst.Field(i).CanSet() || field.IsZero() && !optionFieldSet(marked, dst.Type().Field(i).Name) {
+			continue
+		}
+
+		if field.Kind() == reflect.Map && !dst.Field(i).IsNil() {
+			combined := reflect.MakeMapWithSize(field.Type(), dst.Field(i).Len()+field.Len())
+			for _, m := range []reflect.Value{dst.Field(i), field} {
+				iter := m.MapRange()
+				for iter.Next() {
+					combined.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			dst.Field(i).Set(combined)
+			continue
+		}
+
+		dst.Field(i).Set(field)
+	}
+
+	return &out
+}
+
+// optionFieldSet reports whether bool field name is true in marked fields struct.
+func optionFieldSet(marked reflect.Value, name string) bool {
+	if !marked.IsValid() {
+		return false
+	}
+
+	field := marked.FieldByName(name)
+	return field.IsValid() && field.Kind() == reflect.Bool && field.Bool()
+}
+
+// namedEnum is generator enum with human-readable String names.
+type namedEnum interface {
+	~uint8
+	fmt.Stringer
+}
+
+// unmarshalNamedEnumYAML decodes enum from its name or numeric value.
+func unmarshalNamedEnumYAML[T namedEnum](node *yaml.Node, last T, kind string) (T, error) {
+	var number uint8
+	if err := node.Decode(&number); err == nil {
+		if T(number) > last {
+			return 0, fmt.Errorf("%w %s=%d", ErrInvalidGenerateOption, kind, number)
+		}
+
+		return T(number), nil
+	}
+
+	var name string
+	if err := node.Decode(&name); err != nil {
+		return 0, err
+	}
+
+	normalized := strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+	for value := T(0); value <= last; value++ {
+		if strings.EqualFold(value.String(), normalized) {
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w %s=%q", ErrInvalidGenerateOption, kind, name)
+}
+
+// UnmarshalYAML decodes base material from name (e.g. "steel") or number.
+func (m *BaseMaterial) UnmarshalYAML(node *yaml.Node) error {
+	value, err := unmarshalNamedEnumYAML(node, BaseMaterialSkin, "base_material")
+	if err != nil {
+		return err
+	}
+
+	*m = value
+	return nil
+}
+
+// UnmarshalYAML decodes finish from name (e.g. "gloss") or number.
+func (f *Finish) UnmarshalYAML(node *yaml.Node) error {
+	value, err := unmarshalNamedEnumYAML(node, FinishPolished, "finish")
+	if err != nil {
+		return err
+	}
+
+	*f = value
+	return nil
+}
+
+// UnmarshalYAML decodes condition from name (e.g. "worn") or number.
+func (c *Condition) UnmarshalYAML(node *yaml.Node) error {
+	value, err := unmarshalNamedEnumYAML(node, ConditionOxidized, "condition")
+	if err != nil {
+		return err
+	}
+
+	*c = value
+	return nil
+}
+
+// UnmarshalYAML decodes auto-fill mode from name (e.g. "disabled") or number.
+func (m *TextureAutoFillMode) UnmarshalYAML(node *yaml.Node) error {
+	value, err := unmarshalNamedEnumYAML(node, TextureAutoFillModeFromStageOverride, "auto_fill_mode")
+	if err != nil {
+		return err
+	}
+
+	*m = value
+	return nil
+}
+
+// MarshalYAML encodes base material by name.
+func (m BaseMaterial) MarshalYAML() (any, error) { return m.String(), nil }
+
+// MarshalYAML encodes finish by name.
+func (f Finish) MarshalYAML() (any, error) { return f.String(), nil }
+
+// MarshalYAML encodes condition by name.
+func (c Condition) MarshalYAML() (any, error) { return c.String(), nil }
+
+// MarshalYAML encodes auto-fill mode by name.
+func (m TextureAutoFillMode) MarshalYAML() (any, error) { return m.String(), nil }
@@ -0,0 +1,257 @@
+package rvmat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+const rootProjectConfig = `root: true
+validate:
+  game_root: P:\
+  texture_path_mode: trust
+  trusted_prefixes: ['dz\', 'mymod\']
+  exclude_paths: ['mymod\generated\*']
+normalize:
+  stage_order: true
+  texture_paths: true
+format:
+  indent: "\t"
+  compact_stages: true
+generate:
+  base_material: steel
+  finish: gloss
+  texture_overrides:
+    env: dz\data\data\env_co.paa
+lint:
+  exclude: ['**/legacy/**']
+  rules:
+    - rule: RVMAT2011
+      enabled: false
+overrides:
+  - files: ['vehicles/**/*.rvmat']
+    generate:
+      condition: worn
+    lint:
+      rules:
+        - rule: RVMAT2019
+          severity: error
+`
+
+const nestedProjectConfig = `normalize:
+  stage_order: false
+format:
+  compact_stages: false
+`
+
+func writeProjectConfig(t *testing.T, dir, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFileName), []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestLoadProjectSettingsMergesLayers(t *testing.T) {
+	root := t.TempDir()
+	writeProjectConfig(t, root, rootProjectConfig)
+	writeProjectConfig(t, filepath.Join(root, "vehicles", "car"), nestedProjectConfig)
+
+	settings, err := LoadProjectSettings(filepath.Join(root, "vehicles", "car", "body.rvmat"))
+	if err != nil {
+		t.Fatalf("LoadProjectSettings: %v", err)
+	}
+
+	if len(settings.Files) != 2 {
+		t.Fatalf("unexpected config files: %v", settings.Files)
+	}
+	if settings.Validate == nil || settings.Validate.GameRoot != `P:\` ||
+		settings.Validate.TexturePathMode != TexturePathModeTrust ||
+		!slices.Equal(settings.Validate.TrustedPrefixes, []string{`dz\`, `mymod\`}) {
+		t.Fatalf("unexpected validate section: %+v", settings.Validate)
+	}
+	if settings.Normalize == nil || settings.Normalize.StageOrder || !settings.Normalize.TexturePaths {
+		t.Fatalf("expected nested config to disable stage order only: %+v", settings.Normalize)
+	}
+	if settings.Format == nil || settings.Format.Indent != "\t" || settings.Format.CompactStages {
+		t.Fatalf("unexpected format section: %+v", settings.Format)
+	}
+	if settings.Generate == nil || settings.Generate.BaseMaterial != BaseMaterialSteel ||
+		settings.Generate.Finish != FinishGloss || settings.Generate.Condition != ConditionWorn {
+		t.Fatalf("unexpected generate section: %+v", settings.Generate)
+	}
+	if settings.Lint == nil || len(settings.Lint.Rules) != 2 {
+		t.Fatalf("expected lint rules from root and override: %+v", settings.Lint)
+	}
+
+	other, err := LoadProjectSettings(filepath.Join(root, "weapons", "gun.rvmat"))
+	if err != nil {
+		t.Fatalf("LoadProjectSettings: %v", err)
+	}
+	if other.Generate.Condition != ConditionDefault || len(other.Lint.Rules) != 1 {
+		t.Fatalf("override must not apply outside glob: %+v %+v", other.Generate, other.Lint)
+	}
+}
+
+func TestLoadProjectSettingsAnchorsRelativePaths(t *testing.T) {
+	root := t.TempDir()
+	writeProjectConfig(t, root, rootProjectConfig)
+	car := filepath.Join(root, "vehicles", "car")
+	writeProjectConfig(t, car, `validate:
+  game_root: ../../game
+overrides:
+  - files: ['*.rvmat']
+    generate:
+      output_path: out/body.rvmat
+`)
+
+	settings, err := LoadProjectSettings(filepath.Join(car, "body.rvmat"))
+	if err != nil {
+		t.Fatalf("LoadProjectSettings: %v", err)
+	}
+
+	if want := filepath.Join(root, "game"); settings.Validate.GameRoot != want {
+		t.Fatalf("expected game root %q, got %q", want, settings.Validate.GameRoot)
+	}
+	if want := filepath.Join(car, "out", "body.rvmat"); settings.Generate.OutputPath != want {
+		t.Fatalf("expected output path %q, got %q", want, settings.Generate.OutputPath)
+	}
+
+	outer, err := LoadProjectSettings(filepath.Join(root, "body.rvmat"))
+	if err != nil {
+		t.Fatalf("LoadProjectSettings: %v", err)
+	}
+	if outer.Validate.GameRoot != `P:\` {
+		t.Fatalf("absolute game root must be kept: %q", outer.Validate.GameRoot)
+	}
+}
+
+func TestOptionFieldsMatchOptions(t *testing.T) {
+	pairs := []struct {
+		fields  any
+		options any
+	}{
+		{ValidateFields{}, ValidateOptions{}},
+		{TextureValidateFields{}, TextureValidateOptions{}},
+		{NormalizeFields{}, NormalizeOptions{}},
+		{FormatFields{}, FormatOptions{}},
+		{GenerateSetFields{}, GenerateSetOptions{}},
+	}
+
+	for _, pair := range pairs {
+		fields, options := reflect.TypeOf(pair.fields), reflect.TypeOf(pair.options)
+		for i := range fields.NumField() {
+			field := fields.Field(i)
+			option, ok := options.FieldByName(field.Name)
+			if !ok || option.Tag.Get("yaml") != field.Tag.Get("yaml") {
+				t.Fatalf("%s.%s does not match %s field", fields.Name(), field.Name, options.Name())
+			}
+		}
+	}
+}
+
+func TestProjectSettingsMergeExplicitOptions(t *testing.T) {
+	cfg, err := ParseProjectConfig([]byte(rootProjectConfig))
+	if err != nil {
+		t.Fatalf("ParseProjectConfig: %v", err)
+	}
+
+	settings, err := cfg.Settings("vehicles/car/body.rvmat")
+	if err != nil {
+		t.Fatalf("Settings: %v", err)
+	}
+
+	vopt := settings.MergeValidateOptions(&ValidateOptions{GameRoot: `D:\work`}, nil)
+	if vopt.GameRoot != `D:\work` || vopt.TexturePathMode != TexturePathModeTrust {
+		t.Fatalf("unexpected merged validate options: %+v", vopt)
+	}
+
+	gopt := settings.MergeGenerateSetOptions(&GenerateSetOptions{
+		Finish:           FinishMatte,
+		TextureOverrides: map[string]string{"nohq": `mymod\a_nohq.paa`},
+	}, nil)
+	if gopt.BaseMaterial != BaseMaterialSteel || gopt.Finish != FinishMatte || len(gopt.TextureOverrides) != 2 {
+		t.Fatalf("unexpected merged generate options: %+v", gopt)
+	}
+	if len(settings.Generate.TextureOverrides) != 1 {
+		t.Fatalf("merge must not modify config map: %v", settings.Generate.TextureOverrides)
+	}
+
+	withTexGen := ProjectSettings{}
+	withTexGen.Generate = &GenerateSetOptions{DisableTexGen: true, EmissiveIntensity: 2}
+	if got := withTexGen.MergeGenerateSetOptions(&GenerateSetOptions{}, nil); !got.DisableTexGen {
+		t.Fatalf("zero explicit field must keep config value: %+v", got)
+	}
+	got := withTexGen.MergeGenerateSetOptions(&GenerateSetOptions{}, &GenerateSetFields{
+		DisableTexGen:     true,
+		EmissiveIntensity: true,
+	})
+	if got.DisableTexGen || got.EmissiveIntensity != 0 {
+		t.Fatalf("explicitly set zero fields must override config: %+v", got)
+	}
+
+	if got := (ProjectSettings{}).MergeFormatOptions(nil, nil); got != nil {
+		t.Fatalf("expected nil format options without config: %+v", got)
+	}
+}
+
+func TestProjectSettingsApplyLintPolicy(t *testing.T) {
+	cfg, err := ParseProjectConfig([]byte(rootProjectConfig))
+	if err != nil {
+		t.Fatalf("ParseProjectConfig: %v", err)
+	}
+
+	diagnostics := []lint.Diagnostic{
+		warningDiagnostic(CodeValidateUnknownStageName, "unknown stage", "StageX"),
+		warningDiagnostic(CodeValidateColorComponentCount, "bad color", "ambient"),
+	}
+
+	settings, err := cfg.Settings("vehicles/car/body.rvmat")
+	if err != nil {
+		t.Fatalf("Settings: %v", err)
+	}
+	got, err := settings.ApplyLintPolicy(slices.Clone(diagnostics))
+	if err != nil {
+		t.Fatalf("ApplyLintPolicy: %v", err)
+	}
+	if len(got) != 1 || got[0].Code != mustRuleCode(t, CodeValidateColorComponentCount) ||
+		got[0].Severity != lint.SeverityError {
+		t.Fatalf("unexpected filtered diagnostics: %+v", got)
+	}
+
+	settings, err = cfg.Settings("legacy/old/body.rvmat")
+	if err != nil {
+		t.Fatalf("Settings: %v", err)
+	}
+	got, err = settings.ApplyLintPolicy(slices.Clone(diagnostics))
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected excluded path to drop diagnostics: %+v, %v", got, err)
+	}
+}
+
+func TestParseProjectConfigErrors(t *testing.T) {
+	tests := []string{
+		"validate:\n  game_rot: P:\\\n",
+		"overrides:\n  - generate:\n      finish: gloss\n",
+		"generate:\n  finish: shiny\n",
+	}
+
+	for _, data := range tests {
+		if _, err := ParseProjectConfig([]byte(data)); !errors.Is(err, ErrInvalidProjectConfig) {
+			t.Fatalf("ParseProjectConfig(%q) error=%v, want ErrInvalidProjectConfig", data, err)
+		}
+	}
+
+	settings, err := (&ProjectConfig{}).Settings("a.rvmat")
+	if err != nil || settings.Validate != nil {
+		t.Fatalf("expected empty settings: %+v, %v", settings, err)
+	}
+}
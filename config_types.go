@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import "github.com/woozymasta/lintkit/linting"
+
+const (
+	// ProjectConfigFileName is default project config file name.
+	ProjectConfigFileName = ".rvmat.yaml"
+
+	// ProjectConfigAltFileName is alternative project config file name.
+	ProjectConfigAltFileName = ".rvmat.yml"
+)
+
+// ProjectConfigSections stores option sections shared by config root and overrides.
+//
+// Nil section means the file does not configure it.
+type ProjectConfigSections struct {
+	// Validate stores ValidateOptions defaults (game root, trusted prefixes, exclude paths).
+	Validate *ValidateOptions `json:"validate,omitempty" yaml:"validate,omitempty"`
+	// TextureValidate stores TextureValidateOptions defaults.
+	TextureValidate *TextureValidateOptions `json:"texture_validate,omitempty" yaml:"texture_validate,omitempty"`
+	// Normalize stores NormalizeOptions defaults.
+	Normalize *NormalizeOptions `json:"normalize,omitempty" yaml:"normalize,omitempty"`
+	// Format stores FormatOptions defaults (indent, compact stages).
+	Format *FormatOptions `json:"format,omitempty" yaml:"format,omitempty"`
+	// Generate stores GenerateSetOptions defaults.
+	Generate *GenerateSetOptions `json:"generate,omitempty" yaml:"generate,omitempty"`
+	// Lint stores lint policy: excluded paths, enabled rules and severities.
+	Lint *linting.RunPolicyConfig `json:"lint,omitempty" yaml:"lint,omitempty"`
+}
+
+// ProjectConfigOverride stores sections applied only to matching files.
+type ProjectConfigOverride struct {
+	// ProjectConfigSections stores override values; set keys replace inherited ones.
+	ProjectConfigSections `json:",inline" yaml:",inline"`
+	// Files lists gitignore-style globs relative to config file directory.
+	Files []string `json:"files" yaml:"files"`
+}
+
+// ProjectConfig is one parsed project config file (for example .rvmat.yaml).
+type ProjectConfig struct {
+	// raw stores decoded top-level sections used for key-level merging.
+	raw map[string]any
+	// ProjectConfigSections stores file-wide option sections.
+	ProjectConfigSections `json:",inline" yaml:",inline"`
+	// Path is config file path; its directory anchors override globs.
+	Path string `json:"-" yaml:"-"`
+	// Overrides stores glob-scoped sections applied in order.
+	Overrides []ProjectConfigOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+	// rawOverrides stores decoded override sections used for key-level merging.
+	rawOverrides []map[string]any
+	// Root stops upward config discovery at this file.
+	Root bool `json:"root,omitempty" yaml:"root,omitempty"`
+}
+
+// ProjectSettings stores effective config sections for one target file.
+//
+// Nil section means no config file set it; merge helpers then return
+// explicit options unchanged.
+type ProjectSettings struct {
+	// ProjectConfigSections stores merged option sections.
+	ProjectConfigSections `json:",inline" yaml:",inline"`
+	// target stores slash path of target file relative to outermost config directory.
+	target string
+	// Files lists config files merged into settings, outermost first.
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// ValidateFields marks ValidateOptions fields given explicitly, so their
+// zero values (false, default mode) override config values.
+type ValidateFields struct {
+	// TexturePathMode marks ValidateOptions.TexturePathMode.
+	TexturePathMode bool `json:"texture_path_mode,omitempty" yaml:"texture_path_mode,omitempty"`
+	// DisableExtensionsCheck marks ValidateOptions.DisableExtensionsCheck.
+	DisableExtensionsCheck bool `json:"disable_extensions_check,omitempty" yaml:"disable_extensions_check,omitempty"`
+	// DisableShaderNameCheck marks ValidateOptions.DisableShaderNameCheck.
+	DisableShaderNameCheck bool `json:"disable_shader_name_check,omitempty" yaml:"disable_shader_name_check,omitempty"`
+	// EnableShaderProfileCheck marks ValidateOptions.EnableShaderProfileCheck.
+	EnableShaderProfileCheck bool `json:"enable_shader_profile_check,omitempty" yaml:"enable_shader_profile_check,omitempty"`
+	// EnableShaderCaseCheck marks ValidateOptions.EnableShaderCaseCheck.
+	EnableShaderCaseCheck bool `json:"enable_shader_case_check,omitempty" yaml:"enable_shader_case_check,omitempty"`
+}
+
+// TextureValidateFields marks TextureValidateOptions fields given explicitly.
+type TextureValidateFields struct {
+	// DisableProceduralFnCheck marks TextureValidateOptions.DisableProceduralFnCheck.
+	DisableProceduralFnCheck bool `json:"disable_procedural_fn_check,omitempty" yaml:"disable_procedural_fn_check,omitempty"`
+	// DisableProceduralArgsCheck marks TextureValidateOptions.DisableProceduralArgsCheck.
+	DisableProceduralArgsCheck bool `json:"disable_procedural_args_check,omitempty" yaml:"disable_procedural_args_check,omitempty"`
+	// DisableTextureTagCheck marks TextureValidateOptions.DisableTextureTagCheck.
+	DisableTextureTagCheck bool `json:"disable_texture_tag_check,omitempty" yaml:"disable_texture_tag_check,omitempty"`
+}
+
+// NormalizeFields marks NormalizeOptions fields given explicitly.
+type NormalizeFields struct {
+	// StageTextures marks NormalizeOptions.StageTextures.
+	StageTextures bool `json:"stage_textures,omitempty" yaml:"stage_textures,omitempty"`
+	// StageOrder marks NormalizeOptions.StageOrder.
+	StageOrder bool `json:"stage_order,omitempty" yaml:"stage_order,omitempty"`
+	// TexGenOrder marks NormalizeOptions.TexGenOrder.
+	TexGenOrder bool `json:"texgen_order,omitempty" yaml:"texgen_order,omitempty"`
+	// TexturePaths marks NormalizeOptions.TexturePaths.
+	TexturePaths bool `json:"texture_paths,omitempty" yaml:"texture_paths,omitempty"`
+	// ExtractTexGens marks NormalizeOptions.ExtractTexGens.
+	ExtractTexGens bool `json:"extract_texgens,omitempty" yaml:"extract_texgens,omitempty"`
+	// InlineTexGens marks NormalizeOptions.InlineTexGens.
+	InlineTexGens bool `json:"inline_texgens,omitempty" yaml:"inline_texgens,omitempty"`
+}
+
+// FormatFields marks FormatOptions fields given explicitly.
+type FormatFields struct {
+	// Precision marks FormatOptions.Precision.
+	Precision bool `json:"precision,omitempty" yaml:"precision,omitempty"`
+	// CompactStages marks FormatOptions.CompactStages.
+	CompactStages bool `json:"compact_stages,omitempty" yaml:"compact_stages,omitempty"`
+}
+
+// GenerateSetFields marks GenerateSetOptions fields given explicitly.
+type GenerateSetFields struct {
+	// EmissiveIntensity marks GenerateSetOptions.EmissiveIntensity.
+	EmissiveIntensity bool `json:"emissive_intensity,omitempty" yaml:"emissive_intensity,omitempty"`
+	// SynthesizeNormalStrength marks GenerateSetOptions.SynthesizeNormalStrength.
+	SynthesizeNormalStrength bool `json:"synthesize_normal_strength,omitempty" yaml:"synthesize_normal_strength,omitempty"`
+	// BaseMaterial marks GenerateSetOptions.BaseMaterial.
+	BaseMaterial bool `json:"base_material,omitempty" yaml:"base_material,omitempty"`
+	// Condition marks GenerateSetOptions.Condition.
+	Condition bool `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// Finish marks GenerateSetOptions.Finish.
+	Finish bool `json:"finish,omitempty" yaml:"finish,omitempty"`
+	// TextureAutoFillMode marks GenerateSetOptions.TextureAutoFillMode.
+	TextureAutoFillMode bool `json:"auto_fill_mode,omitempty" yaml:"auto_fill_mode,omitempty"`
+	// ForceProceduralOnly marks GenerateSetOptions.ForceProceduralOnly.
+	ForceProceduralOnly bool `json:"force_procedural_only,omitempty" yaml:"force_procedural_only,omitempty"`
+	// GenerateDamage marks GenerateSetOptions.GenerateDamage.
+	GenerateDamage bool `json:"generate_damage,omitempty" yaml:"generate_damage,omitempty"`
+	// GenerateDestruct marks GenerateSetOptions.GenerateDestruct.
+	GenerateDestruct bool `json:"generate_destruct,omitempty" yaml:"generate_destruct,omitempty"`
+	// DisableDamage marks GenerateSetOptions.DisableDamage.
+	DisableDamage bool `json:"disable_damage,omitempty" yaml:"disable_damage,omitempty"`
+	// DisableDestruct marks GenerateSetOptions.DisableDestruct.
+	DisableDestruct bool `json:"disable_destruct,omitempty" yaml:"disable_destruct,omitempty"`
+	// DisableTexGen marks GenerateSetOptions.DisableTexGen.
+	DisableTexGen bool `json:"disable_texgen,omitempty" yaml:"disable_texgen,omitempty"`
+	// SynthesizeTextures marks GenerateSetOptions.SynthesizeTextures.
+	SynthesizeTextures bool `json:"synthesize_textures,omitempty" yaml:"synthesize_textures,omitempty"`
+}
@@ -219,4 +219,95 @@ var diagnosticCatalog = []lint.CodeSpec{
 		),
 		"Use known engine tag prefix or absolute project-relative path.",
 	),
+	withDescription(
+		lint.InfoCodeSpec(
+			CodeValidatePixelShaderIDCase,
+			StageValidate,
+			"pixel shader ID casing differs from canonical name",
+		),
+		"Engine matching is case-insensitive; canonical casing keeps materials "+
+			"consistent. `ApplyFixes` can rewrite the value.",
+	),
+	withDescription(
+		lint.InfoCodeSpec(
+			CodeValidateVertexShaderIDCase,
+			StageValidate,
+			"vertex shader ID casing differs from canonical name",
+		),
+		"Engine matching is case-insensitive; canonical casing keeps materials "+
+			"consistent. `ApplyFixes` can rewrite the value.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateUnusedSuppression,
+			StageValidate,
+			"unused rvmat-ignore suppression",
+		),
+		"Inline `rvmat-ignore` or `rvmat-ignore-file` comment did not suppress "+
+			"any diagnostic. Remove it or fix the listed code.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateUnresolvedNumericExpression,
+			StageValidate,
+			"numeric expression could not be evaluated",
+		),
+		"Expression or macro token in numeric field was parsed as 0. Parse with "+
+			"`ParseOptions.EvalExpressions` and `ExpressionConstants`, or replace it "+
+			"with a number.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateSurfaceFileNotFound,
+			StageValidate,
+			"surface file not found",
+		),
+		"Material `surfaceInfo` points at .bisurf file that does not exist under game "+
+			"root. Checked with texture file checks enabled.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateInvalidSurfaceFile,
+			StageValidate,
+			"surface file is malformed",
+		),
+		"Referenced .bisurf file cannot be parsed, misses density, or has "+
+			"negative values or rough, dust, or transparency above 1.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateSurfaceMaterialMismatch,
+			StageValidate,
+			"surface does not match material",
+		),
+		"Rigid material such as glass, metal, concrete, or stone references "+
+			"soft-ground surface (grass, dirt, sand, mud). Check `surfaceInfo`.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateModelMaterialUnused,
+			StageValidate,
+			"material not used by model",
+		),
+		"No face of checked P3D model references generated material. Check "+
+			"output path against material paths stored in the model.",
+	),
+	withDescription(
+		lint.WarningCodeSpec(
+			CodeValidateModelVariantSelectionMissing,
+			StageValidate,
+			"damage variant has no model selection",
+		),
+		"Damage or destruct material was generated, but no named selection "+
+			"(destruct: `zbytek`) contains faces using the main material.",
+	),
+	withDescription(
+		lint.InfoCodeSpec(
+			CodeValidateModelSelectionsUnavailable,
+			StageValidate,
+			"model has no selection data",
+		),
+		"Binarized ODOL model carries no parsed LOD or named selection data, "+
+			"so damage and destruct variants were not checked. Use MLOD source.",
+	),
 }
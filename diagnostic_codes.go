@@ -110,6 +110,36 @@ const (
 
 	// CodeValidateUnknownTextureTag reports unknown texture tag.
 	CodeValidateUnknownTextureTag lint.Code = 2028
+
+	// CodeValidatePixelShaderIDCase reports non-canonical pixel shader id casing.
+	CodeValidatePixelShaderIDCase lint.Code = 2029
+
+	// CodeValidateVertexShaderIDCase reports non-canonical vertex shader id casing.
+	CodeValidateVertexShaderIDCase lint.Code = 2030
+
+	// CodeValidateUnusedSuppression reports rvmat-ignore directive that matched nothing.
+	CodeValidateUnusedSuppression lint.Code = 2031
+
+	// CodeValidateUnresolvedNumericExpression reports numeric token parsed as 0.
+	CodeValidateUnresolvedNumericExpression lint.Code = 2032
+
+	// CodeValidateSurfaceFileNotFound reports missing surfaceInfo file.
+	CodeValidateSurfaceFileNotFound lint.Code = 2033
+
+	// CodeValidateInvalidSurfaceFile reports malformed surfaceInfo file.
+	CodeValidateInvalidSurfaceFile lint.Code = 2034
+
+	// CodeValidateSurfaceMaterialMismatch reports suspicious material and surface pairing.
+	CodeValidateSurfaceMaterialMismatch lint.Code = 2035
+
+	// CodeValidateModelMaterialUnused reports generated material without model faces.
+	CodeValidateModelMaterialUnused lint.Code = 2036
+
+	// CodeValidateModelVariantSelectionMissing reports damage variant without model selection.
+	CodeValidateModelVariantSelectionMissing lint.Code = 2037
+
+	// CodeValidateModelSelectionsUnavailable reports variant check skipped for binarized model.
+	CodeValidateModelSelectionsUnavailable lint.Code = 2038
 )
 
 var diagnosticCodeCatalogConfig = lint.CodeCatalogConfig{
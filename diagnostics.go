@@ -24,6 +24,15 @@ func errorDiagnostic(
 	return diagnosticWithSeverity(code, lint.SeverityError, message, path)
 }
 
+// infoDiagnostic builds one info-level lint diagnostic.
+func infoDiagnostic(
+	code lint.Code,
+	message string,
+	path string,
+) lint.Diagnostic {
+	return diagnosticWithSeverity(code, lint.SeverityInfo, message, path)
+}
+
 // diagnosticWithSeverity builds one diagnostic from catalog code and payload.
 func diagnosticWithSeverity(
 	code lint.Code,
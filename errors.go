@@ -41,6 +41,42 @@ var (
 	// ErrStageNotFound indicates required stage is missing.
 	ErrStageNotFound = errors.New("stage not found")
 
+	// ErrInvalidProjectConfig indicates malformed project config file.
+	ErrInvalidProjectConfig = errors.New("invalid project config")
+
+	// ErrInvalidPBO indicates malformed PBO archive or entry data.
+	ErrInvalidPBO = errors.New("invalid pbo")
+
+	// ErrUnsupportedProcedural indicates procedural texture that cannot be rasterized.
+	ErrUnsupportedProcedural = errors.New("unsupported procedural texture")
+
+	// ErrInvalidTextureImage indicates malformed or unsupported texture image data.
+	ErrInvalidTextureImage = errors.New("invalid texture image")
+
+	// ErrInvalidPreviewOption indicates invalid value in PreviewOptions.
+	ErrInvalidPreviewOption = errors.New("invalid preview option")
+
+	// ErrInvalidGLTF indicates malformed or unsupported glTF document.
+	ErrInvalidGLTF = errors.New("invalid gltf")
+
+	// ErrInvalidManifest indicates malformed generate manifest.
+	ErrInvalidManifest = errors.New("invalid generate manifest")
+
+	// ErrOutputCollision indicates several generated files share one output path.
+	ErrOutputCollision = errors.New("output path collision")
+
+	// ErrInvalidExpression indicates config expression that cannot be evaluated.
+	ErrInvalidExpression = errors.New("invalid expression")
+
+	// ErrInvalidMaterialProfile indicates malformed or conflicting custom material profile.
+	ErrInvalidMaterialProfile = errors.New("invalid material profile")
+
+	// ErrInvalidAddonConfig indicates malformed config.cpp or rapified config data.
+	ErrInvalidAddonConfig = errors.New("invalid addon config")
+
+	// ErrInvalidP3D indicates malformed or unsupported P3D model data.
+	ErrInvalidP3D = errors.New("invalid p3d")
+
 	// ErrNilLintRuleRegistrar indicates nil lint rule registrar in registration.
 	ErrNilLintRuleRegistrar = lint.ErrNilRuleRegistrar
 )
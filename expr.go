@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// engineExpressionConstants lists constants known to engine config evaluator.
+var engineExpressionConstants = map[string]float64{
+	"pi":    math.Pi,
+	"true":  1,
+	"false": 0,
+}
+
+// EvalExpression evaluates config arithmetic expression such as "0.5*2",
+// "-(1+pi)/4", or "__EVAL(2^3)".
+//
+// Supported are numbers, parentheses, unary +/-, binary + - * / % and
+// right-associative ^, engine constants (pi, true, false), and names from
+// constants (case-insensitive, e.g. #define macros). Nothing else is
+// executed. Division by zero and non-finite results are errors.
+func EvalExpression(expr string, constants map[string]float64) (float64, error) {
+	src := strings.TrimSpace(expr)
+	if inner, ok := cutEvalWrapper(src); ok {
+		src = inner
+	}
+
+	e := &exprEvaluator{src: src, constants: constants}
+	v, err := e.parseSum()
+	if err == nil {
+		e.skipSpace()
+		if e.pos < len(e.src) {
+			err = fmt.Errorf("unexpected %q at %d", e.src[e.pos], e.pos)
+		}
+	}
+	if err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		err = fmt.Errorf("non-finite result %g", v)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %w", ErrInvalidExpression, expr, err)
+	}
+
+	return v, nil
+}
+
+// cutEvalWrapper strips __EVAL(...) wrapper.
+func cutEvalWrapper(s string) (string, bool) {
+	const prefix = "__eval"
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+
+	rest := strings.TrimSpace(s[len(prefix):])
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return s, false
+	}
+
+	return rest[1 : len(rest)-1], true
+}
+
+// exprEvaluator is recursive-descent evaluator over expression text.
+type exprEvaluator struct {
+	constants map[string]float64 // Caller constants
+	src       string             // Expression text
+	pos       int                // Current byte offset
+	depth     int                // Current nesting depth
+}
+
+// maxExpressionDepth limits parentheses and unary nesting.
+const maxExpressionDepth = 64
+
+// skipSpace advances over whitespace.
+func (e *exprEvaluator) skipSpace() {
+	for e.pos < len(e.src) && (e.src[e.pos] == ' ' || e.src[e.pos] == '\t') {
+		e.pos++
+	}
+}
+
+// accept consumes op when it is next non-space byte.
+func (e *exprEvaluator) accept(op byte) bool {
+	e.skipSpace()
+	if e.pos < len(e.src) && e.src[e.pos] == op {
+		e.pos++
+		return true
+	}
+
+	return false
+}
+
+// parseSum parses + and - terms.
+func (e *exprEvaluator) parseSum() (float64, error) {
+	v, err := e.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		switch {
+		case e.accept('+'):
+			r, err := e.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			v += r
+		case e.accept('-'):
+			r, err := e.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			v -= r
+		default:
+			return v, nil
+		}
+	}
+}
+
+// parseProduct parses *, / and % factors.
+func (e *exprEvaluator) parseProduct() (float64, error) {
+	v, err := e.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		var op byte
+		switch {
+		case e.accept('*'):
+			op = '*'
+		case e.accept('/'):
+			op = '/'
+		case e.accept('%'):
+			op = '%'
+		default:
+			return v, nil
+		}
+
+		r, err := e.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		if op != '*' && r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+
+		switch op {
+		case '*':
+			v *= r
+		case '/':
+			v /= r
+		default:
+			v = math.Mod(v, r)
+		}
+	}
+}
+
+// parseUnary parses unary +/- prefix.
+func (e *exprEvaluator) parseUnary() (float64, error) {
+	if e.depth++; e.depth > maxExpressionDepth {
+		return 0, fmt.Errorf("expression nested too deep")
+	}
+	defer func() { e.depth-- }()
+
+	switch {
+	case e.accept('-'):
+		v, err := e.parseUnary()
+		return -v, err
+	case e.accept('+'):
+		return e.parseUnary()
+	default:
+		return e.parsePower()
+	}
+}
+
+// parsePower parses right-associative ^ exponent.
+func (e *exprEvaluator) parsePower() (float64, error) {
+	v, err := e.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if !e.accept('^') {
+		return v, nil
+	}
+
+	exp, err := e.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Pow(v, exp), nil
+}
+
+// parsePrimary parses number, name, or parenthesized expression.
+func (e *exprEvaluator) parsePrimary() (float64, error) {
+	if e.accept('(') {
+		v, err := e.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if !e.accept(')') {
+			return 0, fmt.Errorf("missing ')' at %d", e.pos)
+		}
+		return v, nil
+	}
+
+	e.skipSpace()
+	start := e.pos
+	if e.pos < len(e.src) && (isDigit(e.src[e.pos]) || e.src[e.pos] == '.') {
+		for e.pos < len(e.src) && (isDigit(e.src[e.pos]) || e.src[e.pos] == '.') {
+			e.pos++
+		}
+		// Exponent part: 1e-3, 2E+4.
+		if e.pos < len(e.src) && (e.src[e.pos] == 'e' || e.src[e.pos] == 'E') {
+			next := e.pos + 1
+			if next < len(e.src) && (e.src[next] == '+' || e.src[next] == '-') {
+				next++
+			}
+			if next < len(e.src) && isDigit(e.src[next]) {
+				e.pos = next
+				for e.pos < len(e.src) && isDigit(e.src[e.pos]) {
+					e.pos++
+				}
+			}
+		}
+
+		v, err := strconv.ParseFloat(e.src[start:e.pos], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", e.src[start:e.pos])
+		}
+		return v, nil
+	}
+
+	for e.pos < len(e.src) && isExprNameByte(e.src[e.pos], e.pos == start) {
+		e.pos++
+	}
+	if start == e.pos {
+		if e.pos >= len(e.src) {
+			return 0, fmt.Errorf("unexpected end of expression")
+		}
+		return 0, fmt.Errorf("unexpected %q at %d", e.src[e.pos], e.pos)
+	}
+
+	name := e.src[start:e.pos]
+	for key, v := range e.constants {
+		if strings.EqualFold(key, name) {
+			return v, nil
+		}
+	}
+	if v, ok := engineExpressionConstants[strings.ToLower(name)]; ok {
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("unknown name %q", name)
+}
+
+// isDigit reports ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isExprNameByte reports identifier byte; digits are not allowed first.
+func isExprNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && isDigit(c))
+}
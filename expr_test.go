@@ -0,0 +1,120 @@
+package rvmat
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEvalExpression(t *testing.T) {
+	constants := map[string]float64{"MACRO_W": 0.25}
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{expr: "0.5*2", want: 1},
+		{expr: "1 + 2 * 3", want: 7},
+		{expr: "(1 + 2) * 3", want: 9},
+		{expr: "-(1+1)/4", want: -0.5},
+		{expr: "--2", want: 2},
+		{expr: "2^3^2", want: 512},
+		{expr: "-2^2", want: -4},
+		{expr: "7 % 4", want: 3},
+		{expr: "1e-1*10", want: 1},
+		{expr: "PI/pi", want: 1},
+		{expr: "true + false", want: 1},
+		{expr: "macro_w*4", want: 1},
+		{expr: "__EVAL(0.5 + 0.25)", want: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalExpression(tt.expr, constants)
+			if err != nil {
+				t.Fatalf("eval: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Fatalf("got %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 / 0",
+		"1 % 0",
+		"unknown * 2",
+		"1.2.3",
+		"2 3",
+		"exec(1)",
+		strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100),
+		"10^400",
+	} {
+		if _, err := EvalExpression(expr, nil); !errors.Is(err, ErrInvalidExpression) {
+			t.Fatalf("%q: expected ErrInvalidExpression, got %v", expr, err)
+		}
+	}
+}
+
+func TestParseEvalExpressions(t *testing.T) {
+	input := `ambient[]={"0.5*2", MACRO_W, "1/0", 1};
+specularPower="__EVAL(25*2)";
+PixelShaderID="Super";
+VertexShaderID="Super";
+`
+	opt := &ParseOptions{
+		EvalExpressions:     true,
+		ExpressionConstants: map[string]float64{"MACRO_W": 0.5},
+	}
+	m, err := Parse([]byte(input), opt)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if want := []float64{1, 0.5, 0, 1}; !slices.Equal(m.Ambient, want) {
+		t.Fatalf("ambient %v, want %v", m.Ambient, want)
+	}
+	if m.SpecularPower == nil || *m.SpecularPower != 50 {
+		t.Fatalf("expected specularPower 50, got %v", m.SpecularPower)
+	}
+
+	// Unresolved tokens stay visible instead of silently zeroed.
+	var found []string
+	for _, d := range Validate(m, nil) {
+		if d.Code == mustRuleCode(t, CodeValidateUnresolvedNumericExpression) {
+			found = append(found, d.Message)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected one unresolved expression diagnostic, got %v", found)
+	}
+
+	// Source text is still written back verbatim.
+	out, err := Format(m, nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if !strings.HasPrefix(string(out), input[:len(`ambient[]={"0.5*2", MACRO_W, "1/0", 1};`)]) {
+		t.Fatalf("expressions not preserved:\n%s", out)
+	}
+
+	if _, err := Parse([]byte(`specularPower="2*2";`), nil); err == nil {
+		t.Fatalf("expected specularPower expression error without EvalExpressions")
+	}
+
+	// Relaxed tokens zeroed without EvalExpressions were never evaluated.
+	relaxed, err := Parse([]byte(`emmisive[]={0,0,0,FOO};`), nil)
+	if err != nil {
+		t.Fatalf("parse relaxed: %v", err)
+	}
+	for _, d := range Validate(relaxed, nil) {
+		if d.Code == mustRuleCode(t, CodeValidateUnresolvedNumericExpression) {
+			t.Fatalf("unexpected unresolved expression diagnostic without EvalExpressions: %+v", d)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSystem is read-only file access used for texture lookups and discovery.
+//
+// Names are OS-style paths as produced by PathResolver (absolute or relative,
+// with either separator). Implementations report missing files with errors
+// matching fs.ErrNotExist.
+type FileSystem interface {
+	// Stat returns file info for name.
+	Stat(name string) (fs.FileInfo, error)
+	// ReadDir returns directory entries of name.
+	ReadDir(name string) ([]fs.DirEntry, error)
+	// ReadFile returns file contents of name.
+	ReadFile(name string) ([]byte, error)
+}
+
+// WritableFileSystem is FileSystem with write access used to store outputs.
+type WritableFileSystem interface {
+	FileSystem
+	// MkdirAll creates directory name with parents.
+	MkdirAll(name string, perm fs.FileMode) error
+	// WriteFile writes data to name, creating or truncating it.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// OSFileSystem is FileSystem backed by the host operating system.
+type OSFileSystem struct{}
+
+// Stat returns file info for name.
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// ReadDir returns directory entries of name.
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// ReadFile returns file contents of name.
+func (OSFileSystem) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// MkdirAll creates directory name with parents.
+func (OSFileSystem) MkdirAll(name string, perm fs.FileMode) error { return os.MkdirAll(name, perm) }
+
+// WriteFile writes data to name, creating or truncating it.
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// ioFileSystem adapts io/fs.FS to FileSystem.
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+// NewIOFileSystem adapts io/fs.FS (embed.FS, fstest.MapFS, zip.Reader) to FileSystem.
+//
+// OS-style names are converted to fs.FS form: separators become '/',
+// leading '/' and volume names are dropped, and ".." cannot climb above root.
+func NewIOFileSystem(fsys fs.FS) FileSystem {
+	return ioFileSystem{fsys: fsys}
+}
+
+// Stat returns file info for name.
+func (f ioFileSystem) Stat(name string) (fs.FileInfo, error) {
+	key, err := ioFileSystemName("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Stat(f.fsys, key)
+}
+
+// ReadDir returns directory entries of name.
+func (f ioFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	key, err := ioFileSystemName("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.ReadDir(f.fsys, key)
+}
+
+// ReadFile returns file contents of name.
+func (f ioFileSystem) ReadFile(name string) ([]byte, error) {
+	key, err := ioFileSystemName("read", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.ReadFile(f.fsys, key)
+}
+
+// ioFileSystemName converts OS-style name to valid fs.FS name.
+func ioFileSystemName(op, name string) (string, error) {
+	key := virtualPath(name)
+	if !fs.ValidPath(key) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return key, nil
+}
+
+// MemFileSystem is in-memory WritableFileSystem for tests and staged outputs.
+//
+// Names are case-sensitive; separators, leading '/' and volume names are
+// normalized like NewIOFileSystem, so `P:\dz\a.paa` and "dz/a.paa" are the
+// same file. Safe for concurrent use.
+type MemFileSystem struct {
+	files map[string]memFile
+	dirs  map[string]time.Time
+	mu    sync.RWMutex
+}
+
+// memFile stores one in-memory file.
+type memFile struct {
+	modTime time.Time
+	data    []byte
+	mode    fs.FileMode
+}
+
+// memFileInfo implements fs.FileInfo and fs.DirEntry for MemFileSystem.
+type memFileInfo struct {
+	modTime time.Time
+	name    string
+	size    int64
+	mode    fs.FileMode
+}
+
+// NewMemFileSystem returns empty in-memory filesystem.
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{
+		files: make(map[string]memFile),
+		dirs:  map[string]time.Time{".": {}},
+	}
+}
+
+// AddFile stores file data and creates parent directories.
+func (m *MemFileSystem) AddFile(name string, data []byte) {
+	_ = m.WriteFile(name, data, 0o600)
+}
+
+// Files returns sorted normalized names of stored files.
+func (m *MemFileSystem) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]string, 0, len(m.files))
+	for name := range m.files {
+		out = append(out, name)
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+// Stat returns file info for name.
+func (m *MemFileSystem) Stat(name string) (fs.FileInfo, error) {
+	key := virtualPath(name)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if file, ok := m.files[key]; ok {
+		return memFileInfo{name: path.Base(key), size: int64(len(file.data)), mode: file.mode, modTime: file.modTime}, nil
+	}
+	if modTime, ok := m.dirs[key]; ok {
+		return memFileInfo{name: path.Base(key), mode: fs.ModeDir | 0o750, modTime: modTime}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns directory entries of name sorted by file name.
+func (m *MemFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	key := virtualPath(name)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.dirs[key]; !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var out []fs.DirEntry
+	for child, file := range m.files {
+		if path.Dir(child) == key {
+			out = append(out, memFileInfo{name: path.Base(child), size: int64(len(file.data)), mode: file.mode, modTime: file.modTime})
+		}
+	}
+	for child, modTime := range m.dirs {
+		if child != "." && path.Dir(child) == key {
+			out = append(out, memFileInfo{name: path.Base(child), mode: fs.ModeDir | 0o750, modTime: modTime})
+		}
+	}
+	slices.SortFunc(out, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return out, nil
+}
+
+// ReadFile returns copy of file contents of name.
+func (m *MemFileSystem) ReadFile(name string) ([]byte, error) {
+	key := virtualPath(name)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, ok := m.files[key]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return bytes.Clone(file.data), nil
+}
+
+// MkdirAll creates directory name with parents.
+func (m *MemFileSystem) MkdirAll(name string, _ fs.FileMode) error {
+	key := virtualPath(name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.mkdirAllLocked(name, key)
+}
+
+// WriteFile writes copy of data to name, creating parent directories.
+func (m *MemFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	key := virtualPath(name)
+	if key == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.dirs[key]; ok {
+		return &fs.PathError{Op: "write", Path: name, Err: errors.New("is a directory")}
+	}
+	if err := m.mkdirAllLocked(name, path.Dir(key)); err != nil {
+		return err
+	}
+
+	m.files[key] = memFile{data: bytes.Clone(data), mode: perm, modTime: time.Now()}
+	return nil
+}
+
+// mkdirAllLocked creates normalized directory key with parents.
+func (m *MemFileSystem) mkdirAllLocked(name, key string) error {
+	for dir := key; dir != "."; dir = path.Dir(dir) {
+		if _, ok := m.files[dir]; ok {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: errors.New("not a directory")}
+		}
+		if _, ok := m.dirs[dir]; ok {
+			break
+		}
+
+		m.dirs[dir] = time.Now()
+	}
+
+	return nil
+}
+
+// Name returns base name.
+func (i memFileInfo) Name() string { return i.name }
+
+// Size returns file size in bytes.
+func (i memFileInfo) Size() int64 { return i.size }
+
+// Mode returns file mode bits.
+func (i memFileInfo) Mode() fs.FileMode { return i.mode }
+
+// ModTime returns modification time.
+func (i memFileInfo) ModTime() time.Time { return i.modTime }
+
+// IsDir reports whether entry is a directory.
+func (i memFileInfo) IsDir() bool { return i.mode.IsDir() }
+
+// Sys returns nil.
+func (i memFileInfo) Sys() any { return nil }
+
+// Type returns file type bits.
+func (i memFileInfo) Type() fs.FileMode { return i.mode.Type() }
+
+// Info returns file info.
+func (i memFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+// virtualPath converts OS-style name to cleaned unrooted slash path.
+func virtualPath(name string) string {
+	name = strings.ReplaceAll(strings.TrimSpace(name), `\`, "/")
+	if hasVolume(name) {
+		name = name[2:]
+	}
+
+	name = path.Clean("/" + name)
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// fileSystemOrOS returns fsys or OS filesystem when nil.
+func fileSystemOrOS(fsys FileSystem) FileSystem {
+	if fsys == nil {
+		return OSFileSystem{}
+	}
+
+	return fsys
+}
+
+// writableFileSystemOrOS returns fsys or OS filesystem when nil.
+func writableFileSystemOrOS(fsys WritableFileSystem) WritableFileSystem {
+	if fsys == nil {
+		return OSFileSystem{}
+	}
+
+	return fsys
+}
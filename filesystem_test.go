@@ -0,0 +1,94 @@
+package rvmat
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFileSystem(t *testing.T) {
+	fsys := NewMemFileSystem()
+	fsys.AddFile(`P:\mod\data\box_co.paa`, []byte("paa"))
+
+	info, err := fsys.Stat("mod/data/box_co.paa")
+	if err != nil || info.IsDir() || info.Size() != 3 {
+		t.Fatalf("unexpected stat: %+v, %v", info, err)
+	}
+	if info, err := fsys.Stat(`/mod\data`); err != nil || !info.IsDir() {
+		t.Fatalf("expected parent directory: %+v, %v", info, err)
+	}
+	if _, err := fsys.Stat("mod/data/box_nohq.paa"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	entries, err := fsys.ReadDir("mod")
+	if err != nil || len(entries) != 1 || entries[0].Name() != "data" || !entries[0].IsDir() {
+		t.Fatalf("unexpected entries: %v, %v", entries, err)
+	}
+
+	if err := fsys.WriteFile("mod", nil, 0o600); err == nil {
+		t.Fatal("expected error writing over directory")
+	}
+	if err := fsys.MkdirAll("mod/data/box_co.paa/sub", 0o750); err == nil {
+		t.Fatal("expected error creating directory under file")
+	}
+}
+
+func TestValidateWithMemFileSystem(t *testing.T) {
+	fsys := NewMemFileSystem()
+	fsys.AddFile(`P:\mod\data\box_nohq.paa`, nil)
+
+	mat := &Material{Stages: []Stage{
+		{Name: "Stage1", Texture: ParseTextureRef(`mod\data\box_nohq.paa`)},
+		{Name: "Stage2", Texture: ParseTextureRef(`mod\data\box_dt.paa`)},
+	}}
+	opt := &ValidateOptions{
+		GameRoot:        `P:\`,
+		TexturePathMode: TexturePathModeStrict,
+		FileSystem:      fsys,
+	}
+	if !opt.IsGameRootExist() {
+		t.Fatal("expected virtual game root to exist")
+	}
+
+	var missing []string
+	for _, issue := range Validate(mat, opt) {
+		if issue.Code == mustRuleCode(t, CodeValidateTextureFileNotFound) {
+			missing = append(missing, issue.Path)
+		}
+	}
+	if len(missing) != 1 {
+		t.Fatalf("unexpected missing textures: %v", missing)
+	}
+}
+
+func TestGenerateSetWithFileSystem(t *testing.T) {
+	source := NewIOFileSystem(fstest.MapFS{
+		"mod/data/crate_co.paa":   {},
+		"mod/data/crate_nohq.paa": {},
+	})
+
+	result, err := GenerateSet(GenerateSetOptions{
+		OutputPath: "mod/data/crate",
+		FileSystem: source,
+	})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	if result.StageResolutions["Stage1"].Source != StageTextureSourceAutoFill {
+		t.Fatalf("expected Stage1 auto-fill from virtual filesystem: %+v", result.StageResolutions["Stage1"])
+	}
+
+	out := NewMemFileSystem()
+	if err := WriteGenerateSetFS(out, result, nil); err != nil {
+		t.Fatalf("WriteGenerateSetFS: %v", err)
+	}
+	data, err := out.ReadFile("mod/data/crate.rvmat")
+	if err != nil || len(data) == 0 {
+		t.Fatalf("expected written main material: %d bytes, %v", len(data), err)
+	}
+	if len(out.Files()) != 3 {
+		t.Fatalf("unexpected written files: %v", out.Files())
+	}
+}
@@ -0,0 +1,673 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+// fixClaimKey marks stage index already matched to diagnostic code.
+type fixClaimKey struct {
+	code  lint.Code
+	index int
+}
+
+// ValidateWithFixes validates material and returns diagnostics with fix
+// suggestions collected through ValidateOptions.OnFix.
+func ValidateWithFixes(m *Material, opt *ValidateOptions) ([]lint.Diagnostic, []Fix) {
+	var vopt ValidateOptions
+	if opt != nil {
+		vopt = *opt
+	}
+
+	var fixes []Fix
+	vopt.OnFix = func(fix Fix) {
+		if opt != nil && opt.OnFix != nil {
+			opt.OnFix(fix)
+		}
+		fixes = append(fixes, fix)
+	}
+
+	return Validate(m, &vopt), fixes
+}
+
+// SuggestFixes returns machine-applicable fixes for validation diagnostics.
+//
+// Diagnostics without an obvious fix are skipped. Sibling texture lookups
+// use the same GameRoot and FileSystem resolution as Validate.
+func SuggestFixes(m *Material, diagnostics []lint.Diagnostic, opt *ValidateOptions) []Fix {
+	if m == nil || len(diagnostics) == 0 {
+		return nil
+	}
+
+	vopt := opt.normalize()
+	resolver := PathResolver{GameRoot: vopt.GameRoot, FileSystem: vopt.FileSystem}
+	claimed := make(map[fixClaimKey]struct{}, len(diagnostics))
+
+	var out []Fix
+	for _, diagnostic := range diagnostics {
+		code, ok := lint.ParsePublicCode(diagnostic.Code)
+		if !ok {
+			continue
+		}
+
+		switch code {
+		case CodeValidateUnexpectedTextureExtension:
+			index, ok := claimTextureStage(m, claimed, code, diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			sibling, ok := siblingPAATexture(resolver, diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindSetTexture,
+				Stage:       m.Stages[index].Name,
+				Value:       sibling,
+				Description: "use existing .paa sibling texture",
+				Edits:       textureSourceEdits(m.Stages[index], sibling),
+				Diagnostic:  diagnostic,
+				StageIndex:  index,
+				Safe:        true,
+			})
+
+		case CodeValidateTexturePathParentTraversal:
+			index, ok := claimTextureStage(m, claimed, code, diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			cleaned, ok := cleanTextureParentSegments(diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindSetTexture,
+				Stage:       m.Stages[index].Name,
+				Value:       cleaned,
+				Description: "collapse '..' path segments",
+				Edits:       textureSourceEdits(m.Stages[index], cleaned),
+				Diagnostic:  diagnostic,
+				StageIndex:  index,
+				Safe:        true,
+			})
+
+		case CodeValidateShaderProfileMissingRequiredStage,
+			CodeValidateShaderProfileMissingCommonStage:
+			if _, ok := fallbackTextureForStage(diagnostic.Path); !ok {
+				continue
+			}
+			st, index, ok := missingStage(m, diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindAddStage,
+				Stage:       diagnostic.Path,
+				Value:       diagnostic.Path,
+				Description: "add stage with fallback texture",
+				Edits:       addStageSourceEdits(m, index, st),
+				Diagnostic:  diagnostic,
+				StageIndex:  -1,
+				Safe:        true,
+			})
+
+		case CodeValidateDuplicateStageName:
+			index, ok := claimDuplicateStage(m, claimed, code, diagnostic.Path)
+			if !ok {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindRemoveStage,
+				Stage:       diagnostic.Path,
+				Description: "remove duplicated stage class",
+				Edits:       spanSourceEdits(stageSourceSpan(m.Stages[index]), ""),
+				Diagnostic:  diagnostic,
+				StageIndex:  index,
+			})
+
+		case CodeValidatePixelShaderIDCase:
+			canonical, ok := canonicalKnownName(knownPixelShaderID, m.PixelShaderID)
+			if !ok || canonical == m.PixelShaderID {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindSetPixelShaderID,
+				Value:       canonical,
+				Description: "use canonical PixelShaderID casing",
+				Edits:       spanSourceEdits(materialKeySpan(m, "PixelShaderID"), "PixelShaderID="+quoteConfigString(canonical)+";"),
+				Diagnostic:  diagnostic,
+				StageIndex:  -1,
+				Safe:        true,
+			})
+
+		case CodeValidateVertexShaderIDCase:
+			canonical, ok := canonicalKnownName(knownVertexShaderID, m.VertexShaderID)
+			if !ok || canonical == m.VertexShaderID {
+				continue
+			}
+
+			out = append(out, Fix{
+				Kind:        FixKindSetVertexShaderID,
+				Value:       canonical,
+				Description: "use canonical VertexShaderID casing",
+				Edits:       spanSourceEdits(materialKeySpan(m, "VertexShaderID"), "VertexShaderID="+quoteConfigString(canonical)+";"),
+				Diagnostic:  diagnostic,
+				StageIndex:  -1,
+				Safe:        true,
+			})
+		}
+	}
+
+	sortAddStageFixes(out)
+	return out
+}
+
+// sortAddStageFixes orders stage insertions canonically in place, keeping
+// other fixes at their positions.
+func sortAddStageFixes(fixes []Fix) {
+	var slots []int
+	var adds []Fix
+	for i, fix := range fixes {
+		if fix.Kind == FixKindAddStage {
+			slots = append(slots, i)
+			adds = append(adds, fix)
+		}
+	}
+
+	slices.SortStableFunc(adds, func(a, b Fix) int {
+		return compareStageNames(a.Stage, b.Stage)
+	})
+	for i, slot := range slots {
+		fixes[slot] = adds[i]
+	}
+}
+
+// ApplyFixes applies fixes directly to material.
+//
+// Only safe fixes are applied unless ApplyFixesOptions.Unsafe is set.
+// Fixes whose target changed since they were suggested are skipped as stale;
+// run validation again to pick up follow-up fixes.
+func ApplyFixes(m *Material, fixes []Fix, opt *ApplyFixesOptions) (FixResult, []lint.Diagnostic) {
+	fixOptions := opt.normalize()
+	if m == nil {
+		return FixResult{}, []lint.Diagnostic{errorDiagnostic(
+			CodeNormalizeNilMaterial,
+			"apply fixes failed: material is nil",
+			"",
+		)}
+	}
+
+	var result FixResult
+	var removals []Fix
+	for _, fix := range fixes {
+		if !fix.Safe && !fixOptions.Unsafe {
+			result.Skipped++
+			continue
+		}
+
+		if fix.Kind == FixKindRemoveStage {
+			removals = append(removals, fix)
+			continue
+		}
+
+		if !applyFix(m, fix, &result) {
+			result.Skipped++
+			continue
+		}
+
+		result.Applied++
+	}
+
+	// Remove from the tail so earlier stage indexes stay valid.
+	slices.SortStableFunc(removals, func(a, b Fix) int {
+		return b.StageIndex - a.StageIndex
+	})
+	for _, fix := range removals {
+		if !applyFix(m, fix, &result) {
+			result.Skipped++
+			continue
+		}
+
+		result.Applied++
+	}
+
+	result.Changed = result.Applied > 0
+	return result, nil
+}
+
+// applyFix applies one fix and reports whether material was changed.
+func applyFix(m *Material, fix Fix, result *FixResult) bool {
+	switch fix.Kind {
+	case FixKindSetTexture:
+		st, ok := fixTargetStage(m, fix)
+		if !ok || st.Texture.Raw != fix.Diagnostic.Path || fix.Value == "" {
+			return false
+		}
+
+		st.Texture = ParseTextureRef(fix.Value)
+		result.count(fix.Kind)
+		return true
+
+	case FixKindAddStage:
+		st, index, ok := missingStage(m, fix.Value)
+		if !ok {
+			return false
+		}
+
+		m.Stages = slices.Insert(m.Stages, index, st)
+		result.count(fix.Kind)
+		return true
+
+	case FixKindRemoveStage:
+		if _, ok := fixTargetStage(m, fix); !ok {
+			return false
+		}
+		if !hasOtherStageNamed(m, fix.Stage, fix.StageIndex) {
+			return false
+		}
+
+		m.Stages = slices.Delete(m.Stages, fix.StageIndex, fix.StageIndex+1)
+		result.count(fix.Kind)
+		return true
+
+	case FixKindSetPixelShaderID:
+		if !strings.EqualFold(m.PixelShaderID, fix.Value) || m.PixelShaderID == fix.Value {
+			return false
+		}
+
+		m.PixelShaderID = fix.Value
+		result.count(fix.Kind)
+		return true
+
+	case FixKindSetVertexShaderID:
+		if !strings.EqualFold(m.VertexShaderID, fix.Value) || m.VertexShaderID == fix.Value {
+			return false
+		}
+
+		m.VertexShaderID = fix.Value
+		result.count(fix.Kind)
+		return true
+
+	default:
+		return false
+	}
+}
+
+// ApplySourceFixes applies source edits of fixes to parsed source text.
+//
+// Only safe fixes are applied unless ApplyFixesOptions.Unsafe is set. Fixes
+// without edits, with edits outside src, or overlapping edits of an earlier
+// fix are skipped. Inserted stages use default FormatOptions layout.
+func ApplySourceFixes(src []byte, fixes []Fix, opt *ApplyFixesOptions) ([]byte, FixResult) {
+	fixOptions := opt.normalize()
+
+	var result FixResult
+	var edits []SourceEdit
+	for _, fix := range fixes {
+		if !fix.Safe && !fixOptions.Unsafe || !sourceEditsFit(src, edits, fix.Edits) {
+			result.Skipped++
+			continue
+		}
+
+		edits = append(edits, fix.Edits...)
+		result.count(fix.Kind)
+		result.Applied++
+	}
+
+	// Apply from the tail so earlier offsets stay valid; replacements go
+	// before insertions at the same offset, later insertions before earlier.
+	order := make([]int, len(edits))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		ea, eb := edits[a], edits[b]
+		if ea.Start.Offset != eb.Start.Offset {
+			return eb.Start.Offset - ea.Start.Offset
+		}
+		if ea.End.Offset != eb.End.Offset {
+			return eb.End.Offset - ea.End.Offset
+		}
+		return b - a
+	})
+
+	out := slices.Clone(src)
+	for _, i := range order {
+		e := edits[i]
+		start, end := e.Start.Offset, e.End.Offset
+		// Removal of whole lines also drops their line break.
+		if e.Text == "" && (start == 0 || out[start-1] == '\n') && end < len(out) && out[end] == '\n' {
+			end++
+		}
+		out = slices.Concat(out[:start], []byte(e.Text), out[end:])
+	}
+
+	result.Changed = result.Applied > 0
+	return out, result
+}
+
+// sourceEditsFit reports whether edits are inside src and do not overlap
+// already accepted ones.
+func sourceEditsFit(src []byte, accepted, edits []SourceEdit) bool {
+	if len(edits) == 0 {
+		return false
+	}
+
+	for i, e := range edits {
+		if e.Start.Offset < 0 || e.Start.Offset > e.End.Offset || e.End.Offset > len(src) {
+			return false
+		}
+		for _, other := range slices.Concat(accepted, edits[:i]) {
+			if sourceEditsOverlap(e, other) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sourceEditsOverlap reports whether edits touch same source bytes.
+// Insertions overlap only replacements strictly around them.
+func sourceEditsOverlap(a, b SourceEdit) bool {
+	switch {
+	case a.Start.Offset == a.End.Offset:
+		return b.Start.Offset < a.Start.Offset && a.Start.Offset < b.End.Offset
+	case b.Start.Offset == b.End.Offset:
+		return a.Start.Offset < b.Start.Offset && b.Start.Offset < a.End.Offset
+	default:
+		return a.Start.Offset < b.End.Offset && b.Start.Offset < a.End.Offset
+	}
+}
+
+// count records one applied fix of kind.
+func (r *FixResult) count(kind FixKind) {
+	switch kind {
+	case FixKindSetTexture:
+		r.TexturesReplaced++
+	case FixKindAddStage:
+		r.StagesAdded++
+	case FixKindRemoveStage:
+		r.StagesRemoved++
+	case FixKindSetPixelShaderID, FixKindSetVertexShaderID:
+		r.ShaderIDsFixed++
+	}
+}
+
+// missingStage returns fallback stage for missing name with its canonical
+// insertion index.
+//
+// UV wiring is copied from nearest stage using texture coordinates.
+func missingStage(m *Material, name string) (Stage, int, bool) {
+	if strings.TrimSpace(name) == "" || hasStageNameCI(m, name) {
+		return Stage{}, 0, false
+	}
+
+	fallback, ok := fallbackTextureForStage(name)
+	if !ok {
+		return Stage{}, 0, false
+	}
+
+	index := len(m.Stages)
+	for i := range m.Stages {
+		if compareStageNames(m.Stages[i].Name, name) > 0 {
+			index = i
+			break
+		}
+	}
+
+	st := Stage{Name: name, Texture: fallback}
+	copyNeighbourStageUV(m, &st, index)
+	return st, index, true
+}
+
+// copyNeighbourStageUV copies UV wiring of nearest stage around insertion
+// index; stages with uvSource "none" are skipped. Without such stage inline
+// tex UV is used.
+func copyNeighbourStageUV(m *Material, st *Stage, index int) {
+	for d := 1; d <= len(m.Stages); d++ {
+		for _, i := range [...]int{index - d, index + d - 1} {
+			if i < 0 || i >= len(m.Stages) {
+				continue
+			}
+
+			n := m.Stages[i]
+			if n.TexGen == "" && n.UVSource == "" {
+				continue
+			}
+			if source, err := EffectiveUVSource(m, n); err != nil || strings.EqualFold(source, "none") {
+				continue
+			}
+
+			st.TexGen = n.TexGen
+			st.UVSource = n.UVSource
+			if n.UVTransform != nil {
+				uv := cloneUVTransform(*n.UVTransform)
+				st.UVTransform = &uv
+			}
+			return
+		}
+	}
+
+	applyStageUV(st, false)
+}
+
+// textureSourceEdits returns edit replacing stage texture statement.
+func textureSourceEdits(st Stage, texture string) []SourceEdit {
+	var span sourceSpan
+	if st.source != nil {
+		span = st.source.keys["texture"]
+	}
+
+	return spanSourceEdits(span, "texture="+quoteConfigString(texture)+";")
+}
+
+// addStageSourceEdits returns edit inserting stage before stage at index or
+// after the previous one.
+func addStageSourceEdits(m *Material, index int, st Stage) []SourceEdit {
+	fopt := (*FormatOptions)(nil).normalize()
+	var b strings.Builder
+	w := &writer{w: &b, indent: fopt.Indent, precision: fopt.Precision}
+	if err := w.writeStage(st); err != nil {
+		return nil
+	}
+	text := b.String()
+
+	if index < len(m.Stages) {
+		if span := stageSourceSpan(m.Stages[index]); !span.isZero() {
+			at := lint.Position{Line: span.startLine, Column: span.startCol, Offset: int(span.start)}
+			return []SourceEdit{{Text: text, Start: at, End: at}}
+		}
+	}
+	if index > 0 {
+		if span := stageSourceSpan(m.Stages[index-1]); !span.isZero() {
+			// Stage class ends with one-byte ";" token.
+			at := lint.Position{Line: span.endLine, Column: span.endCol + 1, Offset: int(span.end)}
+			return []SourceEdit{{Text: "\n" + strings.TrimSuffix(text, "\n"), Start: at, End: at}}
+		}
+	}
+
+	return nil
+}
+
+// stageSourceSpan returns whole class span of parsed stage.
+func stageSourceSpan(st Stage) sourceSpan {
+	if st.source == nil {
+		return sourceSpan{}
+	}
+
+	return st.source.span
+}
+
+// spanSourceEdits returns edit replacing source span with text.
+func spanSourceEdits(span sourceSpan, text string) []SourceEdit {
+	if span.isZero() {
+		return nil
+	}
+
+	return []SourceEdit{{
+		Text:  text,
+		Start: lint.Position{Line: span.startLine, Column: span.startCol, Offset: int(span.start)},
+		End:   lint.Position{Line: span.endLine, Column: span.endCol, Offset: int(span.end)},
+	}}
+}
+
+// fixTargetStage returns stage addressed by fix index when name still matches.
+func fixTargetStage(m *Material, fix Fix) (*Stage, bool) {
+	if fix.StageIndex < 0 || fix.StageIndex >= len(m.Stages) {
+		return nil, false
+	}
+
+	st := &m.Stages[fix.StageIndex]
+	if st.Name != fix.Stage {
+		return nil, false
+	}
+
+	return st, true
+}
+
+// claimTextureStage finds next unclaimed stage using raw texture value.
+func claimTextureStage(
+	m *Material,
+	claimed map[fixClaimKey]struct{},
+	code lint.Code,
+	raw string,
+) (int, bool) {
+	for i := range m.Stages {
+		if m.Stages[i].Texture.Raw != raw {
+			continue
+		}
+
+		key := fixClaimKey{code: code, index: i}
+		if _, ok := claimed[key]; ok {
+			continue
+		}
+
+		claimed[key] = struct{}{}
+		return i, true
+	}
+
+	return 0, false
+}
+
+// claimDuplicateStage finds next unclaimed repeated stage with given name.
+func claimDuplicateStage(
+	m *Material,
+	claimed map[fixClaimKey]struct{},
+	code lint.Code,
+	name string,
+) (int, bool) {
+	first := true
+	for i := range m.Stages {
+		if m.Stages[i].Name != name {
+			continue
+		}
+		if first {
+			first = false
+			continue
+		}
+
+		key := fixClaimKey{code: code, index: i}
+		if _, ok := claimed[key]; ok {
+			continue
+		}
+
+		claimed[key] = struct{}{}
+		return i, true
+	}
+
+	return 0, false
+}
+
+// hasStageNameCI reports whether material has stage with name in any casing.
+func hasStageNameCI(m *Material, name string) bool {
+	name = strings.TrimSpace(name)
+	for _, st := range m.Stages {
+		if strings.EqualFold(strings.TrimSpace(st.Name), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasOtherStageNamed reports whether stage name is used outside given index.
+func hasOtherStageNamed(m *Material, name string, index int) bool {
+	for i, st := range m.Stages {
+		if i != index && st.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// siblingPAATexture returns raw texture path with .paa extension when that file exists.
+func siblingPAATexture(resolver PathResolver, raw string) (string, bool) {
+	ext := filepath.Ext(raw)
+	if strings.EqualFold(ext, ".paa") {
+		return "", false
+	}
+
+	candidate := strings.TrimSuffix(raw, ext) + ".paa"
+	if !resolver.Exists(candidate) {
+		return "", false
+	}
+
+	return candidate, true
+}
+
+// cleanTextureParentSegments collapses "." and ".." segments in texture path.
+//
+// Paths that would escape their root are left untouched.
+func cleanTextureParentSegments(raw string) (string, bool) {
+	separator := "/"
+	if strings.Contains(raw, `\`) {
+		separator = `\`
+	}
+
+	slashed := strings.ReplaceAll(raw, `\`, "/")
+	rooted := strings.HasPrefix(slashed, "/")
+	parts := strings.Split(strings.TrimPrefix(slashed, "/"), "/")
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(kept) == 0 {
+				return "", false
+			}
+			kept = kept[:len(kept)-1]
+		default:
+			kept = append(kept, part)
+		}
+	}
+	if len(kept) == 0 {
+		return "", false
+	}
+
+	cleaned := strings.Join(kept, "/")
+	if rooted {
+		cleaned = "/" + cleaned
+	}
+	if cleaned == slashed {
+		return "", false
+	}
+
+	return strings.ReplaceAll(cleaned, "/", separator), true
+}
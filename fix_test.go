@@ -0,0 +1,204 @@
+package rvmat
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestValidateWithFixesAndApply(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "mod", "data"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	mustTouchFile(t, filepath.Join(root, "mod", "data", "box_co.paa"))
+
+	mat := &Material{
+		PixelShaderID:  "super",
+		VertexShaderID: "Super",
+		Stages: []Stage{
+			{Name: "Stage1", Texture: ParseTextureRef(`mod\data\box_nohq.paa`), TexGen: "0"},
+			{Name: "Stage2", Texture: ParseTextureRef(`mod\data\sub\..\box_dt.paa`), TexGen: "0"},
+			{Name: "Stage3", Texture: ParseTextureRef(`mod\data\box_co.dds`), TexGen: "0"},
+			{Name: "Stage3", Texture: ParseTextureRef(`mod\data\box_mc.paa`), TexGen: "0"},
+		},
+		TexGens: []TexGen{{
+			Name:     "TexGen0",
+			UVSource: "tex",
+			UVTransform: &UVTransform{
+				Aside: []float64{1, 0, 0},
+				Up:    []float64{0, 1, 0},
+				Dir:   []float64{0, 0, 0},
+				Pos:   []float64{0, 0, 0},
+			},
+		}},
+	}
+	opt := &ValidateOptions{
+		GameRoot:                 root,
+		TexturePathMode:          TexturePathModeIgnore,
+		EnableShaderProfileCheck: true,
+		EnableShaderCaseCheck:    true,
+	}
+
+	_, fixes := ValidateWithFixes(mat, opt)
+	kinds := make(map[FixKind]int, len(fixes))
+	for _, fix := range fixes {
+		kinds[fix.Kind]++
+	}
+	if kinds[FixKindSetTexture] != 2 || kinds[FixKindRemoveStage] != 1 ||
+		kinds[FixKindSetPixelShaderID] != 1 || kinds[FixKindAddStage] != 4 {
+		t.Fatalf("unexpected fix kinds: %v", kinds)
+	}
+
+	result, issues := ApplyFixes(mat, fixes, nil)
+	if len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if result.TexturesReplaced != 2 || result.StagesAdded != 4 || result.ShaderIDsFixed != 1 {
+		t.Fatalf("unexpected fix result: %+v", result)
+	}
+	if result.StagesRemoved != 0 || result.Skipped != 1 || !result.Changed {
+		t.Fatalf("expected unsafe stage removal to be skipped: %+v", result)
+	}
+	if mat.PixelShaderID != "Super" {
+		t.Fatalf("unexpected PixelShaderID: %q", mat.PixelShaderID)
+	}
+	if mat.Stages[1].Texture.Raw != `mod\data\box_dt.paa` {
+		t.Fatalf("unexpected cleaned texture: %q", mat.Stages[1].Texture.Raw)
+	}
+	if mat.Stages[2].Texture.Raw != `mod\data\box_co.paa` {
+		t.Fatalf("unexpected sibling texture: %q", mat.Stages[2].Texture.Raw)
+	}
+	if mat.Stages[len(mat.Stages)-1].TexGen != "0" {
+		t.Fatalf("expected added stage to reuse TexGen0: %+v", mat.Stages[len(mat.Stages)-1])
+	}
+
+	result, _ = ApplyFixes(mat, fixes, &ApplyFixesOptions{Unsafe: true})
+	if result.StagesRemoved != 1 || result.Applied != 1 {
+		t.Fatalf("unexpected unsafe fix result: %+v", result)
+	}
+	if mat.Stages[3].Name == "Stage3" {
+		t.Fatalf("expected duplicated Stage3 to be removed: %+v", mat.Stages[3])
+	}
+}
+
+func TestApplyFixesInsertsStageInCanonicalOrder(t *testing.T) {
+	uv := &UVTransform{Aside: []float64{2, 0, 0}, Up: []float64{0, 2, 0}, Dir: []float64{0, 0, 0}, Pos: []float64{0, 0, 0}}
+	mat := &Material{
+		PixelShaderID:  "Super",
+		VertexShaderID: "Super",
+		Stages: []Stage{
+			{Name: "Stage1", Texture: ParseTextureRef(`mod\data\box_nohq.paa`), UVSource: "tex", UVTransform: uv},
+			{Name: "Stage3", Texture: ParseTextureRef(`mod\data\box_mc.paa`), UVSource: "tex", UVTransform: uv},
+			{Name: "Stage4", Texture: ParseTextureRef(`mod\data\box_as.paa`), UVSource: "tex", UVTransform: uv},
+			{Name: "Stage5", Texture: ParseTextureRef(`mod\data\box_smdi.paa`), UVSource: "tex", UVTransform: uv},
+			{Name: "Stage6", Texture: ParseTextureRef("#(ai,64,1,1)fresnel(1.3,0.7)"), UVSource: "none"},
+			{Name: "Stage7", Texture: ParseTextureRef(`dz\data\data\env_land_co.paa`), UVSource: "none"},
+		},
+	}
+
+	_, fixes := ValidateWithFixes(mat, &ValidateOptions{EnableShaderProfileCheck: true})
+	result, _ := ApplyFixes(mat, fixes, nil)
+	if result.StagesAdded != 1 {
+		t.Fatalf("unexpected fix result: %+v", result)
+	}
+
+	st := mat.Stages[1]
+	if st.Name != "Stage2" || st.TexGen != "" || st.UVSource != "tex" || !reflect.DeepEqual(st.UVTransform, uv) {
+		t.Fatalf("expected Stage2 inserted with neighbour UV: %+v", mat.Stages)
+	}
+}
+
+func TestApplySourceFixes(t *testing.T) {
+	src := []byte(`PixelShaderID="super";
+VertexShaderID="Super";
+class Stage1
+{
+	texture="mod\data\sub\..\box_nohq.paa";
+	texGen="0";
+};
+class Stage3
+{
+	texture="mod\data\box_mc.paa";
+	texGen="0";
+};
+class Stage3
+{
+	texture="mod\data\box_mc.paa";
+	texGen="0";
+};
+class TexGen0
+{
+	uvSource="tex";
+};
+`)
+	mat, err := Parse(src, nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	_, fixes := ValidateWithFixes(mat, &ValidateOptions{EnableShaderProfileCheck: true, EnableShaderCaseCheck: true})
+	out, result := ApplySourceFixes(src, fixes, &ApplyFixesOptions{Unsafe: true})
+	if result.Skipped != 0 || result.TexturesReplaced != 1 || result.ShaderIDsFixed != 1 ||
+		result.StagesRemoved != 1 || result.StagesAdded == 0 {
+		t.Fatalf("unexpected source fix result: %+v\n%s", result, out)
+	}
+
+	fixed, err := Parse(out, nil)
+	if err != nil {
+		t.Fatalf("parse fixed source: %v\n%s", err, out)
+	}
+	if fixed.PixelShaderID != "Super" || fixed.Stages[0].Texture.Raw != `mod\data\box_nohq.paa` {
+		t.Fatalf("unexpected fixed material:\n%s", out)
+	}
+	names := make([]string, len(fixed.Stages))
+	for i, st := range fixed.Stages {
+		names[i] = st.Name
+	}
+	if !slices.Equal(names, []string{"Stage1", "Stage2", "Stage3", "Stage4", "Stage5", "Stage6", "Stage7"}) ||
+		fixed.Stages[1].TexGen != "0" || bytes.Contains(out, []byte("};\n\n")) {
+		t.Fatalf("expected stages in canonical order without gaps:\n%s", out)
+	}
+	if _, again := ValidateWithFixes(fixed, &ValidateOptions{EnableShaderProfileCheck: true, EnableShaderCaseCheck: true}); len(again) != 0 {
+		t.Fatalf("expected no follow-up fixes, got %+v\n%s", again, out)
+	}
+}
+
+func TestSuggestFixesSkipsMissingSibling(t *testing.T) {
+	mat := &Material{
+		Stages: []Stage{
+			{Name: "Stage1", Texture: ParseTextureRef(`mod\data\box_nohq.dds`)},
+		},
+	}
+
+	_, fixes := ValidateWithFixes(mat, &ValidateOptions{GameRoot: t.TempDir()})
+	for _, fix := range fixes {
+		if fix.Kind == FixKindSetTexture {
+			t.Fatalf("unexpected texture fix without sibling file: %+v", fix)
+		}
+	}
+}
+
+func TestCleanTextureParentSegments(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{raw: `dz\data\..\data\a_co.paa`, want: `dz\data\a_co.paa`, ok: true},
+		{raw: `dz/a/./b/../c.paa`, want: `dz/a/c.paa`, ok: true},
+		{raw: `..\dz\a_co.paa`},
+		{raw: `\dz\..\..\a_co.paa`},
+		{raw: `dz\a..b_co.paa`},
+	}
+
+	for _, tt := range tests {
+		got, ok := cleanTextureParentSegments(tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("cleanTextureParentSegments(%q) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import "github.com/woozymasta/lintkit/lint"
+
+// FixKind identifies one material edit operation.
+type FixKind string
+
+const (
+	// FixKindSetTexture replaces stage texture value.
+	FixKindSetTexture FixKind = "set_texture"
+
+	// FixKindAddStage inserts missing stage with fallback texture in canonical order.
+	FixKindAddStage FixKind = "add_stage"
+
+	// FixKindRemoveStage removes duplicated stage class.
+	FixKindRemoveStage FixKind = "remove_stage"
+
+	// FixKindSetPixelShaderID replaces PixelShaderID value.
+	FixKindSetPixelShaderID FixKind = "set_pixel_shader_id"
+
+	// FixKindSetVertexShaderID replaces VertexShaderID value.
+	FixKindSetVertexShaderID FixKind = "set_vertex_shader_id"
+)
+
+// Fix describes one machine-applicable material edit for a diagnostic.
+type Fix struct {
+	// Kind is edit operation type.
+	Kind FixKind `json:"kind" yaml:"kind"`
+
+	// Stage is target stage name for stage-level edits.
+	Stage string `json:"stage,omitempty" yaml:"stage,omitempty"`
+
+	// Value is new value (texture path, stage name, or shader ID).
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Description is human-readable fix summary.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Edits holds equivalent edits of parsed source text; empty for
+	// materials without source spans.
+	Edits []SourceEdit `json:"edits,omitempty" yaml:"edits,omitempty"`
+
+	// Diagnostic is validation diagnostic addressed by this fix.
+	Diagnostic lint.Diagnostic `json:"diagnostic" yaml:"diagnostic"`
+
+	// StageIndex is target index in Material.Stages for texture and remove edits.
+	StageIndex int `json:"stage_index" yaml:"stage_index"`
+
+	// Safe indicates fix keeps material intent and may be applied in bulk.
+	Safe bool `json:"safe" yaml:"safe"`
+}
+
+// SourceEdit replaces parsed source text of one statement.
+//
+// Start and End use diagnostic span convention: End points at last token of
+// replaced statement. Offsets are byte offsets; End.Offset is exclusive.
+// Equal offsets insert Text.
+type SourceEdit struct {
+	// Text is replacement text.
+	Text string `json:"text" yaml:"text"`
+
+	// Start is position of first replaced byte.
+	Start lint.Position `json:"start" yaml:"start"`
+
+	// End is position of last replaced token.
+	End lint.Position `json:"end" yaml:"end"`
+}
+
+// FixResult reports what was changed by ApplyFixes and ApplySourceFixes.
+type FixResult struct {
+	// Applied is count of applied fixes.
+	Applied int `json:"applied,omitempty" yaml:"applied,omitempty"`
+
+	// Skipped is count of unsafe or stale fixes that were not applied.
+	Skipped int `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+
+	// TexturesReplaced is count of stage textures replaced.
+	TexturesReplaced int `json:"textures_replaced,omitempty" yaml:"textures_replaced,omitempty"`
+
+	// StagesAdded is count of missing stages added.
+	StagesAdded int `json:"stages_added,omitempty" yaml:"stages_added,omitempty"`
+
+	// StagesRemoved is count of duplicate stages removed.
+	StagesRemoved int `json:"stages_removed,omitempty" yaml:"stages_removed,omitempty"`
+
+	// ShaderIDsFixed is count of shader ID values rewritten.
+	ShaderIDsFixed int `json:"shader_ids_fixed,omitempty" yaml:"shader_ids_fixed,omitempty"`
+
+	// Changed indicates whether any fix was applied.
+	Changed bool `json:"changed" yaml:"changed"`
+}
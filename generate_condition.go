@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// conditionEffectDetailSwap marks Stage2 texture swapped by condition.
+	conditionEffectDetailSwap = "detail_swap"
+	// conditionEffectDetailBlend marks procedural Stage2 blended by condition.
+	conditionEffectDetailBlend = "detail_blend"
+	// conditionEffectMacroSwap marks Stage3 texture swapped by condition.
+	conditionEffectMacroSwap = "macro_swap"
+	// conditionEffectMacroBlend marks procedural Stage3 blended by condition.
+	conditionEffectMacroBlend = "macro_blend"
+	// conditionEffectGlossScale marks procedural SMDI gloss scaled by condition.
+	conditionEffectGlossScale = "gloss_scale"
+	// conditionEffectASDarken marks procedural AS darkened by condition.
+	conditionEffectASDarken = "as_darken"
+)
+
+// DefaultConditionEffect returns built-in stage effect preset for condition.
+//
+// Presets are opt-in: pass one as GenerateOptions.ConditionEffect or
+// GenerateSetOptions.ConditionEffect. Conditions without preset return zero
+// effect.
+func DefaultConditionEffect(condition Condition) ConditionEffect {
+	switch condition {
+	case ConditionDirty:
+		return ConditionEffect{
+			DetailColor: []float64{0.42, 0.4, 0.37, 0.5},
+			MacroColor:  []float64{0.36, 0.31, 0.25, 0.35},
+			GlossScale:  0.75,
+			ASDarken:    0.15,
+		}
+	case ConditionOxidized:
+		return ConditionEffect{
+			MacroColor: []float64{0.45, 0.24, 0.12, 0.4},
+			GlossScale: 0.65,
+			ASDarken:   0.1,
+		}
+	default:
+		return ConditionEffect{}
+	}
+}
+
+// resolveConditionEffect returns option or profile condition effect; without
+// both no stage effect is applied.
+func resolveConditionEffect(seed materialSeed, condition Condition, override *ConditionEffect) (ConditionEffect, error) {
+	if override != nil {
+		if err := validateConditionEffect(*override); err != nil {
+			return ConditionEffect{}, fmt.Errorf("%w condition effect: %w", ErrInvalidGenerateOption, err)
+		}
+		return *override, nil
+	}
+
+	return seed.conditionEffects[condition], nil
+}
+
+// validateConditionEffect checks effect colors and factors.
+func validateConditionEffect(effect ConditionEffect) error {
+	for _, item := range [...]struct {
+		field  string
+		values []float64
+	}{{"detail_color", effect.DetailColor}, {"macro_color", effect.MacroColor}} {
+		if len(item.values) == 0 {
+			continue
+		}
+		if len(item.values) != 4 {
+			return fmt.Errorf("%s needs 4 values, got %d", item.field, len(item.values))
+		}
+		for _, v := range item.values {
+			if v < 0 || v > 1 {
+				return fmt.Errorf("%s value %g out of 0..1", item.field, v)
+			}
+		}
+	}
+	if effect.GlossScale < 0 {
+		return errors.New("gloss_scale must be >= 0")
+	}
+	if effect.ASDarken < 0 || effect.ASDarken > 1 {
+		return fmt.Errorf("as_darken %g out of 0..1", effect.ASDarken)
+	}
+
+	return nil
+}
+
+// applyConditionEffect applies condition stage effects to generated material
+// and returns applied effect names by stage name.
+func applyConditionEffect(m *Material, effect ConditionEffect, overrides map[string]string) map[string][]string {
+	applied := map[string][]string{}
+	for i := range m.Stages {
+		stage := &m.Stages[i]
+		role, _ := textureRoleForStageName(stage.Name)
+		if role == "" || textureOverride(overrides, stage.Name, role) != "" {
+			continue
+		}
+
+		color := stage.Texture.Procedural
+		var name string
+		switch role {
+		case "dt":
+			name = swapOrBlendStage(stage, effect.DetailTexture, effect.DetailColor, conditionEffectDetailSwap, conditionEffectDetailBlend, false)
+		case "mc":
+			name = swapOrBlendStage(stage, effect.MacroTexture, effect.MacroColor, conditionEffectMacroSwap, conditionEffectMacroBlend, true)
+		case "smdi":
+			// Gloss range is already scaled in seed; see scaleSMDIGloss.
+			if scalesGloss(effect) && color != nil && color.Color != nil {
+				name = conditionEffectGlossScale
+			}
+		case "as":
+			if effect.ASDarken > 0 && color != nil && color.Color != nil {
+				c := *color.Color
+				k := 1 - effect.ASDarken
+				stage.Texture = NewProceduralColor("argb", 8, 8, 3, c.R*k, c.G*k, c.B*k, c.A, c.Tag)
+				name = conditionEffectASDarken
+			}
+		}
+		if name != "" {
+			applied[stage.Name] = append(applied[stage.Name], name)
+		}
+	}
+
+	return applied
+}
+
+// scalesGloss reports effect that changes SMDI gloss range.
+func scalesGloss(effect ConditionEffect) bool {
+	return effect.GlossScale > 0 && effect.GlossScale != 1
+}
+
+// scaleSMDIGloss returns seed with SMDI gloss (blue) range scaled by effect,
+// so procedural and synthesized SMDI fallbacks share it.
+func scaleSMDIGloss(seed materialSeed, effect ConditionEffect) materialSeed {
+	if !scalesGloss(effect) {
+		return seed
+	}
+
+	gMin, gMax, bMin, bMax := seed.smdiRange()
+	seed.smdi = &[4]float64{gMin, gMax, clamp01(bMin * effect.GlossScale), clamp01(bMax * effect.GlossScale)}
+
+	return seed
+}
+
+// swapOrBlendStage swaps stage texture or blends its procedural color and
+// returns applied effect name. Macro blend replaces color and alpha instead
+// of mixing, since macro alpha is itself the blend amount.
+func swapOrBlendStage(stage *Stage, texture string, rgba []float64, swap, blend string, replaceAlpha bool) string {
+	if raw := strings.TrimSpace(texture); raw != "" {
+		stage.Texture = ParseTextureRef(NormalizeGameTexturePath(raw))
+		return swap
+	}
+
+	proc := stage.Texture.Procedural
+	if len(rgba) != 4 || proc == nil || proc.Color == nil {
+		return ""
+	}
+
+	c := *proc.Color
+	if replaceAlpha {
+		stage.Texture = NewProceduralColor("argb", 8, 8, 3, rgba[0], rgba[1], rgba[2], rgba[3], c.Tag)
+		return blend
+	}
+
+	k := rgba[3]
+	stage.Texture = NewProceduralColor(
+		"argb", 8, 8, 3,
+		c.R+(rgba[0]-c.R)*k,
+		c.G+(rgba[1]-c.G)*k,
+		c.B+(rgba[2]-c.B)*k,
+		c.A,
+		c.Tag,
+	)
+
+	return blend
+}
+
+// isConditionTextureEffect reports effect that replaces stage texture.
+func isConditionTextureEffect(name string) bool {
+	switch name {
+	case conditionEffectDetailSwap, conditionEffectDetailBlend, conditionEffectMacroSwap, conditionEffectMacroBlend:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// damageHealthLevels are DayZ health level thresholds from pristine to ruined.
+var damageHealthLevels = [...]string{"1.0", "0.7", "0.5", "0.3", "0.0"}
+
+// DamageConfigOptions configures GenerateDamageConfig.
+type DamageConfigOptions struct {
+	// ClassName is config class receiving material mapping.
+	ClassName string `json:"class_name" yaml:"class_name"`
+	// BaseClass is optional parent class of ClassName.
+	BaseClass string `json:"base_class,omitempty" yaml:"base_class,omitempty"`
+	// GameRoot is stripped from result output paths to get material game paths.
+	GameRoot string `json:"game_root,omitempty" yaml:"game_root,omitempty"`
+	// Indent is the indentation string for nested blocks (default is four spaces).
+	Indent string `json:"indent,omitempty" yaml:"indent,omitempty"`
+	// Selections lists hiddenSelections names, one per result;
+	// empty value omits hiddenSelections[].
+	Selections []string `json:"selections,omitempty" yaml:"selections,omitempty"`
+	// Hitpoints sets GlobalHealth hitpoints (default 100).
+	Hitpoints float64 `json:"hitpoints,omitempty" yaml:"hitpoints,omitempty"`
+	// Intermediates uses _worn and _badly_damaged materials for worn and
+	// badly damaged levels even when result has no such variants.
+	Intermediates bool `json:"intermediates,omitempty" yaml:"intermediates,omitempty"`
+}
+
+// GenerateDamageConfig writes config.cpp class with hiddenSelectionsMaterials[]
+// and DamageSystem healthLevels[] for generated material sets.
+//
+// Each result maps to one hidden selection. Health levels are written in
+// pristine, worn, damaged, badly damaged, ruined order; a missing variant
+// falls back to the previous healthier material.
+func GenerateDamageConfig(results []*GenerateSetResult, opt DamageConfigOptions) ([]byte, error) {
+	if strings.TrimSpace(opt.ClassName) == "" {
+		return nil, errors.New("generate damage config: empty class name")
+	}
+	if len(results) == 0 {
+		return nil, errors.New("generate damage config: no results")
+	}
+	if len(opt.Selections) != 0 && len(opt.Selections) != len(results) {
+		return nil, fmt.Errorf("generate damage config: %d selections for %d results", len(opt.Selections), len(results))
+	}
+
+	levels := make([][]string, len(damageHealthLevels))
+	mains := make([]string, 0, len(results))
+	for i, result := range results {
+		if result == nil || strings.TrimSpace(result.MainOutputPath) == "" {
+			return nil, fmt.Errorf("generate damage config: result %d has no main output path", i)
+		}
+
+		for level, path := range damageHealthLevelPaths(result, opt.Intermediates) {
+			levels[level] = append(levels[level], configMaterialPath(path, opt.GameRoot))
+		}
+		mains = append(mains, configMaterialPath(result.MainOutputPath, opt.GameRoot))
+	}
+
+	indent := opt.Indent
+	if indent == "" {
+		indent = "    "
+	}
+	hitpoints := opt.Hitpoints
+	if hitpoints <= 0 {
+		hitpoints = 100
+	}
+
+	var b strings.Builder
+	line := func(depth int, s string) {
+		b.WriteString(strings.Repeat(indent, depth))
+		b.WriteString(s)
+		b.WriteByte('\n')
+	}
+
+	header := "class " + opt.ClassName
+	if opt.BaseClass != "" {
+		header += ": " + opt.BaseClass
+	}
+	line(0, header)
+	line(0, "{")
+	if len(opt.Selections) != 0 {
+		line(1, "hiddenSelections[]="+configStringArray(opt.Selections)+";")
+	}
+	line(1, "hiddenSelectionsMaterials[]="+configStringArray(mains)+";")
+	line(1, "class DamageSystem")
+	line(1, "{")
+	line(2, "class GlobalHealth")
+	line(2, "{")
+	line(3, "class Health")
+	line(3, "{")
+	line(4, "hitpoints="+formatPrettyFloat(hitpoints)+";")
+	line(4, "healthLevels[]=")
+	line(4, "{")
+	for level, paths := range levels {
+		entry := "{" + damageHealthLevels[level] + "," + configStringArray(paths) + "}"
+		if level < len(levels)-1 {
+			entry += ","
+		}
+		line(5, entry)
+	}
+	line(4, "};")
+	line(3, "};")
+	line(2, "};")
+	line(1, "};")
+	line(0, "};")
+
+	return []byte(b.String()), nil
+}
+
+// damageHealthLevelPaths returns output paths of result for each health level.
+//
+// Generated worn and badly damaged variants are used when present.
+func damageHealthLevelPaths(result *GenerateSetResult, intermediates bool) [len(damageHealthLevels)]string {
+	variant := func(suffix, fallback string) string {
+		if _, path, ok := result.Variant(suffix); ok && path != "" {
+			return path
+		}
+		if intermediates {
+			return outputPathWithSuffix(result.MainOutputPath, suffix)
+		}
+
+		return fallback
+	}
+
+	pristine := result.MainOutputPath
+	worn := variant(DamageSuffixWorn, pristine)
+	damaged := cmp.Or(result.DamageOutputPath, worn)
+	badly := variant(DamageSuffixBadlyDamaged, damaged)
+	ruined := cmp.Or(result.DestructOutputPath, badly)
+
+	return [...]string{pristine, worn, damaged, badly, ruined}
+}
+
+// configMaterialPath converts output file path to config game path.
+func configMaterialPath(path, gameRoot string) string {
+	p := strings.ReplaceAll(strings.TrimSpace(path), "/", `\`)
+	root := strings.TrimRight(strings.ReplaceAll(strings.TrimSpace(gameRoot), "/", `\`), `\`)
+	if root != "" && len(p) > len(root) && strings.EqualFold(p[:len(root)], root) && p[len(root)] == '\\' {
+		p = p[len(root):]
+	}
+
+	return NormalizeGameTexturePath(p)
+}
+
+// configStringArray formats strings as config array literal.
+func configStringArray(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = quoteConfigString(s)
+	}
+
+	return "{" + strings.Join(quoted, ",") + "}"
+}
@@ -0,0 +1,87 @@
+package rvmat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateDamageConfig(t *testing.T) {
+	crate := &GenerateSetResult{
+		MainOutputPath:     `P:\mod\data\crate.rvmat`,
+		DamageOutputPath:   `P:\mod\data\crate_damage.rvmat`,
+		DestructOutputPath: `P:\mod\data\crate_destruct.rvmat`,
+	}
+	lid := &GenerateSetResult{MainOutputPath: `P:\mod\data\lid.rvmat`}
+
+	out, err := GenerateDamageConfig([]*GenerateSetResult{crate, lid}, DamageConfigOptions{
+		ClassName:  "Test_Crate",
+		BaseClass:  "Container_Base",
+		GameRoot:   `P:\`,
+		Selections: []string{"camo", "lid"},
+		Indent:     "\t",
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	want := `class Test_Crate: Container_Base
+{
+	hiddenSelections[]={"camo","lid"};
+	hiddenSelectionsMaterials[]={"mod\data\crate.rvmat","mod\data\lid.rvmat"};
+	class DamageSystem
+	{
+		class GlobalHealth
+		{
+			class Health
+			{
+				hitpoints=100;
+				healthLevels[]=
+				{
+					{1.0,{"mod\data\crate.rvmat","mod\data\lid.rvmat"}},
+					{0.7,{"mod\data\crate.rvmat","mod\data\lid.rvmat"}},
+					{0.5,{"mod\data\crate_damage.rvmat","mod\data\lid.rvmat"}},
+					{0.3,{"mod\data\crate_damage.rvmat","mod\data\lid.rvmat"}},
+					{0.0,{"mod\data\crate_destruct.rvmat","mod\data\lid.rvmat"}}
+				};
+			};
+		};
+	};
+};
+`
+	if string(out) != want {
+		t.Fatalf("unexpected config:\n%s", out)
+	}
+
+	out, err = GenerateDamageConfig([]*GenerateSetResult{crate}, DamageConfigOptions{
+		ClassName:     "Test_Crate",
+		Hitpoints:     250,
+		Intermediates: true,
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	text := string(out)
+	if strings.Contains(text, "hiddenSelections[]") || !strings.Contains(text, "hitpoints=250;") ||
+		!strings.Contains(text, `{0.7,{"mod\data\crate_worn.rvmat"}},`) ||
+		!strings.Contains(text, `{0.3,{"mod\data\crate_badly_damaged.rvmat"}},`) {
+		t.Fatalf("unexpected config with intermediates:\n%s", text)
+	}
+
+	// Generated worn variant is used without Intermediates.
+	crate.Variants = []GeneratedVariant{{Suffix: DamageSuffixWorn, OutputPath: `P:\mod\data\crate_worn.rvmat`, Material: &Material{}}}
+	out, err = GenerateDamageConfig([]*GenerateSetResult{crate}, DamageConfigOptions{ClassName: "Test_Crate"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if text := string(out); !strings.Contains(text, `{0.7,{"mod\data\crate_worn.rvmat"}},`) ||
+		!strings.Contains(text, `{0.3,{"mod\data\crate_damage.rvmat"}},`) {
+		t.Fatalf("unexpected config with worn variant:\n%s", text)
+	}
+
+	if _, err := GenerateDamageConfig([]*GenerateSetResult{crate}, DamageConfigOptions{
+		ClassName:  "Test_Crate",
+		Selections: []string{"camo", "lid"},
+	}); err == nil {
+		t.Fatal("expected selection count error")
+	}
+}
@@ -13,33 +13,37 @@ import (
 
 // Generate generates baseline material from options.
 func Generate(opts GenerateOptions) (*Material, error) {
+	m, _, err := generateMaterial(opts)
+	return m, err
+}
+
+// generateMaterial generates material and returns condition effects
+// applied by stage name.
+func generateMaterial(opts GenerateOptions) (*Material, map[string][]string, error) {
 	if opts.WithDamage && opts.WithDestruct {
-		return nil, errors.New("generate material: both WithDamage and WithDestruct are set")
+		return nil, nil, errors.New("generate material: both WithDamage and WithDestruct are set")
 	}
 
-	baseMaterial, err := normalizeBaseMaterial(opts.BaseMaterial)
+	seed, err := resolveMaterialSeed(opts.Profile, opts.BaseMaterial)
 	if err != nil {
-		return nil, fmt.Errorf("generate material: %w", err)
+		return nil, nil, fmt.Errorf("generate material: %w", err)
 	}
 
 	finish, err := normalizeFinish(opts.Finish)
 	if err != nil {
-		return nil, fmt.Errorf("generate material: %w", err)
+		return nil, nil, fmt.Errorf("generate material: %w", err)
 	}
 
 	condition, err := normalizeCondition(opts.Condition)
 	if err != nil {
-		return nil, fmt.Errorf("generate material: %w", err)
+		return nil, nil, fmt.Errorf("generate material: %w", err)
 	}
 
-	seed, ok := materialCatalog[baseMaterial]
-	if !ok {
-		return nil, fmt.Errorf(
-			"generate material: unknown base material %s: %w",
-			baseMaterial,
-			ErrUnknownBaseMaterial,
-		)
+	effect, err := resolveConditionEffect(seed, condition, opts.ConditionEffect)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate material: %w", err)
 	}
+	seed = scaleSMDIGloss(seed, effect)
 
 	specular, power := applyMaterialModifiers(seed, finish, condition)
 	emissive := seed.emissive
@@ -64,15 +68,19 @@ func Generate(opts GenerateOptions) (*Material, error) {
 	if opts.UseTexGen {
 		m.TexGens = generateSuperTexGens()
 	}
+	effects := applyConditionEffect(m, effect, opts.TextureOverrides)
 
-	if opts.WithDamage {
-		return GenerateDamage(m)
+	switch {
+	case opts.WithDamage:
+		m, err = GenerateDamage(m)
+	case opts.WithDestruct:
+		m, err = GenerateDestruct(m)
 	}
-	if opts.WithDestruct {
-		return GenerateDestruct(m)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return m, nil
+	return m, effects, nil
 }
 
 // GenerateDamage creates damage material variant from base.
@@ -91,7 +99,13 @@ func applyMaterialModifiers(seed materialSeed, finish Finish, condition Conditio
 	power := seed.specularPower
 
 	finishMultS, finishMultP := finishModifier(finish)
+	if mod, ok := seed.finishes[finish]; ok {
+		finishMultS, finishMultP = mod.Specular, mod.Power
+	}
 	conditionMultS, conditionMultP := conditionModifier(condition)
+	if mod, ok := seed.conditions[condition]; ok {
+		conditionMultS, conditionMultP = mod.Specular, mod.Power
+	}
 
 	for i := range 3 {
 		specular[i] *= finishMultS * conditionMultS
@@ -136,6 +150,23 @@ func conditionModifier(condition Condition) (specularMult, powerMult float64) {
 	}
 }
 
+// materialSeedFresnel returns Stage6 fresnel texture for material seed.
+func materialSeedFresnel(seed materialSeed) TextureRef {
+	if seed.fresnelGlass {
+		return NewProceduralFresnelGlass(
+			"ai",
+			64,
+			1,
+			1,
+			seed.fresnelA,
+			0,
+			false,
+		)
+	}
+
+	return NewProceduralFresnel("ai", 64, 1, 1, seed.fresnelA, seed.fresnelB)
+}
+
 // generateSuperStages builds baseline Super stage set.
 func generateSuperStages(seed materialSeed, specular [4]float64, power float64, opts GenerateOptions) []Stage {
 	stages := make([]Stage, 0, 7)
@@ -148,20 +179,7 @@ func generateSuperStages(seed materialSeed, specular [4]float64, power float64,
 		stages = append(stages, stage)
 	}
 
-	fresnel := NewProceduralFresnel("ai", 64, 1, 1, seed.fresnelA, seed.fresnelB)
-	if seed.fresnelGlass {
-		fresnel = NewProceduralFresnelGlass(
-			"ai",
-			64,
-			1,
-			1,
-			seed.fresnelA,
-			0,
-			false,
-		)
-	}
-
-	stage6 := Stage{Name: "Stage6", Texture: fresnel}
+	stage6 := Stage{Name: "Stage6", Texture: materialSeedFresnel(seed)}
 	applyStageUV(&stage6, opts.UseTexGen)
 	stages = append(stages, stage6)
 
@@ -266,12 +284,15 @@ func fallbackTextureForRole(role string, seed materialSeed, specular [4]float64,
 		return NewProceduralColor("argb", 8, 8, 3, 0.5, 0.5, 0.5, 0, "MC")
 	case "as":
 		ao := defaultASFromMaterialClass(seed.materialClass)
+		if seed.ambientShadow != nil {
+			ao = *seed.ambientShadow
+		}
 		return NewProceduralColor("argb", 8, 8, 3, ao, ao, ao, 1, "AS")
 	case "smdi":
 		specMean := (specular[0] + specular[1] + specular[2]) / 3
 		specRatio := clamp01(specMean / 0.35)
 		glossRatio := glossRatioFromSpecularPower(power)
-		gMin, gMax, bMin, bMax := defaultSMDIRangeFromMaterialClass(seed.materialClass)
+		gMin, gMax, bMin, bMax := seed.smdiRange()
 		g := gMin + (gMax-gMin)*specRatio
 		b := bMin + (bMax-bMin)*glossRatio
 		return NewProceduralColor("argb", 8, 8, 3, 1, clamp01(g), clamp01(b), 1, "SMDI")
@@ -330,6 +351,51 @@ func generateVariantWithMacro(base *Material, macroRaw string) (*Material, error
 	return out, nil
 }
 
+// generateDamageVariant clones base and applies damage variant definition.
+func generateDamageVariant(base *Material, v DamageVariant, texturePrefix string) (*Material, error) {
+	var out *Material
+	if raw := strings.TrimSpace(v.MacroTexture); raw != "" {
+		variant, err := generateVariantWithMacro(base, NormalizeGameTexturePath(raw))
+		if err != nil {
+			return nil, err
+		}
+		out = variant
+	} else {
+		if base == nil {
+			return nil, fmt.Errorf("generate variant: nil material: %w", ErrMaterialNotFound)
+		}
+		out = cloneMaterial(base)
+	}
+
+	for _, item := range [...]struct{ role, raw string }{{"as", v.ASTexture}, {"smdi", v.SMDITexture}} {
+		if strings.TrimSpace(item.raw) == "" {
+			continue
+		}
+		stageName, _ := stageNameForTextureRole(item.role)
+		stage := findMaterialStageByName(out, stageName)
+		if stage == nil {
+			return nil, fmt.Errorf("generate variant: %s stage not found: %w", item.role, ErrStageNotFound)
+		}
+
+		stage.Texture = ParseTextureRef(strings.TrimSpace(item.raw))
+		if stage.Texture.IsPath() {
+			stage.Texture = ParseTextureRef(withTexturePrefix(stage.Texture.Raw, texturePrefix))
+		}
+	}
+
+	if v.SpecularMult > 0 {
+		for i := 0; i < len(out.Specular) && i < 3; i++ {
+			out.Specular[i] *= v.SpecularMult
+		}
+	}
+	if v.PowerMult > 0 && out.SpecularPower != nil {
+		power := min(*out.SpecularPower*v.PowerMult, 1000)
+		out.SpecularPower = &power
+	}
+
+	return out, nil
+}
+
 // cloneMaterial deep-copies material for safe variant generation.
 func cloneMaterial(in *Material) *Material {
 	if in == nil {
@@ -402,6 +468,14 @@ func cloneTextureRef(in TextureRef) TextureRef {
 			irr := *in.Procedural.Irradiance
 			pt.Irradiance = &irr
 		}
+		if in.Procedural.PerlinNoise != nil {
+			noise := *in.Procedural.PerlinNoise
+			pt.PerlinNoise = &noise
+		}
+		if in.Procedural.TreeCrown != nil {
+			crown := *in.Procedural.TreeCrown
+			pt.TreeCrown = &crown
+		}
 		out.Procedural = &pt
 	}
 
@@ -483,6 +557,15 @@ func defaultSMDIRangeFromMaterialClass(class materialClass) (gMin, gMax, bMin, b
 	}
 }
 
+// smdiRange returns seed SMDI ranges, profile override first.
+func (seed materialSeed) smdiRange() (gMin, gMax, bMin, bMax float64) {
+	if seed.smdi != nil {
+		return seed.smdi[0], seed.smdi[1], seed.smdi[2], seed.smdi[3]
+	}
+
+	return defaultSMDIRangeFromMaterialClass(seed.materialClass)
+}
+
 // glossRatioFromSpecularPower maps rvmat specularPower to normalized gloss ratio.
 func glossRatioFromSpecularPower(power float64) float64 {
 	normalized := clamp01(power / 1000)
@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// materialNameHints maps lower-case name tokens to generator base materials.
+var materialNameHints = map[string]BaseMaterial{
+	"textile": BaseMaterialTextile, "cloth": BaseMaterialTextile, "fabric": BaseMaterialTextile,
+	"canvas": BaseMaterialTextile,
+	"steel":  BaseMaterialSteel, "metal": BaseMaterialSteel, "iron": BaseMaterialSteel,
+	"aluminium": BaseMaterialSteel, "aluminum": BaseMaterialSteel, "chrome": BaseMaterialSteel,
+	"rust": BaseMaterialRust, "rusty": BaseMaterialRust,
+	"wood": BaseMaterialWood, "wooden": BaseMaterialWood, "plank": BaseMaterialWood,
+	"glass": BaseMaterialGlass, "window": BaseMaterialGlass, "windshield": BaseMaterialGlass,
+	"plastic": BaseMaterialPlastic, "polymer": BaseMaterialPlastic,
+	"rubber": BaseMaterialRubber, "tire": BaseMaterialRubber, "tyre": BaseMaterialRubber,
+	"leather": BaseMaterialLeather,
+	"earth":   BaseMaterialEarth, "dirt": BaseMaterialEarth, "mud": BaseMaterialEarth, "soil": BaseMaterialEarth,
+	"paper": BaseMaterialPaper, "cardboard": BaseMaterialPaper,
+	"concrete": BaseMaterialConcrete, "cement": BaseMaterialConcrete,
+	"stone": BaseMaterialStone, "rock": BaseMaterialStone, "brick": BaseMaterialStone,
+	"skin": BaseMaterialSkin,
+}
+
+// planColorSuffixes lists color map suffixes recognized as group base texture.
+var planColorSuffixes = []string{"co", "ca"}
+
+// GeneratePlanOptions controls directory-wide generation planning.
+type GeneratePlanOptions struct {
+	// FileSystem is walked for textures and materials.
+	// Nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
+	// GameRoot is OS directory game paths start from (for example P:\);
+	// walked textures are made relative to it for BaseTexture. Empty value
+	// treats relative root as relative to game root; absolute root then
+	// requires GameRoot.
+	GameRoot string `json:"game_root,omitempty" yaml:"game_root,omitempty"`
+	// Defaults stores options applied to every planned entry;
+	// non-default BaseMaterial disables name hint inference.
+	Defaults GenerateSetOptions `json:"defaults" yaml:"defaults"`
+	// IncludeExisting also plans stems that already have .rvmat.
+	IncludeExisting bool `json:"include_existing,omitempty" yaml:"include_existing,omitempty"`
+}
+
+// GeneratePlanEntry is one planned GenerateSet for a texture stem group.
+type GeneratePlanEntry struct {
+	// Textures maps found role ("co", "ca", "nohq", "as", "smdi", ...) to file path.
+	Textures map[string]string `json:"textures,omitempty" yaml:"textures,omitempty"`
+	// Stem is group path without role suffix and extension.
+	Stem string `json:"stem" yaml:"stem"`
+	// Hint is name token that selected base material (empty when none matched).
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+	// Existing is existing material path (only with IncludeExisting).
+	Existing string `json:"existing,omitempty" yaml:"existing,omitempty"`
+	// Options is generator input for this group.
+	Options GenerateSetOptions `json:"options" yaml:"options"`
+}
+
+// GeneratePlanSkip is texture stem group left out of plan.
+type GeneratePlanSkip struct {
+	// Stem is group path without role suffix and extension.
+	Stem string `json:"stem" yaml:"stem"`
+	// Reason explains why group was skipped.
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// GeneratePlan is reviewable directory generation plan.
+type GeneratePlan struct {
+	// Root is walked directory.
+	Root string `json:"root" yaml:"root"`
+	// Entries lists planned groups sorted by stem.
+	Entries []GeneratePlanEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+	// Skipped lists groups without plan entry sorted by stem.
+	Skipped []GeneratePlanSkip `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// planGroup collects textures sharing one stem.
+type planGroup struct {
+	textures map[string]string
+	stem     string
+	material string
+}
+
+// PlanGenerateDirectory walks root recursively, groups textures by stem
+// and role suffix, and plans one GenerateSet per group without .rvmat.
+//
+// Groups need a color map (_co or _ca) used as BaseTexture; other roles
+// are discovered again by GenerateSet auto-fill. Base material is inferred
+// from name hints (e.g. "metal", "wood", "glass") in file stem first, then
+// in parent directories from nearest. BaseTexture is game path relative to
+// GameRoot, OutputPath and Textures stay OS paths. Nothing is written; run
+// the plan with GenerateFromManifest(plan.Manifest(), ...) and FileSystem
+// rooted at game root (for example NewIOFileSystem(os.DirFS(root))), so
+// auto-fill resolves game paths.
+func PlanGenerateDirectory(root string, opt *GeneratePlanOptions) (*GeneratePlan, error) {
+	o := GeneratePlanOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	fsys := fileSystemOrOS(o.FileSystem)
+	if strings.TrimSpace(o.GameRoot) == "" && filepath.IsAbs(root) {
+		return nil, fmt.Errorf("%w: absolute plan root %q needs game_root", ErrInvalidGenerateOption, root)
+	}
+
+	groups := map[string]*planGroup{}
+	if err := walkPlanDirectory(fsys, filepath.Clean(root), groups); err != nil {
+		return nil, fmt.Errorf("plan generate directory: %w", err)
+	}
+
+	plan := &GeneratePlan{Root: root}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[key]
+		if g.material != "" && !o.IncludeExisting {
+			plan.Skipped = append(plan.Skipped, GeneratePlanSkip{Stem: g.stem, Reason: "material exists: " + g.material})
+			continue
+		}
+
+		entry := GeneratePlanEntry{Stem: g.stem, Textures: g.textures, Existing: g.material}
+		opts := o.Defaults
+		if opts.BaseMaterial == BaseMaterialDefault && opts.Profile == "" {
+			opts.BaseMaterial, entry.Hint = inferBaseMaterialFromName(g.stem)
+		}
+		base := ""
+		for _, suffix := range colorSuffixPriorityForMaterial(opts.BaseMaterial) {
+			if raw := g.textures[strings.TrimPrefix(suffix, "_")]; raw != "" {
+				base = raw
+				break
+			}
+		}
+		if base == "" {
+			plan.Skipped = append(plan.Skipped, GeneratePlanSkip{Stem: g.stem, Reason: "no color texture (_co/_ca)"})
+			continue
+		}
+		gamePath, ok := planGamePath(base, o.GameRoot)
+		if !ok {
+			plan.Skipped = append(plan.Skipped, GeneratePlanSkip{Stem: g.stem, Reason: "outside game root: " + base})
+			continue
+		}
+		opts.BaseTexture = gamePath
+
+		opts.OutputPath = g.stem + ".rvmat"
+		entry.Options = opts
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	return plan, nil
+}
+
+// Manifest converts plan to generate manifest with one entry per stem.
+//
+// Output paths stay as walked (joined to Root), so manifest Path is empty.
+func (p *GeneratePlan) Manifest() *GenerateManifest {
+	m := &GenerateManifest{Materials: make([]GenerateManifestEntry, 0, len(p.Entries))}
+	for _, entry := range p.Entries {
+		m.Materials = append(m.Materials, GenerateManifestEntry{
+			Name:               filepath.ToSlash(entry.Stem),
+			GenerateSetOptions: entry.Options,
+		})
+	}
+
+	return m
+}
+
+// planGamePath converts walked file path to game path relative to gameRoot.
+//
+// ok is false when path is outside gameRoot.
+func planGamePath(path, gameRoot string) (string, bool) {
+	if strings.TrimSpace(gameRoot) != "" {
+		absRoot, err := filepath.Abs(gameRoot)
+		if err != nil {
+			return "", false
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", false
+		}
+		rel, err := filepath.Rel(absRoot, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		path = rel
+	}
+
+	return NormalizeGameTexturePath(path), true
+}
+
+// walkPlanDirectory indexes textures and materials under dir into groups.
+func walkPlanDirectory(fsys FileSystem, dir string, groups map[string]*planGroup) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		full := filepath.Join(dir, name)
+		if entry.IsDir() {
+			if err := walkPlanDirectory(fsys, full, groups); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext == ".rvmat" {
+			planGroupFor(groups, strings.TrimSuffix(full, filepath.Ext(name))).material = full
+			continue
+		}
+		if !hasAllowedExt(name) {
+			continue
+		}
+
+		stem, stemExt, ok := splitBaseTextureStem(full)
+		if !ok {
+			continue
+		}
+		raw := strings.TrimSuffix(strings.TrimSpace(full), stemExt)
+		role := textureRoleForSuffix(strings.TrimPrefix(strings.ToLower(raw[len(stem):]), "_"))
+		if role == "" {
+			continue
+		}
+
+		// Keep first file of role by extension priority.
+		g := planGroupFor(groups, stem)
+		if prev := g.textures[role]; prev == "" || textureExtensionRank(ext) < textureExtensionRank(filepath.Ext(prev)) {
+			g.textures[role] = full
+		}
+	}
+
+	return nil
+}
+
+// planGroupFor returns group for stem, creating it when missing.
+func planGroupFor(groups map[string]*planGroup, stem string) *planGroup {
+	key := strings.ToLower(stem)
+	g, ok := groups[key]
+	if !ok {
+		g = &planGroup{stem: stem, textures: map[string]string{}}
+		groups[key] = g
+	}
+
+	return g
+}
+
+// textureRoleForSuffix maps file name suffix to color or stage role key.
+func textureRoleForSuffix(suffix string) string {
+	if slices.Contains(planColorSuffixes, suffix) {
+		return suffix
+	}
+	for role, suffixes := range autoFillRoleSuffixes {
+		if slices.Contains(suffixes, suffix) {
+			return role
+		}
+	}
+
+	return ""
+}
+
+// textureExtensionRank returns extension priority index (lower wins).
+func textureExtensionRank(ext string) int {
+	if i := slices.Index(textureExtensionsByPriority(), strings.ToLower(ext)); i >= 0 {
+		return i
+	}
+
+	return len(textureExtensionsByPriority())
+}
+
+// inferBaseMaterialFromName picks base material from name hints in path.
+//
+// File stem tokens are checked first, then parent directories from nearest.
+func inferBaseMaterialFromName(path string) (BaseMaterial, string) {
+	parts := strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' || r == '\\' })
+	for i := len(parts) - 1; i >= 0; i-- {
+		tokens := strings.FieldsFunc(strings.ToLower(parts[i]), func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+		for _, token := range tokens {
+			if material, ok := materialNameHints[token]; ok {
+				return material, token
+			}
+		}
+	}
+
+	return BaseMaterialDefault, ""
+}
@@ -0,0 +1,126 @@
+package rvmat
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanGenerateDirectory(t *testing.T) {
+	fsys := NewMemFileSystem()
+	for _, name := range []string{
+		"data/metal/hatch_co.paa",
+		"data/metal/hatch_nohq.paa",
+		"data/metal/hatch_smdi.tga",
+		"data/metal/hatch_smdi.paa",
+		"data/crate_wood_co.png",
+		"data/windshield_ca.paa",
+		"data/windshield_co.paa",
+		"data/done_co.paa",
+		"data/done.rvmat",
+		"data/orphan_nohq.paa",
+		"data/readme.txt",
+	} {
+		fsys.AddFile(name, []byte("x"))
+	}
+
+	plan, err := PlanGenerateDirectory("data", &GeneratePlanOptions{
+		FileSystem: fsys,
+		Defaults:   GenerateSetOptions{Finish: FinishSatin},
+	})
+	if err != nil {
+		t.Fatalf("PlanGenerateDirectory: %v", err)
+	}
+	if len(plan.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", plan.Entries)
+	}
+
+	byStem := map[string]GeneratePlanEntry{}
+	for _, entry := range plan.Entries {
+		byStem[filepath.ToSlash(entry.Stem)] = entry
+	}
+
+	hatch := byStem["data/metal/hatch"]
+	if hatch.Options.BaseMaterial != BaseMaterialSteel || hatch.Hint != "metal" || hatch.Options.Finish != FinishSatin {
+		t.Fatalf("unexpected hatch entry: %+v", hatch)
+	}
+	if filepath.Ext(hatch.Textures["smdi"]) != ".paa" || hatch.Textures["nohq"] == "" {
+		t.Fatalf("expected paa smdi by extension priority: %v", hatch.Textures)
+	}
+	if hatch.Options.OutputPath != filepath.Join("data", "metal", "hatch.rvmat") {
+		t.Fatalf("unexpected output path %q", hatch.Options.OutputPath)
+	}
+
+	if crate := byStem["data/crate_wood"]; crate.Options.BaseMaterial != BaseMaterialWood {
+		t.Fatalf("expected wood hint from stem: %+v", crate)
+	}
+	if glass := byStem["data/windshield"]; !strings.HasSuffix(glass.Options.BaseTexture, "windshield_ca.paa") {
+		t.Fatalf("expected glass to prefer _ca: %+v", glass)
+	}
+
+	reasons := map[string]string{}
+	for _, skip := range plan.Skipped {
+		reasons[filepath.ToSlash(skip.Stem)] = skip.Reason
+	}
+	if !strings.HasPrefix(reasons["data/done"], "material exists") || !strings.HasPrefix(reasons["data/orphan"], "no color texture") {
+		t.Fatalf("unexpected skipped: %+v", plan.Skipped)
+	}
+
+	manifest := plan.Manifest()
+	data, err := FormatGenerateManifest(manifest)
+	if err != nil {
+		t.Fatalf("FormatGenerateManifest: %v", err)
+	}
+	if !strings.Contains(string(data), "base_material: steel") {
+		t.Fatalf("expected named enums in manifest yaml:\n%s", data)
+	}
+
+	parsed, err := ParseGenerateManifest(data)
+	if err != nil {
+		t.Fatalf("reparse manifest: %v\n%s", err, data)
+	}
+
+	res, err := GenerateFromManifest(parsed, &GenerateManifestOptions{FileSystem: fsys, Output: fsys})
+	if err != nil {
+		t.Fatalf("GenerateFromManifest: %v", err)
+	}
+	if res.StageResolutions["data/metal/hatch"]["Stage1"].Source != StageTextureSourceAutoFill {
+		t.Fatalf("expected auto-filled hatch normal map: %+v", res.StageResolutions["data/metal/hatch"])
+	}
+	if _, err := fsys.ReadFile(filepath.Join("data", "metal", "hatch.rvmat")); err != nil {
+		t.Fatalf("expected written hatch material: %v", err)
+	}
+}
+
+func TestPlanGenerateDirectoryGameRoot(t *testing.T) {
+	gameRoot := filepath.Join(t.TempDir(), "work")
+	root := filepath.Join(gameRoot, "mod", "data")
+	fsys := NewMemFileSystem()
+	fsys.AddFile(filepath.Join(root, "hatch_co.paa"), []byte("x"))
+
+	if _, err := PlanGenerateDirectory(root, &GeneratePlanOptions{FileSystem: fsys}); !errors.Is(err, ErrInvalidGenerateOption) {
+		t.Fatalf("expected game root error for absolute root, got %v", err)
+	}
+
+	plan, err := PlanGenerateDirectory(root, &GeneratePlanOptions{FileSystem: fsys, GameRoot: gameRoot})
+	if err != nil {
+		t.Fatalf("PlanGenerateDirectory: %v", err)
+	}
+	if len(plan.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", plan)
+	}
+
+	opts := plan.Entries[0].Options
+	if opts.BaseTexture != `mod\data\hatch_co.paa` || opts.OutputPath != filepath.Join(root, "hatch.rvmat") {
+		t.Fatalf("unexpected planned paths: %q %q", opts.BaseTexture, opts.OutputPath)
+	}
+
+	other, err := PlanGenerateDirectory(root, &GeneratePlanOptions{FileSystem: fsys, GameRoot: filepath.Join(gameRoot, "other")})
+	if err != nil {
+		t.Fatalf("PlanGenerateDirectory: %v", err)
+	}
+	if len(other.Entries) != 0 || len(other.Skipped) != 1 || !strings.HasPrefix(other.Skipped[0].Reason, "outside game root") {
+		t.Fatalf("expected texture outside game root to be skipped: %+v", other)
+	}
+}
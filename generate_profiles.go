@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// profileRegistry stores registered custom material profiles by lower-case name.
+var profileRegistry = struct {
+	seeds    map[string]materialSeed
+	profiles map[string]MaterialProfile
+	mu       sync.RWMutex
+}{
+	seeds:    map[string]materialSeed{},
+	profiles: map[string]MaterialProfile{},
+}
+
+// MaterialModifier is finish or condition multiplier pair.
+type MaterialModifier struct {
+	// Specular multiplies specular RGB.
+	Specular float64 `json:"specular" yaml:"specular"`
+	// Power multiplies specularPower.
+	Power float64 `json:"power" yaml:"power"`
+}
+
+// MaterialProfile is user-defined generator base material profile.
+//
+// Unset fields are inherited from Extends profile (built-in name such as
+// "steel" or earlier registered profile; default built-in profile when
+// empty). SMDI ranges are [min, max] in 0..1 used by procedural _smdi
+// fallback and texture synthesis.
+type MaterialProfile struct {
+	// SpecularPower is baseline specularPower.
+	SpecularPower *float64 `json:"specular_power,omitempty" yaml:"specular_power,omitempty"`
+	// FresnelN is Stage6 fresnel refractive index (fresnelGlass index for glass).
+	FresnelN *float64 `json:"fresnel_n,omitempty" yaml:"fresnel_n,omitempty"`
+	// FresnelK is Stage6 fresnel extinction coefficient.
+	FresnelK *float64 `json:"fresnel_k,omitempty" yaml:"fresnel_k,omitempty"`
+	// FresnelGlass switches Stage6 to fresnelGlass.
+	FresnelGlass *bool `json:"fresnel_glass,omitempty" yaml:"fresnel_glass,omitempty"`
+	// AmbientShadow is procedural _as fallback intensity.
+	AmbientShadow *float64 `json:"ambient_shadow,omitempty" yaml:"ambient_shadow,omitempty"`
+	// Finishes overrides finish multipliers by finish name.
+	Finishes map[Finish]MaterialModifier `json:"finishes,omitempty" yaml:"finishes,omitempty"`
+	// Conditions overrides condition multipliers by condition name.
+	Conditions map[Condition]MaterialModifier `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// ConditionEffects overrides condition stage effects by condition name.
+	ConditionEffects map[Condition]ConditionEffect `json:"condition_effects,omitempty" yaml:"condition_effects,omitempty"`
+	// Name is profile name referenced by GenerateOptions.Profile.
+	Name string `json:"name" yaml:"name"`
+	// Extends is parent profile name.
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
+	// Specular is baseline specular RGB or RGBA.
+	Specular []float64 `json:"specular,omitempty" yaml:"specular,omitempty"`
+	// Emissive is baseline emissive RGB or RGBA.
+	Emissive []float64 `json:"emissive,omitempty" yaml:"emissive,omitempty"`
+	// SMDISpecular is SMDI green range.
+	SMDISpecular []float64 `json:"smdi_specular,omitempty" yaml:"smdi_specular,omitempty"`
+	// SMDIGloss is SMDI blue range.
+	SMDIGloss []float64 `json:"smdi_gloss,omitempty" yaml:"smdi_gloss,omitempty"`
+}
+
+// materialProfileFile is YAML document with profile list.
+type materialProfileFile struct {
+	Profiles []MaterialProfile `json:"profiles" yaml:"profiles"`
+}
+
+// RegisterMaterialProfile validates profile, resolves its parent, and makes
+// it available by name to GenerateOptions.Profile.
+//
+// Names are case-insensitive and cannot shadow built-in base materials or
+// already registered profiles.
+func RegisterMaterialProfile(p MaterialProfile) error {
+	profileRegistry.mu.Lock()
+	defer profileRegistry.mu.Unlock()
+
+	name := strings.ToLower(strings.TrimSpace(p.Name))
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidMaterialProfile)
+	}
+	if _, ok := builtinMaterialByName(name); ok {
+		return fmt.Errorf("%w: %q is built-in base material", ErrInvalidMaterialProfile, p.Name)
+	}
+	if _, ok := profileRegistry.seeds[name]; ok {
+		return fmt.Errorf("%w: %q already registered", ErrInvalidMaterialProfile, p.Name)
+	}
+
+	parent, err := lookupMaterialSeedLocked(p.Extends)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidMaterialProfile, p.Name, err)
+	}
+
+	seed, err := p.apply(parent)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidMaterialProfile, p.Name, err)
+	}
+
+	profileRegistry.seeds[name] = seed
+	profileRegistry.profiles[name] = p
+	return nil
+}
+
+// UnregisterMaterialProfile removes registered custom profile by name.
+func UnregisterMaterialProfile(name string) {
+	profileRegistry.mu.Lock()
+	defer profileRegistry.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	delete(profileRegistry.seeds, key)
+	delete(profileRegistry.profiles, key)
+}
+
+// MaterialProfileNames returns built-in and registered profile names sorted.
+func MaterialProfileNames() []string {
+	profileRegistry.mu.RLock()
+	defer profileRegistry.mu.RUnlock()
+
+	names := slices.Collect(maps.Keys(profileRegistry.profiles))
+	for material := range materialCatalog {
+		names = append(names, material.String())
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// ParseMaterialProfiles parses YAML document with top-level "profiles" list.
+//
+// Unknown keys are rejected. Profiles are not registered.
+func ParseMaterialProfiles(data []byte) ([]MaterialProfile, error) {
+	var file materialProfileFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMaterialProfile, err)
+	}
+
+	return file.Profiles, nil
+}
+
+// LoadMaterialProfiles reads profile YAML file and registers profiles in order,
+// so later profiles can extend earlier ones.
+//
+// Nil fsys uses OS filesystem.
+func LoadMaterialProfiles(fsys FileSystem, path string) ([]MaterialProfile, error) {
+	data, err := fileSystemOrOS(fsys).ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read material profiles: %w", err)
+	}
+
+	profiles, err := ParseMaterialProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, p := range profiles {
+		if err := RegisterMaterialProfile(p); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return profiles, nil
+}
+
+// resolveMaterialSeed returns generator seed for profile name or base material.
+//
+// Non-empty profile name has priority over base material.
+func resolveMaterialSeed(profile string, material BaseMaterial) (materialSeed, error) {
+	if strings.TrimSpace(profile) != "" {
+		profileRegistry.mu.RLock()
+		defer profileRegistry.mu.RUnlock()
+
+		seed, err := lookupMaterialSeedLocked(profile)
+		if err != nil {
+			return materialSeed{}, fmt.Errorf("%w profile=%q", ErrUnknownBaseMaterial, profile)
+		}
+		return seed, nil
+	}
+
+	baseMaterial, err := normalizeBaseMaterial(material)
+	if err != nil {
+		return materialSeed{}, err
+	}
+
+	seed, ok := materialCatalog[baseMaterial]
+	if !ok {
+		return materialSeed{}, fmt.Errorf("unknown base material %s: %w", baseMaterial, ErrUnknownBaseMaterial)
+	}
+
+	return seed, nil
+}
+
+// lookupMaterialSeedLocked finds built-in or registered seed by name.
+//
+// Empty name returns default built-in profile. Caller holds registry lock.
+func lookupMaterialSeedLocked(name string) (materialSeed, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if material, ok := builtinMaterialByName(key); ok {
+		material, _ = normalizeBaseMaterial(material)
+		return materialCatalog[material], nil
+	}
+	if seed, ok := profileRegistry.seeds[key]; ok {
+		return seed, nil
+	}
+
+	return materialSeed{}, fmt.Errorf("unknown profile %q", name)
+}
+
+// builtinMaterialByName returns built-in base material by name ("" is default).
+func builtinMaterialByName(name string) (BaseMaterial, bool) {
+	for material := BaseMaterialDefault; material <= BaseMaterialSkin; material++ {
+		if name == "" && material == BaseMaterialDefault || name == material.String() {
+			return material, true
+		}
+	}
+
+	return 0, false
+}
+
+// apply overlays profile values on parent seed.
+func (p MaterialProfile) apply(seed materialSeed) (materialSeed, error) {
+	if p.Specular != nil {
+		if err := profileColor(&seed.specular, p.Specular, "specular"); err != nil {
+			return seed, err
+		}
+	}
+	if p.Emissive != nil {
+		if err := profileColor(&seed.emissive, p.Emissive, "emissive"); err != nil {
+			return seed, err
+		}
+	}
+	if p.SpecularPower != nil {
+		if *p.SpecularPower <= 0 {
+			return seed, fmt.Errorf("specular_power %g must be > 0", *p.SpecularPower)
+		}
+		seed.specularPower = *p.SpecularPower
+	}
+	if p.FresnelN != nil {
+		seed.fresnelA = *p.FresnelN
+	}
+	if p.FresnelK != nil {
+		seed.fresnelB = *p.FresnelK
+	}
+	if p.FresnelGlass != nil {
+		seed.fresnelGlass = *p.FresnelGlass
+	}
+	if seed.fresnelA <= 0 || seed.fresnelB < 0 {
+		return seed, fmt.Errorf("fresnel n=%g k=%g out of range", seed.fresnelA, seed.fresnelB)
+	}
+
+	if p.AmbientShadow != nil {
+		if *p.AmbientShadow < 0 || *p.AmbientShadow > 1 {
+			return seed, fmt.Errorf("ambient_shadow %g out of 0..1", *p.AmbientShadow)
+		}
+		ao := *p.AmbientShadow
+		seed.ambientShadow = &ao
+	}
+
+	gMin, gMax, bMin, bMax := seed.smdiRange()
+	if p.SMDISpecular != nil || p.SMDIGloss != nil {
+		var err error
+		if gMin, gMax, err = profileRange(p.SMDISpecular, gMin, gMax, "smdi_specular"); err != nil {
+			return seed, err
+		}
+		if bMin, bMax, err = profileRange(p.SMDIGloss, bMin, bMax, "smdi_gloss"); err != nil {
+			return seed, err
+		}
+		seed.smdi = &[4]float64{gMin, gMax, bMin, bMax}
+	}
+
+	if len(p.Finishes) > 0 {
+		finishes := maps.Clone(seed.finishes)
+		if finishes == nil {
+			finishes = map[Finish]MaterialModifier{}
+		}
+		for finish, mod := range p.Finishes {
+			normalized, err := normalizeFinish(finish)
+			if err != nil {
+				return seed, err
+			}
+			if mod.Specular <= 0 || mod.Power <= 0 {
+				return seed, fmt.Errorf("finish %s multipliers must be > 0", finish)
+			}
+			finishes[normalized] = mod
+		}
+		seed.finishes = finishes
+	}
+
+	if len(p.Conditions) > 0 {
+		conditions := maps.Clone(seed.conditions)
+		if conditions == nil {
+			conditions = map[Condition]MaterialModifier{}
+		}
+		for condition, mod := range p.Conditions {
+			normalized, err := normalizeCondition(condition)
+			if err != nil {
+				return seed, err
+			}
+			if mod.Specular <= 0 || mod.Power <= 0 {
+				return seed, fmt.Errorf("condition %s multipliers must be > 0", condition)
+			}
+			conditions[normalized] = mod
+		}
+		seed.conditions = conditions
+	}
+
+	if len(p.ConditionEffects) > 0 {
+		effects := maps.Clone(seed.conditionEffects)
+		if effects == nil {
+			effects = map[Condition]ConditionEffect{}
+		}
+		for condition, effect := range p.ConditionEffects {
+			normalized, err := normalizeCondition(condition)
+			if err != nil {
+				return seed, err
+			}
+			if err := validateConditionEffect(effect); err != nil {
+				return seed, fmt.Errorf("condition %s effect: %w", condition, err)
+			}
+			effects[normalized] = effect
+		}
+		seed.conditionEffects = effects
+	}
+
+	return seed, nil
+}
+
+// profileColor copies RGB or RGBA profile color into seed color.
+func profileColor(dst *[4]float64, values []float64, field string) error {
+	if len(values) != 3 && len(values) != 4 {
+		return fmt.Errorf("%s needs 3 or 4 values, got %d", field, len(values))
+	}
+	for _, v := range values {
+		if v < 0 {
+			return fmt.Errorf("%s has negative value %g", field, v)
+		}
+	}
+
+	copy(dst[:], values)
+	return nil
+}
+
+// profileRange validates optional [min, max] range or keeps inherited one.
+func profileRange(values []float64, lo, hi float64, field string) (float64, float64, error) {
+	if values == nil {
+		return lo, hi, nil
+	}
+	if len(values) != 2 || values[0] < 0 || values[1] > 1 || values[0] > values[1] {
+		return 0, 0, fmt.Errorf("%s must be [min, max] within 0..1", field)
+	}
+
+	return values[0], values[1], nil
+}
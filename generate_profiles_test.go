@@ -0,0 +1,151 @@
+package rvmat
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegisterMaterialProfile(t *testing.T) {
+	power := 120.0
+	ao := 0.5
+	err := RegisterMaterialProfile(MaterialProfile{
+		Name:          "Painted_Steel",
+		Extends:       "steel",
+		SpecularPower: &power,
+		AmbientShadow: &ao,
+		SMDISpecular:  []float64{0.2, 0.2},
+		Finishes:      map[Finish]MaterialModifier{FinishGloss: {Specular: 1, Power: 2}},
+	})
+	if err != nil {
+		t.Fatalf("register profile: %v", err)
+	}
+	t.Cleanup(func() { UnregisterMaterialProfile("painted_steel") })
+
+	if !slices.Contains(MaterialProfileNames(), "painted_steel") {
+		t.Fatalf("expected painted_steel in %v", MaterialProfileNames())
+	}
+
+	mat, err := Generate(GenerateOptions{Profile: "painted_steel", Finish: FinishGloss})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if mat.SpecularPower == nil || *mat.SpecularPower != 240 {
+		t.Fatalf("expected profile finish power 240, got %v", mat.SpecularPower)
+	}
+
+	steel, err := Generate(GenerateOptions{BaseMaterial: BaseMaterialSteel})
+	if err != nil {
+		t.Fatalf("generate steel: %v", err)
+	}
+	if mat.Specular[0] != steel.Specular[0] {
+		t.Fatalf("expected specular inherited from steel: %v vs %v", mat.Specular, steel.Specular)
+	}
+	if findMaterialStageByName(mat, "Stage6").Texture.Raw != findMaterialStageByName(steel, "Stage6").Texture.Raw {
+		t.Fatalf("expected fresnel inherited from steel")
+	}
+
+	as := findMaterialStageByName(mat, "Stage4").Texture.Procedural.Color
+	if as == nil || as.G != 0.5 {
+		t.Fatalf("expected profile AS 0.5, got %+v", as)
+	}
+	smdi := findMaterialStageByName(mat, "Stage5").Texture.Procedural.Color
+	if smdi == nil || smdi.G != 0.2 {
+		t.Fatalf("expected fixed SMDI G 0.2, got %+v", smdi)
+	}
+
+	// Built-in condition table is kept when profile does not override it.
+	worn, err := Generate(GenerateOptions{Profile: "painted_steel", Condition: ConditionWorn})
+	if err != nil {
+		t.Fatalf("generate worn: %v", err)
+	}
+	if *worn.SpecularPower != 96 {
+		t.Fatalf("expected built-in worn multiplier, got %v", *worn.SpecularPower)
+	}
+}
+
+func TestRegisterMaterialProfileErrors(t *testing.T) {
+	badPower := 0.0
+	tests := []struct {
+		name    string
+		profile MaterialProfile
+	}{
+		{name: "empty name", profile: MaterialProfile{}},
+		{name: "built-in name", profile: MaterialProfile{Name: "Steel"}},
+		{name: "unknown parent", profile: MaterialProfile{Name: "x", Extends: "missing"}},
+		{name: "bad power", profile: MaterialProfile{Name: "x", SpecularPower: &badPower}},
+		{name: "bad color", profile: MaterialProfile{Name: "x", Specular: []float64{1, 1}}},
+		{name: "bad range", profile: MaterialProfile{Name: "x", SMDIGloss: []float64{0.8, 0.2}}},
+		{name: "bad modifier", profile: MaterialProfile{
+			Name:       "x",
+			Conditions: map[Condition]MaterialModifier{ConditionDirty: {Specular: 1}},
+		}},
+		{name: "bad condition effect", profile: MaterialProfile{
+			Name:             "x",
+			ConditionEffects: map[Condition]ConditionEffect{ConditionDirty: {ASDarken: 2}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterMaterialProfile(tt.profile); !errors.Is(err, ErrInvalidMaterialProfile) {
+				t.Fatalf("expected ErrInvalidMaterialProfile, got %v", err)
+			}
+		})
+	}
+
+	if _, err := Generate(GenerateOptions{Profile: "missing"}); !errors.Is(err, ErrUnknownBaseMaterial) {
+		t.Fatalf("expected ErrUnknownBaseMaterial, got %v", err)
+	}
+}
+
+func TestLoadMaterialProfiles(t *testing.T) {
+	fsys := NewIOFileSystem(fstest.MapFS{
+		"profiles.yaml": {Data: []byte(`
+profiles:
+  - name: ceramic
+    extends: plastic
+    specular: [0.3, 0.3, 0.3]
+    specular_power: 300
+    fresnel_n: 1.5
+    conditions:
+      dirty: {specular: 0.5, power: 0.25}
+    condition_effects:
+      dirty: {macro_texture: 'my\data\grime_mc.paa'}
+  - name: ceramic_tile
+    extends: ceramic
+    smdi_gloss: [0.4, 0.9]
+`)},
+	})
+
+	profiles, err := LoadMaterialProfiles(fsys, "profiles.yaml")
+	if err != nil {
+		t.Fatalf("load profiles: %v", err)
+	}
+	t.Cleanup(func() {
+		UnregisterMaterialProfile("ceramic")
+		UnregisterMaterialProfile("ceramic_tile")
+	})
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(profiles))
+	}
+
+	res, err := GenerateSet(GenerateSetOptions{Profile: "ceramic_tile", Condition: ConditionDirty})
+	if err != nil {
+		t.Fatalf("generate set: %v", err)
+	}
+	if *res.Main.SpecularPower != 75 {
+		t.Fatalf("expected inherited dirty multiplier power 75, got %v", *res.Main.SpecularPower)
+	}
+	if res.Main.Specular[0] != 0.15 {
+		t.Fatalf("expected specular 0.15, got %v", res.Main.Specular)
+	}
+	if r := res.StageResolutions["Stage3"]; r.Source != StageTextureSourceCondition || r.Texture.Raw != `my\data\grime_mc.paa` {
+		t.Fatalf("expected inherited dirty macro swap, got %+v", r)
+	}
+
+	if _, err := ParseMaterialProfiles([]byte("profiles:\n  - name: a\n    shine: 1\n")); !errors.Is(err, ErrInvalidMaterialProfile) {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
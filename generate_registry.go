@@ -22,13 +22,18 @@ const (
 
 // materialSeed defines generation seed values.
 type materialSeed struct {
-	specular      [4]float64
-	emissive      [4]float64
-	specularPower float64
-	fresnelA      float64
-	fresnelB      float64
-	materialClass materialClass
-	fresnelGlass  bool
+	finishes         map[Finish]MaterialModifier
+	conditions       map[Condition]MaterialModifier
+	conditionEffects map[Condition]ConditionEffect
+	ambientShadow    *float64
+	smdi             *[4]float64
+	specular         [4]float64
+	emissive         [4]float64
+	specularPower    float64
+	fresnelA         float64
+	fresnelB         float64
+	materialClass    materialClass
+	fresnelGlass     bool
 }
 
 // materialCatalog stores baseline generator material families.
@@ -5,9 +5,10 @@
 package rvmat
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -28,7 +29,7 @@ func GenerateSet(opts GenerateSetOptions) (*GenerateSetResult, error) {
 	baseTexture := strings.TrimSpace(opts.BaseTexture)
 	var discoveredBaseDirIndex map[string]string
 	if baseTexture == "" {
-		baseTexture, discoveredBaseDirIndex = discoverBaseTextureFromOutput(mainOutputPath, opts.BaseMaterial)
+		baseTexture, discoveredBaseDirIndex = discoverBaseTextureFromOutput(opts.FileSystem, mainOutputPath, opts.BaseMaterial)
 	}
 
 	// normalize stage texture overrides
@@ -52,7 +53,7 @@ func GenerateSet(opts GenerateSetOptions) (*GenerateSetResult, error) {
 			autoFillDirIndex = discoveredBaseDirIndex
 		}
 
-		for role, raw := range resolveAutoFillBySeed(seed, autoFillDirIndex) {
+		for role, raw := range resolveAutoFillBySeed(opts.FileSystem, seed, autoFillDirIndex) {
 			if hasExplicitRoleOrStage(overrides, role) {
 				continue
 			}
@@ -72,12 +73,14 @@ func GenerateSet(opts GenerateSetOptions) (*GenerateSetResult, error) {
 	normalizedOverrides := normalizeTexturePathMap(overrides)
 
 	// generate main material
-	main, err := Generate(GenerateOptions{
+	main, effects, err := generateMaterial(GenerateOptions{
 		TextureOverrides:  normalizedOverrides,
 		BaseTexture:       baseTextureForDerive,
 		EmissiveIntensity: opts.EmissiveIntensity,
+		Profile:           opts.Profile,
 		BaseMaterial:      opts.BaseMaterial,
 		Condition:         opts.Condition,
+		ConditionEffect:   opts.ConditionEffect,
 		Finish:            opts.Finish,
 		UseTexGen:         useTexGen,
 	})
@@ -85,47 +88,92 @@ func GenerateSet(opts GenerateSetOptions) (*GenerateSetResult, error) {
 		return nil, fmt.Errorf("generate rvmat: %w", err)
 	}
 
+	// synthesize images for procedural fallback stages
+	synthesized := synthesizeStageTextures(opts, main, explicitSource, baseTexture, mainOutputPath)
+	synthesizedRoles := map[string]string{}
+	for _, tex := range synthesized {
+		synthesizedRoles[tex.Role] = tex.Texture
+	}
+
 	applyTexturePrefixToMaterial(main, opts.TexturePrefix)
 
 	// build result
 	result := &GenerateSetResult{
-		Main:               main,
-		MainOutputPath:     mainOutputPath,
-		StageResolutions:   resolveStageResolution(main, explicitSource, autoFilled, baseTextureForDerive),
-		DamageOutputPath:   "",
-		DestructOutputPath: "",
-	}
-
-	generateDamage := opts.GenerateDamage || !opts.DisableDamage
-	generateDestruct := opts.GenerateDestruct || !opts.DisableDestruct
-
-	// generate damage variant
-	if generateDamage {
-		damageMacro := resolveMacroTexture(opts.DamageMacroTexture, DefaultDamageMacroTexture)
-		damage, derr := generateVariantWithMacro(main, damageMacro)
-		if derr != nil {
-			return nil, fmt.Errorf("generate rvmat damage: %w", derr)
-		}
+		Main:                main,
+		MainOutputPath:      mainOutputPath,
+		StageResolutions:    resolveStageResolution(main, explicitSource, autoFilled, synthesizedRoles, baseTextureForDerive, effects),
+		SynthesizedTextures: synthesized,
+		DamageOutputPath:    "",
+		DestructOutputPath:  "",
+	}
 
-		result.Damage = damage
-		result.DamageOutputPath = outputPathWithSuffix(result.MainOutputPath, "_damage")
+	variants, err := resolveDamageVariants(opts)
+	if err != nil {
+		return nil, fmt.Errorf("generate rvmat: %w", err)
 	}
 
-	// generate destruct variant
-	if generateDestruct {
-		destructMacro := resolveMacroTexture(opts.DestructMacroTexture, DefaultDestructMacroTexture)
-		destruct, derr := generateVariantWithMacro(main, destructMacro)
-		if derr != nil {
-			return nil, fmt.Errorf("generate rvmat destruct: %w", derr)
+	// generate damage level variants
+	for _, v := range variants {
+		variant, verr := generateDamageVariant(main, v, opts.TexturePrefix)
+		if verr != nil {
+			return nil, fmt.Errorf("generate rvmat %s: %w", strings.TrimPrefix(v.Suffix, "_"), verr)
 		}
 
-		result.Destruct = destruct
-		result.DestructOutputPath = outputPathWithSuffix(result.MainOutputPath, "_destruct")
+		path := outputPathWithSuffix(result.MainOutputPath, v.Suffix)
+		switch {
+		case strings.EqualFold(v.Suffix, DamageSuffixDamage):
+			result.Damage, result.DamageOutputPath = variant, path
+		case strings.EqualFold(v.Suffix, DamageSuffixDestruct):
+			result.Destruct, result.DestructOutputPath = variant, path
+		default:
+			result.Variants = append(result.Variants, GeneratedVariant{Suffix: v.Suffix, OutputPath: path, Material: variant})
+		}
 	}
 
 	return result, nil
 }
 
+// resolveDamageVariants merges built-in damage/destruct and custom variant definitions.
+func resolveDamageVariants(opts GenerateSetOptions) ([]DamageVariant, error) {
+	var out []DamageVariant
+	if opts.GenerateDamage || !opts.DisableDamage {
+		out = append(out, DamageVariant{
+			Suffix:       DamageSuffixDamage,
+			MacroTexture: resolveMacroTexture(opts.DamageMacroTexture, DefaultDamageMacroTexture),
+		})
+	}
+	if opts.GenerateDestruct || !opts.DisableDestruct {
+		out = append(out, DamageVariant{
+			Suffix:       DamageSuffixDestruct,
+			MacroTexture: resolveMacroTexture(opts.DestructMacroTexture, DefaultDestructMacroTexture),
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, v := range opts.Variants {
+		v.Suffix = strings.TrimSpace(v.Suffix)
+		key := strings.ToLower(v.Suffix)
+		switch {
+		case v.Suffix == "":
+			return nil, errors.New("damage variant: empty suffix")
+		case seen[key]:
+			return nil, fmt.Errorf("damage variant: duplicate suffix %q", v.Suffix)
+		case v.SpecularMult < 0 || v.PowerMult < 0:
+			return nil, fmt.Errorf("damage variant %q: negative multiplier", v.Suffix)
+		}
+		seen[key] = true
+
+		i := slices.IndexFunc(out, func(d DamageVariant) bool { return strings.EqualFold(d.Suffix, v.Suffix) })
+		if i >= 0 {
+			out[i] = v
+			continue
+		}
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
 // normalizeTextureAutoFillMode validates mode and applies force-procedural override.
 func normalizeTextureAutoFillMode(mode TextureAutoFillMode, forceProcedural bool, baseTexture string, overrides map[string]string) (TextureAutoFillMode, error) {
 	if forceProcedural {
@@ -212,7 +260,11 @@ func normalizeOutputPath(path string) string {
 }
 
 // discoverBaseTextureFromOutput searches sibling base texture from output stem.
-func discoverBaseTextureFromOutput(outputPath string, baseMaterial BaseMaterial) (string, map[string]string) {
+func discoverBaseTextureFromOutput(
+	fsys FileSystem,
+	outputPath string,
+	baseMaterial BaseMaterial,
+) (string, map[string]string) {
 	if strings.TrimSpace(outputPath) == "" {
 		return "", nil
 	}
@@ -228,7 +280,7 @@ func discoverBaseTextureFromOutput(outputPath string, baseMaterial BaseMaterial)
 		baseStem = normalizedStem
 	}
 
-	dirLowerNameIndex := buildDirLowerNameIndex(filepath.Dir(baseStem))
+	dirLowerNameIndex := buildDirLowerNameIndex(fsys, filepath.Dir(baseStem))
 	for _, suffix := range colorSuffixPriorityForMaterial(baseMaterial) {
 		if suffix == "" {
 			continue
@@ -245,13 +297,19 @@ func discoverBaseTextureFromOutput(outputPath string, baseMaterial BaseMaterial)
 }
 
 // resolveAutoFillBySeed resolves existing sibling textures by suffix priorities.
-func resolveAutoFillBySeed(seedRaw string, dirLowerNameIndex map[string]string) map[string]string {
+func resolveAutoFillBySeed(
+	fsys FileSystem,
+	seedRaw string,
+	dirLowerNameIndex map[string]string,
+) map[string]string {
 	out := map[string]string{}
 	stem, _, ok := splitBaseTextureStem(seedRaw)
 	if !ok {
 		return out
 	}
 
+	// Game-style seeds use backslashes; look them up as OS paths.
+	stem = normalizeOSPath(stem)
 	dir := filepath.Dir(stem)
 	base := filepath.Base(stem)
 	if strings.TrimSpace(base) == "" || base == "." {
@@ -259,7 +317,7 @@ func resolveAutoFillBySeed(seedRaw string, dirLowerNameIndex map[string]string)
 	}
 
 	if len(dirLowerNameIndex) == 0 {
-		dirLowerNameIndex = buildDirLowerNameIndex(dir)
+		dirLowerNameIndex = buildDirLowerNameIndex(fsys, dir)
 	}
 
 	extensions := textureExtensionsByPriority()
@@ -306,13 +364,13 @@ func pickExistingTextureByPriorityFromIndex(baseWithoutExt string, dirLowerNameI
 }
 
 // buildDirLowerNameIndex returns lower-case filename to original name map.
-func buildDirLowerNameIndex(dir string) map[string]string {
+func buildDirLowerNameIndex(fsys FileSystem, dir string) map[string]string {
 	cleanDir := filepath.Clean(strings.TrimSpace(dir))
 	if cleanDir == "" {
 		cleanDir = "."
 	}
 
-	entries, err := os.ReadDir(cleanDir)
+	entries, err := fileSystemOrOS(fsys).ReadDir(cleanDir)
 	if err != nil {
 		return nil
 	}
@@ -345,7 +403,14 @@ func hasExplicitRoleOrStage(overrides map[string]string, role string) bool {
 }
 
 // resolveStageResolution builds stage texture source report for generated material.
-func resolveStageResolution(m *Material, explicit map[string]StageTextureSource, autoFilled map[string]string, baseTexture string) map[string]GenerateStageResolution {
+func resolveStageResolution(
+	m *Material,
+	explicit map[string]StageTextureSource,
+	autoFilled map[string]string,
+	synthesized map[string]string,
+	baseTexture string,
+	effects map[string][]string,
+) map[string]GenerateStageResolution {
 	out := map[string]GenerateStageResolution{}
 	if m == nil {
 		return out
@@ -363,6 +428,12 @@ func resolveStageResolution(m *Material, explicit map[string]StageTextureSource,
 		case role != "" && strings.TrimSpace(autoFilled[role]) != "":
 			source = StageTextureSourceAutoFill
 
+		case role != "" && strings.TrimSpace(synthesized[role]) != "":
+			source = StageTextureSourceSynthesized
+
+		case slices.ContainsFunc(effects[stage.Name], isConditionTextureEffect):
+			source = StageTextureSourceCondition
+
 		case role != "" && role != "env" && strings.TrimSpace(baseTexture) != "" && stage.Texture.IsPath():
 			source = StageTextureSourceDerived
 
@@ -377,6 +448,7 @@ func resolveStageResolution(m *Material, explicit map[string]StageTextureSource,
 			Role:    role,
 			Source:  source,
 			Texture: stage.Texture,
+			Effects: effects[stage.Name],
 		}
 	}
 
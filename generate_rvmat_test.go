@@ -1,6 +1,11 @@
 package rvmat
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -506,6 +511,233 @@ func TestGenerateSetGenerateFlagsOverrideDisableFlags(t *testing.T) {
 	}
 }
 
+func TestGenerateSetDamageVariants(t *testing.T) {
+	variants := DefaultDamageVariants()
+	variants[2].SMDITexture = `my\data\box_badly_smdi.paa`
+	result, err := GenerateSet(GenerateSetOptions{
+		OutputPath:    `out\box.rvmat`,
+		TexturePrefix: `mod`,
+		Variants:      variants,
+	})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+
+	if result.DamageOutputPath != `out\box_damage.rvmat` || result.DestructOutputPath != `out\box_destruct.rvmat` {
+		t.Fatalf("unexpected damage/destruct paths: %q %q", result.DamageOutputPath, result.DestructOutputPath)
+	}
+	if len(result.Variants) != 2 {
+		t.Fatalf("expected worn and badly damaged variants, got %d", len(result.Variants))
+	}
+
+	worn, wornPath, ok := result.Variant(DamageSuffixWorn)
+	if !ok || wornPath != `out\box_worn.rvmat` {
+		t.Fatalf("unexpected worn variant path %q", wornPath)
+	}
+	if worn.Specular[0] >= result.Main.Specular[0] || *worn.SpecularPower >= *result.Main.SpecularPower {
+		t.Fatalf("expected worn variant with lower specular")
+	}
+	if findMaterialStageByName(worn, "Stage3").Texture.Raw != findMaterialStageByName(result.Main, "Stage3").Texture.Raw {
+		t.Fatalf("expected worn variant to keep main macro")
+	}
+
+	badly, _, ok := result.Variant(DamageSuffixBadlyDamaged)
+	if !ok || findMaterialStageByName(badly, "Stage3").Texture.Raw != DefaultDamageMacroTexture {
+		t.Fatalf("expected badly damaged variant with damage macro")
+	}
+	if raw := findMaterialStageByName(badly, "Stage5").Texture.Raw; raw != `mod\my\data\box_badly_smdi.paa` {
+		t.Fatalf("unexpected badly damaged SMDI texture %q", raw)
+	}
+
+	if _, err := GenerateSet(GenerateSetOptions{Variants: []DamageVariant{{Suffix: "_worn"}, {Suffix: "_WORN"}}}); err == nil {
+		t.Fatalf("expected duplicate suffix error")
+	}
+}
+
+func TestGenerateSetConditionEffects(t *testing.T) {
+	clean, err := GenerateSet(GenerateSetOptions{BaseMaterial: BaseMaterialSteel})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	plain, err := GenerateSet(GenerateSetOptions{BaseMaterial: BaseMaterialSteel, Condition: ConditionDirty})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	for name, res := range plain.StageResolutions {
+		if len(res.Effects) != 0 {
+			t.Fatalf("unexpected %s effects without ConditionEffect: %+v", name, res)
+		}
+	}
+
+	effect := DefaultConditionEffect(ConditionDirty)
+	dirty, err := GenerateSet(GenerateSetOptions{BaseMaterial: BaseMaterialSteel, Condition: ConditionDirty, ConditionEffect: &effect})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+
+	macro := dirty.StageResolutions["Stage3"]
+	if macro.Source != StageTextureSourceCondition || len(macro.Effects) != 1 || macro.Effects[0] != "macro_blend" {
+		t.Fatalf("unexpected Stage3 resolution: %+v", macro)
+	}
+	if c := macro.Texture.Procedural.Color; c == nil || c.A != 0.35 {
+		t.Fatalf("expected macro blend alpha 0.35, got %+v", macro.Texture)
+	}
+	if as := dirty.StageResolutions["Stage4"]; as.Source != StageTextureSourceProcedural || as.Effects[0] != "as_darken" ||
+		as.Texture.Procedural.Color.R >= clean.StageResolutions["Stage4"].Texture.Procedural.Color.R {
+		t.Fatalf("expected darker procedural AS: %+v", as)
+	}
+	plainGloss := plain.StageResolutions["Stage5"].Texture.Procedural.Color.B
+	if smdi := dirty.StageResolutions["Stage5"]; smdi.Effects[0] != "gloss_scale" ||
+		math.Abs(smdi.Texture.Procedural.Color.B-plainGloss*effect.GlossScale) > 1e-3 {
+		t.Fatalf("expected SMDI gloss range scaled by %g: %+v", effect.GlossScale, smdi)
+	}
+
+	// Synthesized SMDI takes scaled gloss range too.
+	synth := GenerateSetOptions{
+		FileSystem:         NewMemFileSystem(),
+		BaseTexture:        `mod\data\crate_co.paa`,
+		BaseMaterial:       BaseMaterialSteel,
+		Condition:          ConditionDirty,
+		SynthesizeTextures: true,
+	}
+	plainSynth, err := GenerateSet(synth)
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	synth.ConditionEffect = &effect
+	dirtySynth, err := GenerateSet(synth)
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	if got, want := dirtySynth.SynthesizedTextures[2].Image.NRGBAAt(0, 0).B, plainSynth.SynthesizedTextures[2].Image.NRGBAAt(0, 0).B; got >= want {
+		t.Fatalf("expected lower synthesized SMDI gloss: %d >= %d", got, want)
+	}
+	if len(clean.StageResolutions["Stage3"].Effects) != 0 {
+		t.Fatalf("unexpected effects without condition: %+v", clean.StageResolutions["Stage3"])
+	}
+
+	// Swapped textures replace derived maps but keep explicit overrides.
+	rusty, err := GenerateSet(GenerateSetOptions{
+		BaseTexture:         `my\data\hatch_co.paa`,
+		TextureAutoFillMode: TextureAutoFillModeDisabled,
+		TextureOverrides:    map[string]string{"dt": `my\data\hatch_dt.paa`},
+		Condition:           ConditionOxidized,
+		ConditionEffect: &ConditionEffect{
+			DetailTexture: `my\data\rust_dt.paa`,
+			MacroTexture:  `my\data\rust_mc.paa`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	if r := rusty.StageResolutions["Stage3"]; r.Source != StageTextureSourceCondition || r.Texture.Raw != `my\data\rust_mc.paa` {
+		t.Fatalf("expected swapped macro: %+v", r)
+	}
+	if r := rusty.StageResolutions["Stage2"]; r.Source != StageTextureSourceExplicit || r.Texture.Raw != `my\data\hatch_dt.paa` {
+		t.Fatalf("expected explicit detail kept: %+v", r)
+	}
+
+	if _, err := GenerateSet(GenerateSetOptions{ConditionEffect: &ConditionEffect{MacroColor: []float64{1, 1}}}); err == nil {
+		t.Fatalf("expected invalid condition effect error")
+	}
+}
+
+func TestGenerateSetSynthesizeTextures(t *testing.T) {
+	// Base texture with dark left and bright right half.
+	base := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := range 4 {
+		for x := range 4 {
+			v := uint8(64)
+			if x >= 2 {
+				v = 192
+			}
+			base.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, base); err != nil {
+		t.Fatalf("encode base: %v", err)
+	}
+
+	fsys := NewMemFileSystem()
+	fsys.AddFile("data/crate_co.png", buf.Bytes())
+	fsys.AddFile("data/crate_nohq.paa", []byte("x"))
+
+	result, err := GenerateSet(GenerateSetOptions{
+		FileSystem:               fsys,
+		BaseTexture:              "data/crate_co.png",
+		OutputPath:               "out/crate.rvmat",
+		BaseMaterial:             BaseMaterialSteel,
+		SynthesizeTextures:       true,
+		SynthesizeNormalStrength: 4,
+	})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+
+	// Stage1 exists on disk, so only AS and SMDI are synthesized.
+	if result.StageResolutions["Stage1"].Source != StageTextureSourceAutoFill {
+		t.Fatalf("expected autofill source for Stage1")
+	}
+	for _, stageName := range []string{"Stage4", "Stage5"} {
+		res := result.StageResolutions[stageName]
+		if res.Source != StageTextureSourceSynthesized || !res.Texture.IsPath() {
+			t.Fatalf("expected synthesized path for %s: %+v", stageName, res)
+		}
+	}
+	if raw := findMaterialStageByName(result.Damage, "Stage5").Texture.Raw; raw != `data\crate_smdi.paa` {
+		t.Fatalf("unexpected damage Stage5 texture: %q", raw)
+	}
+	if len(result.SynthesizedTextures) != 2 {
+		t.Fatalf("expected 2 synthesized textures, got %+v", result.SynthesizedTextures)
+	}
+
+	smdi := result.SynthesizedTextures[1]
+	if smdi.Role != "smdi" || smdi.Texture != `data\crate_smdi.paa` || smdi.OutputPath != filepath.Join("out", "crate_smdi.png") {
+		t.Fatalf("unexpected smdi texture: %+v", smdi)
+	}
+	dark, bright := smdi.Image.NRGBAAt(0, 0), smdi.Image.NRGBAAt(3, 0)
+	if dark.R != 255 || bright.G <= dark.G || dark.B != bright.B {
+		t.Fatalf("unexpected smdi texels: %+v %+v", dark, bright)
+	}
+
+	if err := WriteGenerateSetFS(fsys, result, nil); err != nil {
+		t.Fatalf("write generated set: %v", err)
+	}
+	img, err := LoadTextureImage(fsys, filepath.Join("out", "crate_as.png"))
+	if err != nil || img.Bounds().Dx() != 4 {
+		t.Fatalf("expected written as texture: %v", err)
+	}
+	if _, err := fsys.Stat(filepath.Join("data", "crate_as.png")); err == nil {
+		t.Fatal("synthesized texture must not be written next to base texture")
+	}
+}
+
+func TestGenerateSetSynthesizeFlatNormal(t *testing.T) {
+	result, err := GenerateSet(GenerateSetOptions{
+		FileSystem:         NewMemFileSystem(),
+		BaseTexture:        `mod\data\missing_co.paa`,
+		SynthesizeTextures: true,
+	})
+	if err != nil {
+		t.Fatalf("generate rvmat: %v", err)
+	}
+	if len(result.SynthesizedTextures) != 3 {
+		t.Fatalf("expected 3 synthesized textures, got %d", len(result.SynthesizedTextures))
+	}
+
+	nohq := result.SynthesizedTextures[0]
+	if nohq.Role != "nohq" || nohq.Image.Bounds().Dx() != 8 {
+		t.Fatalf("expected 8x8 fallback nohq: %+v", nohq)
+	}
+	if c := nohq.Image.NRGBAAt(3, 3); c != (color.NRGBA{R: 128, G: 128, B: 255, A: 255}) {
+		t.Fatalf("expected flat normal, got %+v", c)
+	}
+	if nohq.Texture != `mod\data\missing_nohq.paa` || nohq.OutputPath != "" {
+		t.Fatalf("unexpected synthesized paths without output: %+v", nohq)
+	}
+}
+
 func TestWriteGenerateSetUsesFormatIndent(t *testing.T) {
 	tmp := t.TempDir()
 	outPath := filepath.Join(tmp, "testbox.rvmat")
@@ -4,7 +4,11 @@
 
 package rvmat
 
-import "fmt"
+import (
+	"fmt"
+	"image"
+	"strings"
+)
 
 // TextureAutoFillMode controls how stage textures are auto-discovered from disk.
 type TextureAutoFillMode uint8
@@ -32,10 +36,54 @@ const (
 	StageTextureSourceDerived StageTextureSource = "derived"
 	// StageTextureSourceProcedural is procedural/default generated fallback.
 	StageTextureSourceProcedural StageTextureSource = "procedural"
+	// StageTextureSourceSynthesized is image synthesized from base texture and profile.
+	StageTextureSourceSynthesized StageTextureSource = "synthesized"
+	// StageTextureSourceCondition is texture swapped or blended by condition effect.
+	StageTextureSourceCondition StageTextureSource = "condition"
+)
+
+const (
+	// DamageSuffixWorn is output suffix of worn damage level material.
+	DamageSuffixWorn = "_worn"
+	// DamageSuffixDamage is output suffix of damaged level material.
+	DamageSuffixDamage = "_damage"
+	// DamageSuffixBadlyDamaged is output suffix of badly damaged level material.
+	DamageSuffixBadlyDamaged = "_badly_damaged"
+	// DamageSuffixDestruct is output suffix of ruined level material.
+	DamageSuffixDestruct = "_destruct"
 )
 
+// DamageVariant defines one damage level material derived from main material.
+type DamageVariant struct {
+	// Suffix is appended to main output file name (DamageSuffix* constants).
+	Suffix string `json:"suffix" yaml:"suffix"`
+	// MacroTexture replaces Stage3 macro texture; empty value keeps main one.
+	MacroTexture string `json:"macro_texture,omitempty" yaml:"macro_texture,omitempty"`
+	// ASTexture replaces ambient shadow (as) stage texture when set.
+	ASTexture string `json:"as_texture,omitempty" yaml:"as_texture,omitempty"`
+	// SMDITexture replaces specular (smdi) stage texture when set.
+	SMDITexture string `json:"smdi_texture,omitempty" yaml:"smdi_texture,omitempty"`
+	// SpecularMult scales specular RGB; zero keeps main value.
+	SpecularMult float64 `json:"specular_mult,omitempty" yaml:"specular_mult,omitempty"`
+	// PowerMult scales specularPower; zero keeps main value.
+	PowerMult float64 `json:"power_mult,omitempty" yaml:"power_mult,omitempty"`
+}
+
+// GeneratedVariant is extra damage level material generated by GenerateSet.
+type GeneratedVariant struct {
+	// Material is generated variant material.
+	Material *Material `json:"material,omitempty" yaml:"material,omitempty"`
+	// Suffix is variant output suffix.
+	Suffix string `json:"suffix" yaml:"suffix"`
+	// OutputPath is output path for variant material.
+	OutputPath string `json:"output_path,omitempty" yaml:"output_path,omitempty"`
+}
+
 // GenerateSetOptions configures top-level rvmat generation orchestration.
 type GenerateSetOptions struct {
+	// FileSystem is used to discover sibling textures.
+	// Nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
 	// TextureOverrides overrides stage or role textures
 	// (stage1..stage7, nohq/dt/mc/as/smdi/env/fresnel).
 	// Stage keys have priority over role keys.
@@ -47,12 +95,25 @@ type GenerateSetOptions struct {
 	// TexturePrefix prepends a path prefix to generated local texture paths.
 	// Known game-root paths (for example dz\*, ca\*, a3\*) are kept as-is.
 	TexturePrefix string `json:"texture_prefix,omitempty" yaml:"texture_prefix,omitempty"`
+	// ConditionEffect overrides profile stage effects of Condition; see
+	// DefaultConditionEffect for opt-in built-in presets.
+	ConditionEffect *ConditionEffect `json:"condition_effect,omitempty" yaml:"condition_effect,omitempty"`
+	// Profile selects built-in or registered custom material profile by name
+	// (see RegisterMaterialProfile); it has priority over BaseMaterial.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
 	// DamageMacroTexture overrides Stage3 macro texture for damage variant.
 	DamageMacroTexture string `json:"damage_macro_texture,omitempty" yaml:"damage_macro_texture,omitempty"`
 	// DestructMacroTexture overrides Stage3 macro texture for destruct variant.
 	DestructMacroTexture string `json:"destruct_macro_texture,omitempty" yaml:"destruct_macro_texture,omitempty"`
+	// Variants defines extra damage level materials (see DefaultDamageVariants).
+	// Variants with DamageSuffixDamage or DamageSuffixDestruct suffix replace
+	// built-in damage and destruct definitions.
+	Variants []DamageVariant `json:"variants,omitempty" yaml:"variants,omitempty"`
 	// EmissiveIntensity sets emissive RGB for generated material when > 0.
 	EmissiveIntensity float64 `json:"emissive_intensity,omitempty" yaml:"emissive_intensity,omitempty"`
+	// SynthesizeNormalStrength derives _nohq from base texture luminance when > 0;
+	// synthesized normal map is flat otherwise.
+	SynthesizeNormalStrength float64 `json:"synthesize_normal_strength,omitempty" yaml:"synthesize_normal_strength,omitempty"`
 	// BaseMaterial selects generation material profile.
 	BaseMaterial BaseMaterial `json:"base_material,omitempty" yaml:"base_material,omitempty"`
 	// Condition applies surface condition modifier.
@@ -73,6 +134,9 @@ type GenerateSetOptions struct {
 	DisableDestruct bool `json:"disable_destruct,omitempty" yaml:"disable_destruct,omitempty"`
 	// DisableTexGen disables compact TexGen generation.
 	DisableTexGen bool `json:"disable_texgen,omitempty" yaml:"disable_texgen,omitempty"`
+	// SynthesizeTextures synthesizes _smdi/_as/_nohq images for stages that
+	// would fall back to procedural textures (see SynthesizedTextures).
+	SynthesizeTextures bool `json:"synthesize_textures,omitempty" yaml:"synthesize_textures,omitempty"`
 }
 
 // GenerateStageResolution describes resolved texture for one stage.
@@ -83,6 +147,21 @@ type GenerateStageResolution struct {
 	Source StageTextureSource `json:"source,omitempty" yaml:"source,omitempty"`
 	// Texture is resolved stage texture.
 	Texture TextureRef `json:"texture" yaml:"texture"`
+	// Effects lists condition effects applied to stage (for example "macro_blend").
+	Effects []string `json:"effects,omitempty" yaml:"effects,omitempty"`
+}
+
+// SynthesizedTexture is texture image synthesized by GenerateSet.
+type SynthesizedTexture struct {
+	// Image is synthesized texture image.
+	Image *image.NRGBA `json:"-" yaml:"-"`
+	// Role is canonical role key (nohq/as/smdi).
+	Role string `json:"role,omitempty" yaml:"role,omitempty"`
+	// Texture is ".paa" texture path referenced by generated materials.
+	Texture string `json:"texture,omitempty" yaml:"texture,omitempty"`
+	// OutputPath is PNG file path next to main output written by
+	// WriteGenerateSetFS; convert it to Texture for game builds.
+	OutputPath string `json:"output_path,omitempty" yaml:"output_path,omitempty"`
 }
 
 // GenerateSetResult is top-level generation output.
@@ -101,6 +180,39 @@ type GenerateSetResult struct {
 	DamageOutputPath string `json:"damage_output_path,omitempty" yaml:"damage_output_path,omitempty"`
 	// DestructOutputPath is output path for destruct material.
 	DestructOutputPath string `json:"destruct_output_path,omitempty" yaml:"destruct_output_path,omitempty"`
+	// SynthesizedTextures lists images to write next to main output.
+	SynthesizedTextures []SynthesizedTexture `json:"synthesized_textures,omitempty" yaml:"synthesized_textures,omitempty"`
+	// Variants lists extra damage level materials (for example worn).
+	Variants []GeneratedVariant `json:"variants,omitempty" yaml:"variants,omitempty"`
+}
+
+// DefaultDamageVariants returns worn, damage, badly damaged, and destruct
+// variant definitions for all DayZ health levels.
+func DefaultDamageVariants() []DamageVariant {
+	return []DamageVariant{
+		{Suffix: DamageSuffixWorn, SpecularMult: 0.9, PowerMult: 0.85},
+		{Suffix: DamageSuffixDamage, MacroTexture: DefaultDamageMacroTexture},
+		{Suffix: DamageSuffixBadlyDamaged, MacroTexture: DefaultDamageMacroTexture, SpecularMult: 0.75, PowerMult: 0.7},
+		{Suffix: DamageSuffixDestruct, MacroTexture: DefaultDestructMacroTexture},
+	}
+}
+
+// Variant returns generated material and output path for variant suffix,
+// including built-in damage and destruct variants.
+func (r *GenerateSetResult) Variant(suffix string) (*Material, string, bool) {
+	switch {
+	case strings.EqualFold(suffix, DamageSuffixDamage) && r.Damage != nil:
+		return r.Damage, r.DamageOutputPath, true
+	case strings.EqualFold(suffix, DamageSuffixDestruct) && r.Destruct != nil:
+		return r.Destruct, r.DestructOutputPath, true
+	}
+	for _, v := range r.Variants {
+		if strings.EqualFold(v.Suffix, suffix) {
+			return v.Material, v.OutputPath, true
+		}
+	}
+
+	return nil, "", false
 }
 
 // String returns human-readable auto-fill mode name.
@@ -7,32 +7,54 @@ package rvmat
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 )
 
 // WriteGenerateSet writes generated materials to result output paths.
 func WriteGenerateSet(result *GenerateSetResult, opt *FormatOptions) error {
+	return WriteGenerateSetFS(OSFileSystem{}, result, opt)
+}
+
+// WriteGenerateSetFS writes generated materials to result output paths in fsys.
+//
+// Synthesized textures are written as PNG to their output paths.
+//
+// Nil fsys uses OS filesystem.
+func WriteGenerateSetFS(fsys WritableFileSystem, result *GenerateSetResult, opt *FormatOptions) error {
+	fsys = writableFileSystemOrOS(fsys)
 	if result == nil {
 		return errors.New("write generated rvmat result: nil result")
 	}
 
-	if err := writeGeneratedMaterial(result.MainOutputPath, result.Main, opt); err != nil {
+	if err := writeGeneratedMaterial(fsys, result.MainOutputPath, result.Main, opt); err != nil {
 		return fmt.Errorf("write generated rvmat result main: %w", err)
 	}
-	if err := writeGeneratedMaterial(result.DamageOutputPath, result.Damage, opt); err != nil {
+	if err := writeGeneratedMaterial(fsys, result.DamageOutputPath, result.Damage, opt); err != nil {
 		return fmt.Errorf("write generated rvmat result damage: %w", err)
 	}
-	if err := writeGeneratedMaterial(result.DestructOutputPath, result.Destruct, opt); err != nil {
+	if err := writeGeneratedMaterial(fsys, result.DestructOutputPath, result.Destruct, opt); err != nil {
 		return fmt.Errorf("write generated rvmat result destruct: %w", err)
 	}
+	for _, v := range result.Variants {
+		if err := writeGeneratedMaterial(fsys, v.OutputPath, v.Material, opt); err != nil {
+			return fmt.Errorf("write generated rvmat result %s: %w", strings.TrimPrefix(v.Suffix, "_"), err)
+		}
+	}
+	for _, tex := range result.SynthesizedTextures {
+		if strings.TrimSpace(tex.OutputPath) == "" || tex.Image == nil {
+			continue
+		}
+		if err := writeImagePNG(fsys, filepath.Clean(tex.OutputPath), tex.Image); err != nil {
+			return fmt.Errorf("write generated rvmat result %s texture: %w", tex.Role, err)
+		}
+	}
 
 	return nil
 }
 
-// writeGeneratedMaterial writes one generated material to disk.
-func writeGeneratedMaterial(path string, m *Material, opt *FormatOptions) error {
+// writeGeneratedMaterial writes one generated material to fsys.
+func writeGeneratedMaterial(fsys WritableFileSystem, path string, m *Material, opt *FormatOptions) error {
 	if strings.TrimSpace(path) == "" || m == nil {
 		return nil
 	}
@@ -40,7 +62,7 @@ func writeGeneratedMaterial(path string, m *Material, opt *FormatOptions) error
 	clean := filepath.Clean(path)
 	dir := filepath.Dir(clean)
 	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0o750); err != nil {
+		if err := fsys.MkdirAll(dir, 0o750); err != nil {
 			return err
 		}
 	}
@@ -50,5 +72,5 @@ func writeGeneratedMaterial(path string, m *Material, opt *FormatOptions) error
 		return err
 	}
 
-	return os.WriteFile(clean, formatted, 0o600)
+	return fsys.WriteFile(clean, formatted, 0o600)
 }
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+)
+
+// synthesizedTextureRoles lists roles synthesized by GenerateSet.
+var synthesizedTextureRoles = []string{"nohq", "as", "smdi"}
+
+// synthesizeStageTextures replaces procedural fallback stages of generated
+// material with synthesized image textures and returns them by role.
+//
+// Procedural fallback values already carry class and profile tuning, so they
+// become image means: SMDI green is scaled by base texture luminance
+// (normalized to its mean), SMDI blue and AS keep fallback values, and NOHQ
+// is flat or derived from luminance as height map. Without readable base
+// texture images are 8x8 and uniform.
+//
+// Like ConvertPBR, material references ".paa" siblings of base texture while
+// images are written as ".png" for conversion; see synthesizedOutputPath.
+func synthesizeStageTextures(
+	opts GenerateSetOptions,
+	m *Material,
+	explicit map[string]StageTextureSource,
+	baseTexture string,
+	mainOutputPath string,
+) []SynthesizedTexture {
+	if !opts.SynthesizeTextures || opts.ForceProceduralOnly || m == nil {
+		return nil
+	}
+
+	gameBase := NormalizeGameTexturePath(baseTexture)
+	if _, _, ok := splitBaseTextureStem(gameBase); !ok {
+		return nil
+	}
+
+	source := newLuminanceMap(opts.FileSystem, baseTexture)
+	var out []SynthesizedTexture
+	for _, role := range synthesizedTextureRoles {
+		stageName, _ := stageNameForTextureRole(role)
+		st := findMaterialStageByName(m, stageName)
+		if st == nil || explicit[stageName] == StageTextureSourceExplicit ||
+			!st.Texture.IsProcedural() || st.Texture.Procedural == nil || st.Texture.Procedural.Color == nil {
+			continue
+		}
+
+		raw := textureWithExtension(derivedTextureForRole(gameBase, role), ".paa")
+		out = append(out, SynthesizedTexture{
+			Role:       role,
+			Texture:    raw,
+			OutputPath: synthesizedOutputPath(mainOutputPath, raw),
+			Image:      source.synthesize(role, *st.Texture.Procedural.Color, opts.SynthesizeNormalStrength),
+		})
+		st.Texture = ParseTextureRef(raw)
+	}
+
+	return out
+}
+
+// synthesizedOutputPath returns PNG file path for synthesized texture.
+//
+// Files are placed next to main output; empty output path writes nothing.
+func synthesizedOutputPath(mainOutputPath, raw string) string {
+	if strings.TrimSpace(mainOutputPath) == "" {
+		return ""
+	}
+
+	name := textureWithExtension(strings.ReplaceAll(raw, `\`, "/"), ".png")
+	return filepath.Join(filepath.Dir(mainOutputPath), filepath.Base(filepath.FromSlash(name)))
+}
+
+// luminanceMap is base texture luminance used as synthesis source.
+type luminanceMap struct {
+	values []float64
+	mean   float64
+	width  int
+	height int
+}
+
+// newLuminanceMap loads base texture luminance or uniform 8x8 map.
+func newLuminanceMap(fsys FileSystem, baseTexture string) luminanceMap {
+	img, err := loadTextureImageAnyExt(fsys, baseTexture)
+	if err != nil {
+		return luminanceMap{width: 8, height: 8}
+	}
+
+	b := img.Bounds()
+	lm := luminanceMap{width: b.Dx(), height: b.Dy(), values: make([]float64, b.Dx()*b.Dy())}
+	for y := range lm.height {
+		for x := range lm.width {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			l := luminance(float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+			lm.values[y*lm.width+x] = l
+			lm.mean += l
+		}
+	}
+	lm.mean /= float64(len(lm.values))
+
+	return lm
+}
+
+// at returns luminance at wrapped texel, 0.5 for uniform map.
+func (lm luminanceMap) at(x, y int) float64 {
+	if lm.values == nil {
+		return 0.5
+	}
+
+	x = (x%lm.width + lm.width) % lm.width
+	y = (y%lm.height + lm.height) % lm.height
+	return lm.values[y*lm.width+x]
+}
+
+// synthesize builds role image around procedural fallback color.
+func (lm luminanceMap) synthesize(role string, fallback ProceduralColor, normalStrength float64) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, lm.width, lm.height))
+	for y := range lm.height {
+		for x := range lm.width {
+			var r, g, b float64
+			switch role {
+			case "smdi":
+				r, g, b = 1, fallback.G, fallback.B
+				if lm.values != nil && lm.mean > 0 {
+					g *= lm.at(x, y) / lm.mean
+				}
+
+			case "nohq":
+				// Height gradient in DirectX convention: green follows image Y down.
+				dx := (lm.at(x+1, y) - lm.at(x-1, y)) * normalStrength
+				dy := (lm.at(x, y+1) - lm.at(x, y-1)) * normalStrength
+				n := normalize3([3]float64{-dx, -dy, 1})
+				r, g, b = n[0]*0.5+0.5, n[1]*0.5+0.5, n[2]*0.5+0.5
+
+			default:
+				r, g, b = fallback.R, fallback.G, fallback.B
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{R: unitToByte(r), G: unitToByte(g), B: unitToByte(b), A: 255})
+		}
+	}
+
+	return img
+}
@@ -83,6 +83,27 @@ const (
 	ConditionOxidized
 )
 
+// ConditionEffect is texture stage change applied by surface condition.
+//
+// Swaps and blends skip stages set by texture overrides or auto-fill;
+// blends and ambient shadow changes only touch procedural fallbacks, and
+// gloss scale narrows SMDI fallback range used by procedural and
+// synthesized textures.
+type ConditionEffect struct {
+	// DetailTexture swaps Stage2 detail (_dt) texture.
+	DetailTexture string `json:"detail_texture,omitempty" yaml:"detail_texture,omitempty"`
+	// MacroTexture swaps Stage3 macro (_mc) texture.
+	MacroTexture string `json:"macro_texture,omitempty" yaml:"macro_texture,omitempty"`
+	// DetailColor is RGBA mixed into procedural Stage2 detail by its alpha.
+	DetailColor []float64 `json:"detail_color,omitempty" yaml:"detail_color,omitempty"`
+	// MacroColor is RGBA of procedural Stage3 macro; alpha is blend amount.
+	MacroColor []float64 `json:"macro_color,omitempty" yaml:"macro_color,omitempty"`
+	// GlossScale scales procedural SMDI gloss (blue) range; zero keeps it.
+	GlossScale float64 `json:"gloss_scale,omitempty" yaml:"gloss_scale,omitempty"`
+	// ASDarken darkens procedural ambient shadow by fraction in 0..1.
+	ASDarken float64 `json:"as_darken,omitempty" yaml:"as_darken,omitempty"`
+}
+
 // GenerateOptions configures baseline material generation.
 type GenerateOptions struct {
 	// TextureOverrides overrides generated textures by stage or role key.
@@ -94,6 +115,12 @@ type GenerateOptions struct {
 	// BaseTexture is a source texture path used to derive role textures.
 	// Example: "my/path/item_co.paa" -> "_nohq/_dt/_mc/_as/_smdi".
 	BaseTexture string `json:"base_texture,omitempty" yaml:"base_texture,omitempty"`
+	// ConditionEffect overrides profile stage effects of Condition; see
+	// DefaultConditionEffect for opt-in built-in presets.
+	ConditionEffect *ConditionEffect `json:"condition_effect,omitempty" yaml:"condition_effect,omitempty"`
+	// Profile selects built-in or registered custom profile by name;
+	// it has priority over BaseMaterial when set.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
 	// EmissiveIntensity sets emissive RGB for generated material when > 0.
 	EmissiveIntensity float64 `json:"emissive_intensity,omitempty" yaml:"emissive_intensity,omitempty"`
 	// BaseMaterial selects generation profile (BaseMaterial* constants).
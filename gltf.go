@@ -0,0 +1,449 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// glbMagic is binary glTF header magic ("glTF").
+	glbMagic = 0x46546C67
+	// glbChunkJSON is binary glTF JSON chunk type ("JSON").
+	glbChunkJSON = 0x4E4F534A
+	// glbChunkBIN is binary glTF buffer chunk type ("BIN\0").
+	glbChunkBIN = 0x004E4942
+)
+
+// GLTFImportOptions controls glTF material import.
+type GLTFImportOptions struct {
+	// TextureExtension replaces image URI extensions (default ".paa").
+	TextureExtension string `json:"texture_extension,omitempty" yaml:"texture_extension,omitempty"`
+	// TexturePrefix is passed to GenerateSetOptions.TexturePrefix.
+	TexturePrefix string `json:"texture_prefix,omitempty" yaml:"texture_prefix,omitempty"`
+	// OutputDir sets GenerateSetOptions.OutputPath to OutputDir/<name>.rvmat when not empty;
+	// names mapping to same file get "_<index>" suffix.
+	OutputDir string `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+}
+
+// GLTFMaterial is one glTF material mapped to generator options.
+//
+// Mapping is lossy: fields without generator equivalent are listed in Dropped.
+type GLTFMaterial struct {
+	// Name is glTF material name or "material<index>" when unnamed.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Dropped lists glTF fields that were not mapped.
+	Dropped []string `json:"dropped,omitempty" yaml:"dropped,omitempty"`
+	// Options is generator input with inferred profile and texture overrides.
+	Options GenerateSetOptions `json:"options" yaml:"options"`
+	// Metallic is glTF metallic factor used for inference.
+	Metallic float64 `json:"metallic" yaml:"metallic"`
+	// Roughness is glTF roughness factor used for inference.
+	Roughness float64 `json:"roughness" yaml:"roughness"`
+	// Index is material index in glTF materials array.
+	Index int `json:"index" yaml:"index"`
+}
+
+// gltfDocument is subset of glTF 2.0 JSON used by import and export.
+type gltfDocument struct {
+	Scene          *int            `json:"scene,omitempty"`
+	Asset          gltfAsset       `json:"asset"`
+	Scenes         []gltfScene     `json:"scenes,omitempty"`
+	Nodes          []gltfNode      `json:"nodes,omitempty"`
+	Meshes         []gltfMesh      `json:"meshes,omitempty"`
+	Materials      []gltfMaterial  `json:"materials,omitempty"`
+	Textures       []gltfTexture   `json:"textures,omitempty"`
+	Images         []gltfImage     `json:"images,omitempty"`
+	Samplers       []gltfSampler   `json:"samplers,omitempty"`
+	Buffers        []gltfBuffer    `json:"buffers,omitempty"`
+	BufferViews    []gltfView      `json:"bufferViews,omitempty"`
+	Accessors      []gltfAccessor  `json:"accessors,omitempty"`
+	ExtensionsUsed []string        `json:"extensionsUsed,omitempty"`
+	Extras         json.RawMessage `json:"extras,omitempty"`
+}
+
+// gltfAsset is glTF asset metadata.
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+// gltfScene is glTF scene root node list.
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+// gltfNode is glTF scene node.
+type gltfNode struct {
+	Mesh *int   `json:"mesh,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// gltfMesh is glTF mesh.
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+// gltfPrimitive is glTF mesh primitive.
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+}
+
+// gltfMaterial is glTF material.
+type gltfMaterial struct {
+	PBR              *gltfPBR                   `json:"pbrMetallicRoughness,omitempty"`
+	NormalTexture    *gltfTextureInfo           `json:"normalTexture,omitempty"`
+	OcclusionTexture *gltfTextureInfo           `json:"occlusionTexture,omitempty"`
+	EmissiveTexture  *gltfTextureInfo           `json:"emissiveTexture,omitempty"`
+	AlphaCutoff      *float64                   `json:"alphaCutoff,omitempty"`
+	Extensions       map[string]json.RawMessage `json:"extensions,omitempty"`
+	Name             string                     `json:"name,omitempty"`
+	AlphaMode        string                     `json:"alphaMode,omitempty"`
+	EmissiveFactor   []float64                  `json:"emissiveFactor,omitempty"`
+	Extras           json.RawMessage            `json:"extras,omitempty"`
+	DoubleSided      bool                       `json:"doubleSided,omitempty"`
+}
+
+// gltfPBR is glTF pbrMetallicRoughness block.
+type gltfPBR struct {
+	BaseColorTexture         *gltfTextureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor           *float64         `json:"metallicFactor,omitempty"`
+	RoughnessFactor          *float64         `json:"roughnessFactor,omitempty"`
+	MetallicRoughnessTexture *gltfTextureInfo `json:"metallicRoughnessTexture,omitempty"`
+	BaseColorFactor          []float64        `json:"baseColorFactor,omitempty"`
+}
+
+// gltfTextureInfo is glTF material texture binding.
+type gltfTextureInfo struct {
+	Scale      *float64                   `json:"scale,omitempty"`
+	Strength   *float64                   `json:"strength,omitempty"`
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"`
+	Index      int                        `json:"index"`
+	TexCoord   int                        `json:"texCoord,omitempty"`
+}
+
+// gltfTexture is glTF texture (image and sampler pair).
+type gltfTexture struct {
+	Source  *int   `json:"source,omitempty"`
+	Sampler *int   `json:"sampler,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// gltfImage is glTF image referenced by URI or buffer view.
+type gltfImage struct {
+	BufferView *int   `json:"bufferView,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	MimeType   string `json:"mimeType,omitempty"`
+	Name       string `json:"name,omitempty"`
+}
+
+// gltfSampler is glTF texture sampler.
+type gltfSampler struct {
+	WrapS int `json:"wrapS,omitempty"`
+	WrapT int `json:"wrapT,omitempty"`
+}
+
+// gltfBuffer is glTF binary buffer.
+type gltfBuffer struct {
+	URI        string `json:"uri,omitempty"`
+	ByteLength int    `json:"byteLength"`
+}
+
+// gltfView is glTF buffer view.
+type gltfView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+// gltfAccessor is glTF typed view into buffer.
+type gltfAccessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+}
+
+// LoadGLTF reads .gltf or .glb file and maps its materials.
+func LoadGLTF(fsys FileSystem, name string, opt *GLTFImportOptions) ([]GLTFMaterial, error) {
+	data, err := fileSystemOrOS(fsys).ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ImportGLTF(data, opt)
+}
+
+// ImportGLTF maps materials of .gltf JSON or .glb binary to generator options.
+//
+// Base material, finish, and condition are inferred from metallic and
+// roughness factors (InferGenerateProfile). Base color texture becomes
+// BaseTexture, normal texture the "nohq" override, and occlusion texture
+// the "as" override; image URIs get TextureExtension. Packed
+// metallic/roughness and emissive textures, alpha, double-sided,
+// texture transforms, and extensions are reported in Dropped; use
+// ConvertPBR to bake them into Super textures.
+func ImportGLTF(data []byte, opt *GLTFImportOptions) ([]GLTFMaterial, error) {
+	o := GLTFImportOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	if o.TextureExtension == "" {
+		o.TextureExtension = ".paa"
+	}
+
+	doc, err := decodeGLTF(data)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("%w: unsupported asset version %q", ErrInvalidGLTF, doc.Asset.Version)
+	}
+
+	out := make([]GLTFMaterial, 0, len(doc.Materials))
+	used := make(map[string]bool, len(doc.Materials))
+	for i := range doc.Materials {
+		m := importGLTFMaterial(doc, i, o)
+		if o.OutputDir != "" {
+			m.Options.OutputPath = filepath.Join(o.OutputDir, gltfUniqueFileName(m.Name, i, used)+".rvmat")
+		}
+		out = append(out, m)
+	}
+
+	return out, nil
+}
+
+// decodeGLTF parses glTF JSON directly or from GLB JSON chunk.
+func decodeGLTF(data []byte) (*gltfDocument, error) {
+	if len(data) >= 12 && binary.LittleEndian.Uint32(data) == glbMagic {
+		chunk, err := glbJSONChunk(data)
+		if err != nil {
+			return nil, err
+		}
+		data = chunk
+	}
+
+	var doc gltfDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGLTF, err)
+	}
+
+	return &doc, nil
+}
+
+// glbJSONChunk returns JSON chunk of binary glTF container.
+func glbJSONChunk(data []byte) ([]byte, error) {
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, fmt.Errorf("%w: glb version %d", ErrInvalidGLTF, version)
+	}
+
+	// First chunk must be JSON per spec.
+	if len(data) < 20 {
+		return nil, fmt.Errorf("%w: truncated glb header", ErrInvalidGLTF)
+	}
+	length := int(binary.LittleEndian.Uint32(data[12:]))
+	if binary.LittleEndian.Uint32(data[16:]) != glbChunkJSON {
+		return nil, fmt.Errorf("%w: first glb chunk is not JSON", ErrInvalidGLTF)
+	}
+	if length < 0 || 20+length > len(data) {
+		return nil, fmt.Errorf("%w: truncated glb JSON chunk", ErrInvalidGLTF)
+	}
+
+	return bytes.TrimRight(data[20:20+length], " \x00"), nil
+}
+
+// importGLTFMaterial maps one glTF material to generator options.
+func importGLTFMaterial(doc *gltfDocument, index int, o GLTFImportOptions) GLTFMaterial {
+	src := doc.Materials[index]
+	res := GLTFMaterial{
+		Index:     index,
+		Name:      strings.TrimSpace(src.Name),
+		Metallic:  1,
+		Roughness: 1,
+	}
+	if res.Name == "" {
+		res.Name = fmt.Sprintf("material%d", index)
+	}
+
+	drop := func(field string) {
+		res.Dropped = append(res.Dropped, field)
+	}
+	texture := func(field string, info *gltfTextureInfo) string {
+		raw, reason := gltfTexturePath(doc, info, o.TextureExtension)
+		if reason != "" {
+			drop(field + ": " + reason)
+		}
+		if info != nil && len(info.Extensions) > 0 {
+			drop(field + ".extensions: " + strings.Join(slices.Sorted(maps.Keys(info.Extensions)), ","))
+		}
+
+		return raw
+	}
+
+	gen := GenerateSetOptions{
+		TexturePrefix:    o.TexturePrefix,
+		TextureOverrides: map[string]string{},
+	}
+
+	if pbr := src.PBR; pbr != nil {
+		// glTF defaults both factors to 1 when absent.
+		res.Metallic = clamp01(optionalFactor(pbr.MetallicFactor))
+		res.Roughness = clamp01(optionalFactor(pbr.RoughnessFactor))
+		if pbr.BaseColorTexture != nil {
+			gen.BaseTexture = texture("baseColorTexture", pbr.BaseColorTexture)
+		}
+		if len(pbr.BaseColorFactor) > 0 && !slices.Equal(pbr.BaseColorFactor, []float64{1, 1, 1, 1}) {
+			drop("baseColorFactor")
+		}
+		if pbr.MetallicRoughnessTexture != nil {
+			drop("metallicRoughnessTexture")
+		}
+	}
+
+	if src.NormalTexture != nil {
+		if raw := texture("normalTexture", src.NormalTexture); raw != "" {
+			gen.TextureOverrides["nohq"] = raw
+		}
+		if src.NormalTexture.Scale != nil && *src.NormalTexture.Scale != 1 {
+			drop("normalTexture.scale")
+		}
+	}
+	if src.OcclusionTexture != nil {
+		if raw := texture("occlusionTexture", src.OcclusionTexture); raw != "" {
+			gen.TextureOverrides["as"] = raw
+		}
+		if src.OcclusionTexture.Strength != nil && *src.OcclusionTexture.Strength != 1 {
+			drop("occlusionTexture.strength")
+		}
+	}
+	if len(gen.TextureOverrides) == 0 {
+		gen.TextureOverrides = nil
+	}
+
+	for _, e := range src.EmissiveFactor {
+		gen.EmissiveIntensity = max(gen.EmissiveIntensity, e)
+	}
+	if slices.ContainsFunc(src.EmissiveFactor, func(e float64) bool { return e != src.EmissiveFactor[0] }) {
+		// Generator emissive is gray; hue is lost.
+		drop("emissiveFactor")
+	}
+	if src.EmissiveTexture != nil {
+		drop("emissiveTexture")
+	}
+	if src.AlphaMode != "" && !strings.EqualFold(src.AlphaMode, "OPAQUE") {
+		drop("alphaMode")
+	}
+	if src.AlphaCutoff != nil {
+		drop("alphaCutoff")
+	}
+	if src.DoubleSided {
+		drop("doubleSided")
+	}
+	if len(src.Extensions) > 0 {
+		drop("extensions: " + strings.Join(slices.Sorted(maps.Keys(src.Extensions)), ","))
+	}
+
+	gen.BaseMaterial, gen.Finish = InferGenerateProfile(res.Metallic, res.Roughness)
+	gen.Condition = inferGenerateCondition(res.Metallic, res.Roughness)
+
+	res.Options = gen
+	return res
+}
+
+// gltfTexturePath resolves texture binding to game texture path.
+//
+// Non-empty reason explains why binding cannot be mapped.
+func gltfTexturePath(doc *gltfDocument, info *gltfTextureInfo, ext string) (string, string) {
+	if info.TexCoord != 0 {
+		return "", fmt.Sprintf("texCoord %d", info.TexCoord)
+	}
+	if info.Index < 0 || info.Index >= len(doc.Textures) {
+		return "", fmt.Sprintf("texture index %d out of range", info.Index)
+	}
+
+	tex := doc.Textures[info.Index]
+	if tex.Source == nil || *tex.Source < 0 || *tex.Source >= len(doc.Images) {
+		return "", "texture without image source"
+	}
+
+	img := doc.Images[*tex.Source]
+	switch {
+	case img.BufferView != nil:
+		return "", "embedded image"
+	case img.URI == "":
+		return "", "image without uri"
+	case strings.HasPrefix(img.URI, "data:"):
+		return "", "data uri image"
+	}
+
+	uri, err := url.PathUnescape(img.URI)
+	if err != nil {
+		uri = img.URI
+	}
+
+	return NormalizeGameTexturePath(textureWithExtension(uri, ext)), ""
+}
+
+// inferGenerateCondition picks generator condition from metallic and roughness.
+//
+// Very rough metals read as oxidized, rough metals as worn, and very rough
+// dielectrics as dirty; everything else keeps default condition.
+func inferGenerateCondition(metallic, roughness float64) Condition {
+	switch {
+	case metallic >= 0.5 && roughness >= 0.85:
+		return ConditionOxidized
+	case metallic >= 0.5 && roughness >= 0.65:
+		return ConditionWorn
+	case metallic < 0.5 && roughness >= 0.9:
+		return ConditionDirty
+	default:
+		return ConditionDefault
+	}
+}
+
+// gltfUniqueFileName returns file name of material not yet in used,
+// appending material index on collision, and records it in used.
+func gltfUniqueFileName(name string, index int, used map[string]bool) string {
+	base := gltfFileName(name)
+	stem := base
+	for n := 0; used[stem]; n++ {
+		stem = fmt.Sprintf("%s_%d", base, index)
+		if n > 0 {
+			stem = fmt.Sprintf("%s_%d_%d", base, index, n)
+		}
+	}
+
+	used[stem] = true
+	return stem
+}
+
+// gltfFileName converts material name to safe file name.
+func gltfFileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// gltfFloat is glTF FLOAT component type.
+	gltfFloat = 5126
+	// gltfUnsignedShort is glTF UNSIGNED_SHORT component type.
+	gltfUnsignedShort = 5123
+	// gltfArrayBuffer is glTF ARRAY_BUFFER buffer view target.
+	gltfArrayBuffer = 34962
+	// gltfElementArrayBuffer is glTF ELEMENT_ARRAY_BUFFER buffer view target.
+	gltfElementArrayBuffer = 34963
+
+	// gltfSphereSegments is longitude segment count of exported preview sphere.
+	gltfSphereSegments = 32
+	// gltfSphereRings is latitude ring count of exported preview sphere.
+	gltfSphereRings = 16
+)
+
+// GLTFExportOptions controls approximate glTF material export.
+type GLTFExportOptions struct {
+	// Metallic sets metallic factor; RVMAT does not encode metalness (default 0).
+	Metallic *float64 `json:"metallic,omitempty" yaml:"metallic,omitempty"`
+	// Name is glTF material name (default "rvmat").
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// BaseTexture is base color (_co) texture path; RVMAT stages do not reference it.
+	BaseTexture string `json:"base_texture,omitempty" yaml:"base_texture,omitempty"`
+	// TextureExtension replaces texture path extensions in image URIs (default ".png").
+	TextureExtension string `json:"texture_extension,omitempty" yaml:"texture_extension,omitempty"`
+	// Shape selects preview mesh geometry (PreviewShape* constants).
+	Shape PreviewShape `json:"shape,omitempty" yaml:"shape,omitempty"`
+	// MaterialOnly omits preview mesh and scene.
+	MaterialOnly bool `json:"material_only,omitempty" yaml:"material_only,omitempty"`
+	// Binary writes .glb container instead of .gltf JSON.
+	Binary bool `json:"binary,omitempty" yaml:"binary,omitempty"`
+}
+
+// GLTFExportResult is approximate glTF document for a material.
+type GLTFExportResult struct {
+	// Data is .gltf JSON or .glb binary document.
+	Data []byte `json:"-" yaml:"-"`
+	// Dropped lists RVMAT fields that have no glTF equivalent.
+	Dropped []string `json:"dropped,omitempty" yaml:"dropped,omitempty"`
+}
+
+// ExportGLTF converts material to approximate glTF metal/rough material
+// for previews in standard viewers.
+//
+// Diffuse becomes base color factor, specular power becomes roughness
+// (inverse of generator gloss curve), emissive becomes emissive factor,
+// Stage1 (nohq) the normal texture, and Stage4 (as) the occlusion
+// texture. Texture URIs keep RVMAT paths with TextureExtension and forward
+// slashes, so textures must be converted next to them. Normal maps keep
+// DirectX green channel. Other stages, colors, shaders, UV transforms,
+// and unknown properties are reported in Dropped.
+//
+// By default document contains a preview mesh (sphere or plane) with
+// embedded geometry so it can be opened directly.
+func ExportGLTF(m *Material, opt *GLTFExportOptions) (*GLTFExportResult, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: nil material", ErrMaterialNotFound)
+	}
+
+	o := GLTFExportOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	if o.Name == "" {
+		o.Name = "rvmat"
+	}
+	if o.TextureExtension == "" {
+		o.TextureExtension = ".png"
+	}
+	if o.Shape != PreviewShapeSphere && o.Shape != PreviewShapePlane {
+		return nil, fmt.Errorf("%w: unknown shape %s", ErrInvalidGLTF, o.Shape)
+	}
+
+	res := &GLTFExportResult{}
+	doc := &gltfDocument{Asset: gltfAsset{Version: "2.0", Generator: "github.com/woozymasta/rvmat"}}
+	mat := exportGLTFMaterial(m, doc, o, res)
+	doc.Materials = []gltfMaterial{mat}
+
+	var bin []byte
+	if !o.MaterialOnly {
+		bin = addGLTFPreviewMesh(doc, o.Shape)
+		if !o.Binary {
+			doc.Buffers[0].URI = "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(bin)
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if o.Binary {
+		data = encodeGLB(data, bin)
+	}
+
+	res.Data = data
+	return res, nil
+}
+
+// exportGLTFMaterial builds glTF material and records dropped fields.
+func exportGLTFMaterial(m *Material, doc *gltfDocument, o GLTFExportOptions, res *GLTFExportResult) gltfMaterial {
+	drop := func(field string) {
+		res.Dropped = append(res.Dropped, field)
+	}
+
+	metallic := 0.0
+	if o.Metallic != nil {
+		metallic = clamp01(*o.Metallic)
+	}
+	roughness := 1.0
+	if m.SpecularPower != nil {
+		roughness = 1 - glossRatioFromSpecularPower(*m.SpecularPower)
+	}
+
+	pbr := &gltfPBR{MetallicFactor: &metallic, RoughnessFactor: &roughness}
+	if len(m.Diffuse) >= 3 {
+		factor := []float64{clamp01(m.Diffuse[0]), clamp01(m.Diffuse[1]), clamp01(m.Diffuse[2]), 1}
+		if len(m.Diffuse) >= 4 {
+			factor[3] = clamp01(m.Diffuse[3])
+		}
+		pbr.BaseColorFactor = factor
+	}
+	if o.BaseTexture != "" {
+		pbr.BaseColorTexture = addGLTFTexture(doc, o.BaseTexture, o.TextureExtension)
+	}
+
+	mat := gltfMaterial{Name: o.Name, PBR: pbr}
+	if len(m.Emissive) >= 3 {
+		factor := make([]float64, 3)
+		for i := range factor {
+			if m.Emissive[i] > 1 {
+				drop(fmt.Sprintf("emissive above 1 (%g)", m.Emissive[i]))
+			}
+			factor[i] = clamp01(m.Emissive[i])
+		}
+		if factor[0] > 0 || factor[1] > 0 || factor[2] > 0 {
+			mat.EmissiveFactor = factor
+		}
+	}
+
+	if len(m.Ambient) > 0 {
+		drop("ambient")
+	}
+	if len(m.ForcedDiffuse) > 0 {
+		drop("forcedDiffuse")
+	}
+	if len(m.Specular) > 0 {
+		drop("specular")
+	}
+	if m.PixelShaderID != "" {
+		drop("PixelShaderID " + m.PixelShaderID)
+	}
+	if m.VertexShaderID != "" {
+		drop("VertexShaderID " + m.VertexShaderID)
+	}
+	if len(m.TexGens) > 0 {
+		drop("TexGen classes")
+	}
+	if len(m.extras) > 0 {
+		drop("unknown properties")
+	}
+
+	for _, stage := range m.Stages {
+		role, _ := textureRoleForStageName(stage.Name)
+		label := stage.Name
+		if role != "" {
+			label += " (" + role + ")"
+		}
+		if stage.UVTransform != nil || stage.TexGen != "" {
+			drop(label + " uv transform")
+		}
+
+		raw := strings.TrimSpace(stage.Texture.Raw)
+		switch {
+		case raw == "":
+			continue
+		case !stage.Texture.IsPath():
+			drop(label + " procedural texture " + raw)
+		case role == "nohq":
+			mat.NormalTexture = addGLTFTexture(doc, raw, o.TextureExtension)
+			drop(label + " DirectX green channel kept")
+		case role == "as":
+			mat.OcclusionTexture = addGLTFTexture(doc, raw, o.TextureExtension)
+		default:
+			drop(label + " texture " + raw)
+		}
+	}
+
+	return mat
+}
+
+// addGLTFTexture appends image and texture for texture path.
+func addGLTFTexture(doc *gltfDocument, raw, ext string) *gltfTextureInfo {
+	uri := strings.ReplaceAll(textureWithExtension(NormalizeGameTexturePath(raw), ext), `\`, "/")
+	for i, tex := range doc.Textures {
+		if tex.Source != nil && doc.Images[*tex.Source].URI == uri {
+			return &gltfTextureInfo{Index: i}
+		}
+	}
+
+	image := len(doc.Images)
+	doc.Images = append(doc.Images, gltfImage{URI: uri})
+	doc.Textures = append(doc.Textures, gltfTexture{Source: &image})
+
+	return &gltfTextureInfo{Index: len(doc.Textures) - 1}
+}
+
+// addGLTFPreviewMesh appends preview mesh, node, and scene and returns buffer bytes.
+func addGLTFPreviewMesh(doc *gltfDocument, shape PreviewShape) []byte {
+	positions, normals, uvs, indices := gltfPreviewGeometry(shape)
+
+	var buf bytes.Buffer
+	view := func(data any, target int) *int {
+		offset := buf.Len()
+		_ = binary.Write(&buf, binary.LittleEndian, data)
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+
+		doc.BufferViews = append(doc.BufferViews, gltfView{
+			ByteOffset: offset,
+			ByteLength: binary.Size(data),
+			Target:     target,
+		})
+		idx := len(doc.BufferViews) - 1
+		return &idx
+	}
+	accessor := func(a gltfAccessor) int {
+		doc.Accessors = append(doc.Accessors, a)
+		return len(doc.Accessors) - 1
+	}
+
+	vertexCount := len(positions) / 3
+	minPos, maxPos := []float64{-1, -1, -1}, []float64{1, 1, 1}
+	if shape == PreviewShapePlane {
+		minPos[2], maxPos[2] = 0, 0
+	}
+	position := accessor(gltfAccessor{
+		BufferView: view(positions, gltfArrayBuffer), ComponentType: gltfFloat,
+		Count: vertexCount, Type: "VEC3", Min: minPos, Max: maxPos,
+	})
+	normal := accessor(gltfAccessor{
+		BufferView: view(normals, gltfArrayBuffer), ComponentType: gltfFloat,
+		Count: vertexCount, Type: "VEC3",
+	})
+	uv := accessor(gltfAccessor{
+		BufferView: view(uvs, gltfArrayBuffer), ComponentType: gltfFloat,
+		Count: vertexCount, Type: "VEC2",
+	})
+	index := accessor(gltfAccessor{
+		BufferView: view(indices, gltfElementArrayBuffer), ComponentType: gltfUnsignedShort,
+		Count: len(indices), Type: "SCALAR",
+	})
+
+	material, mesh, scene := 0, 0, 0
+	doc.Buffers = []gltfBuffer{{ByteLength: buf.Len()}}
+	doc.Meshes = []gltfMesh{{
+		Name: shape.String(),
+		Primitives: []gltfPrimitive{{
+			Attributes: map[string]int{"POSITION": position, "NORMAL": normal, "TEXCOORD_0": uv},
+			Indices:    &index,
+			Material:   &material,
+		}},
+	}}
+	doc.Nodes = []gltfNode{{Mesh: &mesh, Name: shape.String()}}
+	doc.Scenes = []gltfScene{{Nodes: []int{0}}}
+	doc.Scene = &scene
+
+	return buf.Bytes()
+}
+
+// gltfPreviewGeometry returns preview mesh vertex attributes and triangle indices.
+func gltfPreviewGeometry(shape PreviewShape) (positions, normals, uvs []float32, indices []uint16) {
+	if shape == PreviewShapePlane {
+		positions = []float32{-1, -1, 0, 1, -1, 0, 1, 1, 0, -1, 1, 0}
+		normals = []float32{0, 0, 1, 0, 0, 1, 0, 0, 1, 0, 0, 1}
+		uvs = []float32{0, 1, 1, 1, 1, 0, 0, 0}
+		return positions, normals, uvs, []uint16{0, 1, 2, 0, 2, 3}
+	}
+
+	// UV sphere with seam column duplicated for continuous texture coordinates.
+	for ring := 0; ring <= gltfSphereRings; ring++ {
+		v := float64(ring) / gltfSphereRings
+		theta := v * math.Pi
+		for seg := 0; seg <= gltfSphereSegments; seg++ {
+			u := float64(seg) / gltfSphereSegments
+			phi := u * 2 * math.Pi
+			x := float32(math.Sin(theta) * math.Sin(phi))
+			y := float32(math.Cos(theta))
+			z := float32(math.Sin(theta) * math.Cos(phi))
+
+			positions = append(positions, x, y, z)
+			normals = append(normals, x, y, z)
+			uvs = append(uvs, float32(u), float32(v))
+		}
+	}
+
+	stride := uint16(gltfSphereSegments + 1)
+	for ring := range uint16(gltfSphereRings) {
+		for seg := range uint16(gltfSphereSegments) {
+			a := ring*stride + seg
+			b := a + stride
+			indices = append(indices, a, b, a+1, a+1, b, b+1)
+		}
+	}
+
+	return positions, normals, uvs, indices
+}
+
+// encodeGLB packs glTF JSON and binary buffer into .glb container.
+func encodeGLB(jsonData, bin []byte) []byte {
+	jsonData = append(jsonData, bytes.Repeat([]byte{' '}, (4-len(jsonData)%4)%4)...)
+	bin = append(bin, make([]byte, (4-len(bin)%4)%4)...)
+
+	total := 12 + 8 + len(jsonData)
+	if len(bin) > 0 {
+		total += 8 + len(bin)
+	}
+
+	out := make([]byte, 0, total)
+	out = binary.LittleEndian.AppendUint32(out, glbMagic)
+	out = binary.LittleEndian.AppendUint32(out, 2)
+	out = binary.LittleEndian.AppendUint32(out, uint32(total))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(jsonData)))
+	out = binary.LittleEndian.AppendUint32(out, glbChunkJSON)
+	out = append(out, jsonData...)
+	if len(bin) > 0 {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(bin)))
+		out = binary.LittleEndian.AppendUint32(out, glbChunkBIN)
+		out = append(out, bin...)
+	}
+
+	return out
+}
@@ -0,0 +1,176 @@
+package rvmat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testGLTF = `{
+  "asset": {"version": "2.0"},
+  "images": [
+    {"uri": "textures/crate%20co.png"},
+    {"uri": "textures/crate_nohq.png"},
+    {"uri": "textures/crate_as.png"},
+    {"bufferView": 0, "mimeType": "image/png"}
+  ],
+  "textures": [{"source": 0}, {"source": 1}, {"source": 2}, {"source": 3}],
+  "materials": [
+    {
+      "name": "Crate Metal",
+      "pbrMetallicRoughness": {
+        "baseColorTexture": {"index": 0},
+        "metallicFactor": 1,
+        "roughnessFactor": 0.3,
+        "metallicRoughnessTexture": {"index": 3}
+      },
+      "normalTexture": {"index": 1, "scale": 0.5},
+      "occlusionTexture": {"index": 2},
+      "emissiveFactor": [0, 0.5, 0.25],
+      "doubleSided": true,
+      "extensions": {"KHR_materials_clearcoat": {}}
+    },
+    {
+      "pbrMetallicRoughness": {"metallicFactor": 0, "roughnessFactor": 0.95},
+      "emissiveTexture": {"index": 3}
+    },
+    {},
+    {"name": "Crate_Metal", "emissiveFactor": [0.5, 0.5, 0.5]}
+  ]
+}`
+
+func TestImportGLTF(t *testing.T) {
+	materials, err := ImportGLTF([]byte(testGLTF), &GLTFImportOptions{OutputDir: "out"})
+	if err != nil {
+		t.Fatalf("ImportGLTF: %v", err)
+	}
+	if len(materials) != 4 {
+		t.Fatalf("expected 4 materials, got %d", len(materials))
+	}
+
+	metal := materials[0].Options
+	if metal.BaseMaterial != BaseMaterialSteel || metal.Finish != FinishGloss || metal.Condition != ConditionDefault {
+		t.Fatalf("unexpected metal profile: %s/%s/%s", metal.BaseMaterial, metal.Finish, metal.Condition)
+	}
+	if metal.BaseTexture != `textures\crate co.paa` {
+		t.Fatalf("unexpected base texture %q", metal.BaseTexture)
+	}
+	if metal.TextureOverrides["nohq"] != `textures\crate_nohq.paa` || metal.TextureOverrides["as"] != `textures\crate_as.paa` {
+		t.Fatalf("unexpected overrides: %v", metal.TextureOverrides)
+	}
+	if metal.EmissiveIntensity != 0.5 || !strings.HasSuffix(metal.OutputPath, "crate_metal.rvmat") {
+		t.Fatalf("unexpected emissive/output: %v %q", metal.EmissiveIntensity, metal.OutputPath)
+	}
+	for _, want := range []string{"metallicRoughnessTexture", "normalTexture.scale", "emissiveFactor", "doubleSided", "extensions: KHR_materials_clearcoat"} {
+		if !slices.Contains(materials[0].Dropped, want) {
+			t.Fatalf("expected %q in dropped: %v", want, materials[0].Dropped)
+		}
+	}
+
+	rough := materials[1]
+	if rough.Name != "material1" || rough.Options.BaseMaterial != BaseMaterialPlastic ||
+		rough.Options.Finish != FinishMatte || rough.Options.Condition != ConditionDirty {
+		t.Fatalf("unexpected rough dielectric: %+v", rough)
+	}
+	if !slices.Contains(rough.Dropped, "emissiveTexture") {
+		t.Fatalf("expected emissiveTexture dropped: %v", rough.Dropped)
+	}
+
+	// Missing pbrMetallicRoughness uses glTF defaults metallic=1, roughness=1.
+	if def := materials[2].Options; def.BaseMaterial != BaseMaterialSteel || def.Condition != ConditionOxidized {
+		t.Fatalf("unexpected default material profile: %+v", def)
+	}
+
+	// Same file name as "Crate Metal" gets material index suffix; gray
+	// emissive is not reported as dropped.
+	same := materials[3]
+	if !strings.HasSuffix(same.Options.OutputPath, "crate_metal_3.rvmat") || slices.Contains(same.Dropped, "emissiveFactor") {
+		t.Fatalf("unexpected colliding material: %q %v", same.Options.OutputPath, same.Dropped)
+	}
+
+	for _, opts := range []GenerateSetOptions{metal, rough.Options, materials[2].Options} {
+		opts.FileSystem = NewMemFileSystem()
+		if _, err := GenerateSet(opts); err != nil {
+			t.Fatalf("GenerateSet: %v", err)
+		}
+	}
+}
+
+func TestImportGLTFErrors(t *testing.T) {
+	for _, data := range []string{`{`, `{"asset":{"version":"1.0"}}`, "glTF\x01\x00\x00\x00\x0c\x00\x00\x00"} {
+		if _, err := ImportGLTF([]byte(data), nil); !errors.Is(err, ErrInvalidGLTF) {
+			t.Fatalf("expected ErrInvalidGLTF for %q, got %v", data, err)
+		}
+	}
+}
+
+func TestExportGLTFRoundTrip(t *testing.T) {
+	m, err := Generate(GenerateOptions{
+		BaseMaterial:     BaseMaterialPlastic,
+		Finish:           FinishPolished,
+		BaseTexture:      `mod\data\box_co.paa`,
+		TextureOverrides: map[string]string{"nohq": `mod\data\box_nohq.paa`},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	res, err := ExportGLTF(m, &GLTFExportOptions{BaseTexture: `mod\data\box_co.paa`})
+	if err != nil {
+		t.Fatalf("ExportGLTF: %v", err)
+	}
+	if len(res.Dropped) == 0 {
+		t.Fatal("expected dropped fields report")
+	}
+
+	var doc gltfDocument
+	if err := json.Unmarshal(res.Data, &doc); err != nil {
+		t.Fatalf("unmarshal export: %v", err)
+	}
+	if len(doc.Meshes) != 1 || len(doc.Buffers) != 1 || !strings.HasPrefix(doc.Buffers[0].URI, "data:") {
+		t.Fatalf("expected embedded preview mesh: %+v", doc.Buffers)
+	}
+	if doc.Images[0].URI != "mod/data/box_co.png" {
+		t.Fatalf("unexpected base color uri %q", doc.Images[0].URI)
+	}
+
+	back, err := ImportGLTF(res.Data, nil)
+	if err != nil {
+		t.Fatalf("ImportGLTF: %v", err)
+	}
+	// Finish is relative to base material power, so only base material survives.
+	got := back[0].Options
+	if got.BaseMaterial != BaseMaterialPlastic {
+		t.Fatalf("unexpected round-trip base material: %s", got.BaseMaterial)
+	}
+
+	matte, err := Generate(GenerateOptions{BaseMaterial: BaseMaterialPlastic, Finish: FinishMatte})
+	if err != nil {
+		t.Fatalf("Generate matte: %v", err)
+	}
+	matteRes, err := ExportGLTF(matte, &GLTFExportOptions{MaterialOnly: true})
+	if err != nil {
+		t.Fatalf("ExportGLTF matte: %v", err)
+	}
+	matteBack, err := ImportGLTF(matteRes.Data, nil)
+	if err != nil || matteBack[0].Roughness <= back[0].Roughness {
+		t.Fatalf("expected matte rougher than polished: %v vs %v (%v)", matteBack, back[0].Roughness, err)
+	}
+	if got.BaseTexture != `mod\data\box_co.paa` || got.TextureOverrides["nohq"] != `mod\data\box_nohq.paa` {
+		t.Fatalf("unexpected round-trip textures: %q %v", got.BaseTexture, got.TextureOverrides)
+	}
+
+	glb, err := ExportGLTF(m, &GLTFExportOptions{Binary: true, Shape: PreviewShapePlane})
+	if err != nil {
+		t.Fatalf("ExportGLTF binary: %v", err)
+	}
+	if !bytes.HasPrefix(glb.Data, []byte("glTF")) || len(glb.Data)%4 != 0 {
+		t.Fatalf("unexpected glb container")
+	}
+	if _, err := ImportGLTF(glb.Data, nil); err != nil {
+		t.Fatalf("ImportGLTF glb: %v", err)
+	}
+}
@@ -2,6 +2,9 @@ module github.com/woozymasta/rvmat
 
 go 1.25.5
 
-require github.com/woozymasta/lintkit v0.2.2
+require (
+	github.com/woozymasta/lintkit v0.2.2
+	go.yaml.in/yaml/v3 v3.0.4
+)
 
 require github.com/woozymasta/pathrules v0.1.2 // indirect
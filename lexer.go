@@ -6,6 +6,7 @@ package rvmat
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
@@ -35,25 +36,39 @@ const (
 
 // token represents a token in the RVMAT file.
 type token struct {
-	Lit  string    // Literal value of the token
-	Type tokenType // Type of the token
-	Line int       // Line number of the token
-	Col  int       // Column number of the token
+	Lit    string    // Literal value of the token
+	Type   tokenType // Type of the token
+	Line   int       // Line number of the token
+	Col    int       // Column number of the token
+	Offset int64     // Byte offset of the token
+	End    int64     // Byte offset just past the token
 }
 
 // runeReader reads runes with one-rune pushback.
+//
+// ReadByte recovers raw bytes of invalid UTF-8 sequences.
 type runeReader interface {
 	ReadRune() (rune, int, error)
 	UnreadRune() error
+	ReadByte() (byte, error)
 }
 
+// rawByteRuneBase maps invalid UTF-8 input bytes to lone surrogate runes
+// (U+DC80..U+DCFF), which valid UTF-8 never decodes to, so legacy
+// code-page paths survive lexing byte-exact.
+const rawByteRuneBase = 0xDC00
+
 // lexer represents a lexer for the RVMAT file.
 type lexer struct {
-	r   runeReader   // Reader for the input
-	pos position     // Position of the current token
-	ch  rune         // Current character
-	opt ParseOptions // Options for the lexer
-	eof bool         // End of file
+	r          runeReader             // Reader for the input
+	directives []suppressionDirective // Inline suppression directives from comments
+	scratch    []byte                 // Reused token text buffer
+	pos        position               // Position of the current token
+	offset     int64                  // Byte offset of the current character
+	width      int                    // Byte width of the current character
+	ch         rune                   // Current character
+	opt        ParseOptions           // Options for the lexer
+	eof        bool                   // End of file
 }
 
 // position represents a position in the input.
@@ -92,10 +107,21 @@ func toBufferedReader(r io.Reader) *bufio.Reader {
 	return bufio.NewReader(r)
 }
 
-// next returns the next token from the RVMAT file.
+// next returns the next token from the RVMAT file with its byte offset.
 func (l *lexer) next() (token, error) {
-	// Tokenization is single-pass; skip whitespace/comments first.
 	l.skipWhitespace()
+	offset := l.offset
+	tok, err := l.scan()
+	tok.Offset = offset
+	tok.End = l.offset
+
+	return tok, err
+}
+
+// scan tokenizes input at the current character.
+//
+// Caller skips whitespace and comments first.
+func (l *lexer) scan() (token, error) {
 	if l.eof {
 		return token{Type: tokEOF, Line: l.pos.line, Col: l.pos.col}, nil
 	}
@@ -128,7 +154,7 @@ func (l *lexer) next() (token, error) {
 	case ',':
 		l.read()
 		return token{Type: tokComma, Lit: ",", Line: startLine, Col: startCol}, nil
-	case '"':
+	case '"', '\'':
 		lit, err := l.readString()
 		return token{Type: tokString, Lit: lit, Line: startLine, Col: startCol}, err
 
@@ -159,12 +185,22 @@ func (l *lexer) next() (token, error) {
 
 // read reads the next character from the RVMAT file.
 func (l *lexer) read() {
-	ch, _, err := l.r.ReadRune()
+	l.offset += int64(l.width)
+	ch, size, err := l.r.ReadRune()
 	if err != nil {
 		l.eof = true
 		l.ch = 0
+		l.width = 0
 		return
 	}
+	l.width = size
+	if ch == utf8.RuneError && size == 1 {
+		if err := l.r.UnreadRune(); err == nil {
+			if b, err := l.r.ReadByte(); err == nil {
+				ch = rawByteRuneBase + rune(b)
+			}
+		}
+	}
 
 	if ch == '\n' {
 		l.pos.line++
@@ -201,27 +237,37 @@ func (l *lexer) skipWhitespace() {
 			// Support // comments.
 			next := l.peek()
 			if next == '/' {
+				startLine, startCol := l.pos.line, l.pos.col
 				l.read()
 				l.read()
+				var text []byte
 				for l.ch != '\n' && !l.eof {
+					text = appendRuneByteSlice(text, l.ch)
 					l.read()
 				}
+				l.recordDirective(text, startLine, startCol)
 				continue
 			}
 
 			// Support /* */ comments.
 			if next == '*' {
+				startCol := l.pos.col
 				l.read()
 				l.read()
+				var text []byte
 				for {
 					if l.eof {
 						return
 					}
 					if l.ch == '*' && l.peek() == '/' {
+						// Block directives bind to the line where the comment ends.
+						endLine := l.pos.line
 						l.read()
 						l.read()
+						l.recordDirective(text, endLine, startCol)
 						break
 					}
+					text = appendRuneByteSlice(text, l.ch)
 					l.read()
 				}
 				continue
@@ -231,9 +277,23 @@ func (l *lexer) skipWhitespace() {
 	}
 }
 
+// recordDirective stores comment text when it is an rvmat-ignore directive.
+func (l *lexer) recordDirective(text []byte, line, col int) {
+	if !bytes.Contains(text, []byte(suppressionDirectivePrefix)) {
+		return
+	}
+
+	directive, ok := parseSuppressionDirective(string(text), line, col)
+	if !ok {
+		return
+	}
+
+	l.directives = append(l.directives, directive)
+}
+
 // readIdent reads an identifier from the RVMAT file.
 func (l *lexer) readIdent() string {
-	b := make([]byte, 0, 16)
+	b := l.scratch[:0]
 	for isIdentPart(l.ch) {
 		b = appendRuneByteSlice(b, l.ch)
 		l.read()
@@ -242,12 +302,13 @@ func (l *lexer) readIdent() string {
 		}
 	}
 
+	l.scratch = b
 	return string(b)
 }
 
 // readNumberOrIdent reads a number or identifier from the RVMAT file.
 func (l *lexer) readNumberOrIdent() string {
-	b := make([]byte, 0, 16)
+	b := l.scratch[:0]
 	for isWordPart(l.ch) {
 		b = appendRuneByteSlice(b, l.ch)
 		l.read()
@@ -256,45 +317,37 @@ func (l *lexer) readNumberOrIdent() string {
 		}
 	}
 
+	l.scratch = b
 	return string(b)
 }
 
-// readString reads a string from the RVMAT file.
+// readString reads a string in double or single quotes.
+//
+// Config strings have no backslash escapes; a doubled quote character
+// stands for one literal quote ("a""b" is a"b).
 func (l *lexer) readString() (string, error) {
+	quote := l.ch
 	l.read() // consume opening quote
-	b := make([]byte, 0, 16)
+	b := l.scratch[:0]
 	for {
 		if l.eof {
 			return "", l.errorf("unterminated string")
 		}
 
-		// Handle quoted strings.
-		if l.ch == '"' {
-			if l.peek() == '"' {
-				// Treat doubled quotes as an escaped quote (CSV-style).
+		if l.ch == quote {
+			if l.peek() != quote {
 				l.read()
-				l.read()
-				b = append(b, '"')
-				continue
+				break
 			}
+
 			l.read()
-			break
 		}
 
-		// Handle escaped characters.
-		if l.ch == '\\' {
-			next := l.peek()
-			if next == '\\' || next == '"' {
-				l.read()
-				b = appendRuneByteSlice(b, l.ch)
-				l.read()
-				continue
-			}
-		}
 		b = appendRuneByteSlice(b, l.ch)
 		l.read()
 	}
 
+	l.scratch = b
 	return string(b), nil
 }
 
@@ -358,11 +411,15 @@ func isValidNumber(s string) bool {
 	return err == nil
 }
 
-// appendRuneByteSlice appends a rune to a byte slice with an ASCII fast path.
+// appendRuneByteSlice appends a rune to a byte slice with an ASCII fast path;
+// raw-byte runes are appended as their original byte.
 func appendRuneByteSlice(dst []byte, r rune) []byte {
 	if r >= 0 && r < utf8.RuneSelf {
 		return append(dst, byte(r))
 	}
+	if r >= rawByteRuneBase+0x80 && r <= rawByteRuneBase+0xFF {
+		return append(dst, byte(r-rawByteRuneBase))
+	}
 
 	var tmp [utf8.UTFMax]byte
 	n := utf8.EncodeRune(tmp[:], r)
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errLZOTruncated reports LZO stream that ends before end-of-stream marker.
+var errLZOTruncated = errors.New("lzo: unexpected end of data")
+
+// lzoReader holds LZO1X decompression state.
+type lzoReader struct {
+	in   []byte
+	out  []byte
+	pos  int
+	size int // Expected output size; longer output is rejected
+}
+
+// decompressLZO decompresses LZO1X stream with known output size.
+func decompressLZO(data []byte, size int) ([]byte, error) {
+	r := &lzoReader{in: data, out: make([]byte, 0, size), size: size}
+	if err := r.run(); err != nil {
+		return nil, err
+	}
+	if len(r.out) != size {
+		return nil, fmt.Errorf("lzo: output size %d != %d", len(r.out), size)
+	}
+
+	return r.out, nil
+}
+
+// run decodes instructions until end-of-stream marker.
+func (r *lzoReader) run() error {
+	t, err := r.next()
+	if err != nil {
+		return err
+	}
+
+	// First byte above 17 encodes initial literal run.
+	state := 0
+	if t > 17 {
+		if err := r.literals(t - 17); err != nil {
+			return err
+		}
+		state = min(t-17, 4)
+		if t, err = r.next(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var dist, length int
+		switch {
+		case t >= 64:
+			// M2: 3-bit length, 11-bit distance.
+			b, err := r.next()
+			if err != nil {
+				return err
+			}
+			dist = 1 + (t>>2)&7 + b<<3
+			length = t>>5 + 1
+
+		case t >= 32:
+			// M3: long length, 14-bit distance.
+			length = t & 31
+			if length == 0 {
+				if length, err = r.extendedLength(31); err != nil {
+					return err
+				}
+			}
+			length += 2
+			v, err := r.uint16()
+			if err != nil {
+				return err
+			}
+			dist = 1 + v>>2
+
+		case t >= 16:
+			// M4: long length, 16K..48K distance or end of stream.
+			length = t & 7
+			if length == 0 {
+				if length, err = r.extendedLength(7); err != nil {
+					return err
+				}
+			}
+			length += 2
+			v, err := r.uint16()
+			if err != nil {
+				return err
+			}
+			dist = (t&8)<<11 + v>>2
+			if dist == 0 {
+				return nil
+			}
+			dist += 0x4000
+
+		case state == 0:
+			// Literal run.
+			length = t
+			if length == 0 {
+				if length, err = r.extendedLength(15); err != nil {
+					return err
+				}
+			}
+			if err := r.literals(length + 3); err != nil {
+				return err
+			}
+			state = 4
+			if t, err = r.next(); err != nil {
+				return err
+			}
+			continue
+
+		default:
+			// M1: short match after literals; longer form after literal run.
+			b, err := r.next()
+			if err != nil {
+				return err
+			}
+			dist = 1 + t>>2 + b<<2
+			length = 2
+			if state == 4 {
+				dist += 0x800
+				length = 3
+			}
+		}
+
+		if err := r.match(dist, length); err != nil {
+			return err
+		}
+
+		// Low two bits of last instruction byte encode trailing literals.
+		state = int(r.in[r.pos-2] & 3)
+		if err := r.literals(state); err != nil {
+			return err
+		}
+		if t, err = r.next(); err != nil {
+			return err
+		}
+	}
+}
+
+// next returns next input byte.
+func (r *lzoReader) next() (int, error) {
+	if r.pos >= len(r.in) {
+		return 0, errLZOTruncated
+	}
+
+	r.pos++
+	return int(r.in[r.pos-1]), nil
+}
+
+// uint16 returns next little-endian 16-bit value.
+func (r *lzoReader) uint16() (int, error) {
+	if r.pos+2 > len(r.in) {
+		return 0, errLZOTruncated
+	}
+
+	r.pos += 2
+	return int(r.in[r.pos-2]) | int(r.in[r.pos-1])<<8, nil
+}
+
+// extendedLength decodes zero-run length extension added to base.
+func (r *lzoReader) extendedLength(base int) (int, error) {
+	length := base
+	for {
+		b, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		if b != 0 {
+			return length + b, nil
+		}
+
+		length += 255
+	}
+}
+
+// literals copies n input bytes to output.
+func (r *lzoReader) literals(n int) error {
+	if r.pos+n > len(r.in) {
+		return errLZOTruncated
+	}
+	if len(r.out)+n > r.size {
+		return fmt.Errorf("lzo: output exceeds %d bytes", r.size)
+	}
+
+	r.out = append(r.out, r.in[r.pos:r.pos+n]...)
+	r.pos += n
+	return nil
+}
+
+// match copies length bytes from dist bytes back in output.
+func (r *lzoReader) match(dist, length int) error {
+	from := len(r.out) - dist
+	if from < 0 {
+		return fmt.Errorf("lzo: match distance %d before output start", dist)
+	}
+	if len(r.out)+length > r.size {
+		return fmt.Errorf("lzo: output exceeds %d bytes", r.size)
+	}
+
+	for i := range length {
+		r.out = append(r.out, r.out[from+i])
+	}
+
+	return nil
+}
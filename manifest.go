@@ -0,0 +1,372 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// GenerateManifest lists materials generated together with shared defaults.
+type GenerateManifest struct {
+	// Path is manifest file path; relative output paths resolve against its directory.
+	Path string `json:"-" yaml:"-"`
+	// Materials lists generated materials in manifest order.
+	Materials []GenerateManifestEntry `json:"materials" yaml:"materials"`
+	// Defaults stores options shared by all entries.
+	Defaults GenerateSetOptions `json:"defaults" yaml:"defaults"`
+}
+
+// GenerateManifestEntry is one manifest material.
+//
+// Non-zero option fields and fields marked in Set override manifest
+// defaults; TextureOverrides merge by key.
+type GenerateManifestEntry struct {
+	// Name identifies entry in reports (default: output file stem or "materials[i]").
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// GenerateSetOptions stores per-entry overrides.
+	GenerateSetOptions `json:",inline" yaml:",inline"`
+	// Set marks option fields given by entry, so explicit false or 0
+	// overrides defaults. ParseGenerateManifest fills it from entry YAML keys.
+	Set GenerateSetFields `json:"-" yaml:"-"`
+}
+
+// GenerateManifestOptions controls batch generation.
+type GenerateManifestOptions struct {
+	// FileSystem is used by entries for texture discovery.
+	// Nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
+	// Output receives generated files. Nil value uses OS filesystem.
+	Output WritableFileSystem `json:"-" yaml:"-"`
+	// Format controls written material formatting.
+	Format *FormatOptions `json:"format,omitempty" yaml:"format,omitempty"`
+	// Concurrency limits parallel generation (default GOMAXPROCS).
+	Concurrency int `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	// DryRun generates and checks outputs without writing files.
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+// GenerateManifestEntryResult is generation output of one manifest entry.
+type GenerateManifestEntryResult struct {
+	// Err is entry generation error.
+	Err error `json:"-" yaml:"-"`
+	// Result is generated set (nil on error).
+	Result *GenerateSetResult `json:"result,omitempty" yaml:"result,omitempty"`
+	// Name is entry name.
+	Name string `json:"name" yaml:"name"`
+}
+
+// GenerateManifestResult is batch generation output.
+type GenerateManifestResult struct {
+	// StageResolutions is combined stage report by entry name, then stage name.
+	StageResolutions map[string]map[string]GenerateStageResolution `json:"stage_resolutions,omitempty" yaml:"stage_resolutions,omitempty"`
+	// Entries stores per-entry results in manifest order.
+	Entries []GenerateManifestEntryResult `json:"entries,omitempty" yaml:"entries,omitempty"`
+	// Outputs lists planned output files (materials and synthesized textures).
+	Outputs []string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	// Written reports whether outputs were written (false on dry run or error).
+	Written bool `json:"written,omitempty" yaml:"written,omitempty"`
+}
+
+// ParseGenerateManifest parses manifest YAML (JSON is accepted as YAML subset).
+//
+// Unknown keys and duplicate entry names are rejected.
+func ParseGenerateManifest(data []byte) (*GenerateManifest, error) {
+	m := &GenerateManifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	for i, keys := range manifestEntryKeys(&doc) {
+		if i < len(m.Materials) {
+			m.Materials[i].Set = optionFieldsFromKeys[GenerateSetFields](keys)
+		}
+	}
+
+	seen := make(map[string]int, len(m.Materials))
+	for i := range m.Materials {
+		name := m.entryName(i)
+		if prev, ok := seen[strings.ToLower(name)]; ok {
+			return nil, fmt.Errorf("%w: materials[%d] name %q duplicates materials[%d]", ErrInvalidManifest, i, name, prev)
+		}
+		seen[strings.ToLower(name)] = i
+	}
+
+	return m, nil
+}
+
+// LoadGenerateManifest reads and parses manifest file.
+//
+// Nil fsys uses OS filesystem.
+func LoadGenerateManifest(fsys FileSystem, path string) (*GenerateManifest, error) {
+	data, err := fileSystemOrOS(fsys).ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read generate manifest: %w", err)
+	}
+
+	m, err := ParseGenerateManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	m.Path = path
+	return m, nil
+}
+
+// FormatGenerateManifest encodes manifest as YAML for review or storage.
+func FormatGenerateManifest(m *GenerateManifest) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: nil manifest", ErrInvalidManifest)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EntryOptions returns effective generator options of entry i.
+func (m *GenerateManifest) EntryOptions(i int) GenerateSetOptions {
+	opts := *mergeExplicitOptions(&m.Defaults, &m.Materials[i].GenerateSetOptions, &m.Materials[i].Set)
+	if out := strings.TrimSpace(opts.OutputPath); out != "" && m.Path != "" && !filepath.IsAbs(out) {
+		opts.OutputPath = filepath.Join(filepath.Dir(m.Path), out)
+	}
+
+	return opts
+}
+
+// manifestEntryKeys returns mapping keys of each materials entry in document.
+func manifestEntryKeys(doc *yaml.Node) [][]string {
+	root := yamlMappingNode(doc)
+	if root == nil {
+		return nil
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "materials" || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+
+		entries := root.Content[i+1].Content
+		out := make([][]string, len(entries))
+		for j, entry := range entries {
+			out[j] = yamlMappingKeys(yamlMappingNode(entry))
+		}
+		return out
+	}
+
+	return nil
+}
+
+// optionFieldsFromKeys marks fields of F whose YAML names are in keys.
+func optionFieldsFromKeys[F any](keys []string) F {
+	var out F
+	fields := reflect.ValueOf(&out).Elem()
+	for i := range fields.NumField() {
+		name, _, _ := strings.Cut(fields.Type().Field(i).Tag.Get("yaml"), ",")
+		if slices.Contains(keys, name) {
+			fields.Field(i).SetBool(true)
+		}
+	}
+
+	return out
+}
+
+// yamlMappingNode resolves document and alias wrappers to mapping node.
+func yamlMappingNode(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+		case yaml.AliasNode:
+			node = node.Alias
+		case yaml.MappingNode:
+			return node
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// yamlMappingKeys returns keys of mapping, including keys of "<<" merges.
+func yamlMappingKeys(node *yaml.Node) []string {
+	if node == nil {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if key != "<<" {
+			keys = append(keys, key)
+			continue
+		}
+
+		merged := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			merged = value.Content
+		}
+		for _, item := range merged {
+			keys = append(keys, yamlMappingKeys(yamlMappingNode(item))...)
+		}
+	}
+
+	return keys
+}
+
+// entryName returns explicit or derived name of entry i.
+func (m *GenerateManifest) entryName(i int) string {
+	entry := m.Materials[i]
+	if name := strings.TrimSpace(entry.Name); name != "" {
+		return name
+	}
+	if out := strings.TrimSpace(entry.OutputPath); out != "" {
+		return strings.TrimSuffix(filepath.Base(out), filepath.Ext(out))
+	}
+
+	return fmt.Sprintf("materials[%d]", i)
+}
+
+// GenerateFromManifest generates all manifest entries concurrently and
+// writes them unless DryRun is set.
+//
+// Output paths of main, damage, destruct materials and synthesized textures
+// are checked for collisions across all entries (case-insensitive) before
+// anything is written. Entry errors are joined; result keeps successful
+// entries for reporting.
+func GenerateFromManifest(m *GenerateManifest, opt *GenerateManifestOptions) (*GenerateManifestResult, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: nil manifest", ErrInvalidManifest)
+	}
+
+	o := GenerateManifestOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	workers := o.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	res := &GenerateManifestResult{
+		Entries:          make([]GenerateManifestEntryResult, len(m.Materials)),
+		StageResolutions: make(map[string]map[string]GenerateStageResolution, len(m.Materials)),
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i := range m.Materials {
+		opts := m.EntryOptions(i)
+		if opts.FileSystem == nil {
+			opts.FileSystem = o.FileSystem
+		}
+		res.Entries[i].Name = m.entryName(i)
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(entry *GenerateManifestEntryResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			entry.Result, entry.Err = GenerateSet(opts)
+		}(&res.Entries[i])
+	}
+	wg.Wait()
+
+	var errs []error
+	owners := map[string]string{}
+	for _, entry := range res.Entries {
+		if entry.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name, entry.Err))
+			continue
+		}
+
+		res.StageResolutions[entry.Name] = entry.Result.StageResolutions
+		for _, path := range generateSetOutputs(entry.Result) {
+			key := strings.ToLower(filepath.Clean(path))
+			if owner, ok := owners[key]; ok {
+				errs = append(errs, fmt.Errorf("%w: %s: %q also written by %s", ErrOutputCollision, entry.Name, path, owner))
+				continue
+			}
+
+			owners[key] = entry.Name
+			res.Outputs = append(res.Outputs, path)
+		}
+	}
+	slices.Sort(res.Outputs)
+
+	if len(errs) > 0 {
+		return res, errors.Join(errs...)
+	}
+	if o.DryRun {
+		return res, nil
+	}
+
+	for _, entry := range res.Entries {
+		if err := WriteGenerateSetFS(o.Output, entry.Result, o.Format); err != nil {
+			return res, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+	}
+	res.Written = true
+
+	return res, nil
+}
+
+// generateSetOutputs lists files written by WriteGenerateSetFS for result.
+func generateSetOutputs(r *GenerateSetResult) []string {
+	var out []string
+	for _, item := range []struct {
+		m    *Material
+		path string
+	}{
+		{r.Main, r.MainOutputPath},
+		{r.Damage, r.DamageOutputPath},
+		{r.Destruct, r.DestructOutputPath},
+	} {
+		if item.m != nil && strings.TrimSpace(item.path) != "" {
+			out = append(out, item.path)
+		}
+	}
+	for _, v := range r.Variants {
+		if v.Material != nil && strings.TrimSpace(v.OutputPath) != "" {
+			out = append(out, v.OutputPath)
+		}
+	}
+	for _, tex := range r.SynthesizedTextures {
+		if tex.Image != nil && strings.TrimSpace(tex.OutputPath) != "" {
+			out = append(out, tex.OutputPath)
+		}
+	}
+
+	return out
+}
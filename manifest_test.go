@@ -0,0 +1,119 @@
+package rvmat
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testManifest = `
+defaults:
+  base_material: steel
+  finish: gloss
+  disable_destruct: true
+  force_procedural_only: true
+  texture_overrides:
+    dt: mod\data\shared_dt.paa
+materials:
+  - name: hull
+    output_path: out/hull.rvmat
+    condition: worn
+  - output_path: out/wheel.rvmat
+    base_material: rubber
+    texture_overrides:
+      nohq: mod\data\wheel_nohq.paa
+`
+
+func TestParseGenerateManifest(t *testing.T) {
+	m, err := ParseGenerateManifest([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("ParseGenerateManifest: %v", err)
+	}
+	m.Path = filepath.Join("packs", "vehicle.yaml")
+
+	hull := m.EntryOptions(0)
+	if hull.BaseMaterial != BaseMaterialSteel || hull.Finish != FinishGloss || hull.Condition != ConditionWorn {
+		t.Fatalf("unexpected hull options: %+v", hull)
+	}
+	if hull.OutputPath != filepath.Join("packs", "out", "hull.rvmat") {
+		t.Fatalf("expected output relative to manifest, got %q", hull.OutputPath)
+	}
+
+	wheel := m.EntryOptions(1)
+	if wheel.BaseMaterial != BaseMaterialRubber || wheel.TextureOverrides["dt"] == "" || wheel.TextureOverrides["nohq"] == "" {
+		t.Fatalf("expected merged wheel overrides: %+v", wheel)
+	}
+	if m.entryName(1) != "wheel" {
+		t.Fatalf("unexpected derived entry name %q", m.entryName(1))
+	}
+
+	off, err := ParseGenerateManifest([]byte("defaults:\n  disable_texgen: true\nmaterials:\n  - name: a\n  - name: b\n    disable_texgen: false\n"))
+	if err != nil {
+		t.Fatalf("ParseGenerateManifest: %v", err)
+	}
+	if !off.EntryOptions(0).DisableTexGen || off.EntryOptions(1).DisableTexGen {
+		t.Fatalf("expected explicit false to override default: %+v %+v", off.EntryOptions(0), off.EntryOptions(1))
+	}
+
+	for _, data := range []string{
+		"materials:\n  - unknown_key: 1\n",
+		"materials:\n  - name: a\n  - name: A\n",
+	} {
+		if _, err := ParseGenerateManifest([]byte(data)); !errors.Is(err, ErrInvalidManifest) {
+			t.Fatalf("expected ErrInvalidManifest for %q, got %v", data, err)
+		}
+	}
+}
+
+func TestGenerateFromManifest(t *testing.T) {
+	m, err := ParseGenerateManifest([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("ParseGenerateManifest: %v", err)
+	}
+
+	out := NewMemFileSystem()
+	dry, err := GenerateFromManifest(m, &GenerateManifestOptions{Output: out, DryRun: true, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want := []string{
+		filepath.Join("out", "hull.rvmat"),
+		filepath.Join("out", "hull_damage.rvmat"),
+		filepath.Join("out", "wheel.rvmat"),
+		filepath.Join("out", "wheel_damage.rvmat"),
+	}
+	if !slices.Equal(dry.Outputs, want) || dry.Written || len(out.Files()) != 0 {
+		t.Fatalf("unexpected dry run: %v written=%v files=%v", dry.Outputs, dry.Written, out.Files())
+	}
+	if dry.StageResolutions["hull"]["Stage2"].Source != StageTextureSourceExplicit {
+		t.Fatalf("expected combined report with explicit hull Stage2: %+v", dry.StageResolutions["hull"])
+	}
+
+	res, err := GenerateFromManifest(m, &GenerateManifestOptions{Output: out})
+	if err != nil || !res.Written {
+		t.Fatalf("GenerateFromManifest: %v", err)
+	}
+	if _, err := out.ReadFile(filepath.Join("out", "wheel_damage.rvmat")); err != nil {
+		t.Fatalf("expected written wheel damage: %v", err)
+	}
+}
+
+func TestGenerateFromManifestCollision(t *testing.T) {
+	m := &GenerateManifest{
+		Defaults: GenerateSetOptions{ForceProceduralOnly: true, DisableDestruct: true},
+		Materials: []GenerateManifestEntry{
+			{GenerateSetOptions: GenerateSetOptions{OutputPath: "out/box.rvmat"}},
+			{Name: "box damage", GenerateSetOptions: GenerateSetOptions{OutputPath: "out/Box_Damage.rvmat", DisableDamage: true}},
+		},
+	}
+
+	out := NewMemFileSystem()
+	res, err := GenerateFromManifest(m, &GenerateManifestOptions{Output: out})
+	if !errors.Is(err, ErrOutputCollision) {
+		t.Fatalf("expected ErrOutputCollision, got %v", err)
+	}
+	if res == nil || res.Written || len(out.Files()) != 0 {
+		t.Fatalf("expected nothing written on collision")
+	}
+}
@@ -42,6 +42,24 @@ func Normalize(m *Material, opt *NormalizeOptions) (NormalizeResult, []lint.Diag
 		}
 	}
 
+	if normalizeOptions.InlineTexGens {
+		inlined, removed := inlineStageTexGens(m)
+		result.StageUVsInlined = inlined
+		result.TexGensRemoved = removed
+		if inlined > 0 || removed > 0 {
+			result.Changed = true
+		}
+	}
+
+	if normalizeOptions.ExtractTexGens {
+		extracted, created := extractStageTexGens(m)
+		result.StageUVsExtracted = extracted
+		result.TexGensCreated = created
+		if extracted > 0 {
+			result.Changed = true
+		}
+	}
+
 	if normalizeOptions.StageOrder {
 		if normalizeStageOrder(m) {
 			result.StageOrderNormalized = true
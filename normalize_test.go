@@ -1,6 +1,7 @@
 package rvmat
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
@@ -131,3 +132,211 @@ func TestNormalizeTexturePaths(t *testing.T) {
 		t.Fatalf("unexpected Stage6 texture: %q", mat.Stages[3].Texture.Raw)
 	}
 }
+
+func TestNormalizeExtractTexGens(t *testing.T) {
+	uv := func() *UVTransform {
+		return &UVTransform{
+			Aside: []float64{1, 0, 0},
+			Up:    []float64{0, 1, 0},
+			Dir:   []float64{0, 0, 0},
+			Pos:   []float64{0, 0, 0},
+		}
+	}
+
+	mat := &Material{
+		Stages: []Stage{
+			{Name: "Stage1", UVSource: "tex", UVTransform: uv()},
+			{Name: "Stage2", UVSource: "tex", UVTransform: &UVTransform{
+				Aside: []float64{10, 0, 0},
+				Up:    []float64{0, 10, 0},
+				Dir:   []float64{0, 0, 0},
+				Pos:   []float64{0, 0, 0},
+			}},
+			{Name: "Stage3", UVSource: "tex", UVTransform: uv()},
+			{Name: "Stage4", UVSource: "tex1", UVTransform: uv()},
+			{Name: "StageTI"},
+		},
+	}
+	want := effectiveUVsByStage(t, mat)
+
+	result, issues := Normalize(mat, &NormalizeOptions{ExtractTexGens: true})
+	if len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if !result.Changed || result.StageUVsExtracted != 4 || result.TexGensCreated != 3 {
+		t.Fatalf("unexpected normalize result: %+v", result)
+	}
+	if mat.Stages[0].TexGen != "0" || mat.Stages[2].TexGen != "0" {
+		t.Fatalf("expected identical blocks to share TexGen0: %+v", mat.Stages)
+	}
+	if mat.Stages[1].TexGen != "1" || mat.Stages[3].TexGen != "2" {
+		t.Fatalf("unexpected texGen refs: %q %q", mat.Stages[1].TexGen, mat.Stages[3].TexGen)
+	}
+	if mat.Stages[0].UVSource != "" || mat.Stages[0].UVTransform != nil {
+		t.Fatalf("expected inline UV to be cleared")
+	}
+	if mat.Stages[4].TexGen != "" {
+		t.Fatalf("unexpected texGen on stage without UV: %q", mat.Stages[4].TexGen)
+	}
+	if got := effectiveUVsByStage(t, mat); !reflect.DeepEqual(got, want) {
+		t.Fatalf("effective UV changed:\n got=%v\nwant=%v", got, want)
+	}
+}
+
+func TestNormalizeExtractTexGensReusesExisting(t *testing.T) {
+	mat := &Material{
+		TexGens: []TexGen{
+			{
+				Name:     "TexGen0",
+				UVSource: "tex",
+				UVTransform: &UVTransform{
+					Aside: []float64{1, 0, 0},
+					Up:    []float64{0, 1, 0},
+					Dir:   []float64{0, 0, 0},
+					Pos:   []float64{0, 0, 0},
+				},
+			},
+			{Name: "TexGen1", Base: "TexGen0", UVSource: "tex1"},
+		},
+		Stages: []Stage{
+			{Name: "Stage1", UVSource: "tex1", UVTransform: &UVTransform{
+				Aside: []float64{1, 0, 0},
+				Up:    []float64{0, 1, 0},
+				Dir:   []float64{0, 0, 0},
+				Pos:   []float64{0, 0, 0},
+			}},
+		},
+	}
+
+	result, _ := Normalize(mat, &NormalizeOptions{ExtractTexGens: true})
+	if result.StageUVsExtracted != 1 || result.TexGensCreated != 0 {
+		t.Fatalf("unexpected normalize result: %+v", result)
+	}
+	if mat.Stages[0].TexGen != "1" {
+		t.Fatalf("expected reuse of inherited TexGen1, got %q", mat.Stages[0].TexGen)
+	}
+}
+
+func TestNormalizeInlineTexGens(t *testing.T) {
+	mat := &Material{
+		TexGens: []TexGen{
+			{
+				Name:     "TexGen0",
+				UVSource: "tex",
+				UVTransform: &UVTransform{
+					Aside: []float64{1, 0, 0},
+					Up:    []float64{0, 1, 0},
+					Dir:   []float64{0, 0, 1},
+					Pos:   []float64{0, 0, 0},
+				},
+			},
+			{Name: "TexGen1", Base: "TexGen0", UVTransform: &UVTransform{Aside: []float64{4, 0, 0}}},
+			{Name: "TexGen2", UVSource: "none"},
+		},
+		Stages: []Stage{
+			{Name: "Stage1", TexGen: "0"},
+			{Name: "Stage2", TexGen: "1"},
+			{Name: "Stage3", TexGen: "9"},
+		},
+	}
+	want := effectiveUVsByStage(t, mat)
+
+	result, issues := Normalize(mat, &NormalizeOptions{InlineTexGens: true})
+	if len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if result.StageUVsInlined != 2 || result.TexGensRemoved != 2 {
+		t.Fatalf("unexpected normalize result: %+v", result)
+	}
+	if mat.Stages[1].TexGen != "" || mat.Stages[1].UVSource != "tex" {
+		t.Fatalf("unexpected inlined Stage2: %+v", mat.Stages[1])
+	}
+	if mat.Stages[1].UVTransform == nil || mat.Stages[1].UVTransform.Aside[0] != 4 {
+		t.Fatalf("expected merged uvTransform on Stage2: %+v", mat.Stages[1].UVTransform)
+	}
+	if mat.Stages[2].TexGen != "9" {
+		t.Fatalf("expected unresolved reference to be kept: %q", mat.Stages[2].TexGen)
+	}
+	if len(mat.TexGens) != 1 || mat.TexGens[0].Name != "TexGen2" {
+		t.Fatalf("expected only pre-existing orphan TexGen2 to remain: %+v", mat.TexGens)
+	}
+
+	got := effectiveUVsByStage(t, mat)
+	delete(want, "Stage3")
+	delete(got, "Stage3")
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("effective UV changed:\n got=%v\nwant=%v", got, want)
+	}
+}
+
+func TestNormalizeInlineTexGensKeepsOrphanBase(t *testing.T) {
+	mat := &Material{
+		TexGens: []TexGen{
+			{Name: "TexGen0", UVSource: "tex"},
+			{Name: "TexGen1", Base: "TexGen0", UVSource: "tex"},
+		},
+		Stages: []Stage{{Name: "Stage1", TexGen: "0"}},
+	}
+
+	result, _ := Normalize(mat, &NormalizeOptions{InlineTexGens: true})
+	if result.StageUVsInlined != 1 || result.TexGensRemoved != 0 {
+		t.Fatalf("unexpected normalize result: %+v", result)
+	}
+	if len(mat.TexGens) != 2 {
+		t.Fatalf("expected TexGen0 kept as base of orphan TexGen1: %+v", mat.TexGens)
+	}
+}
+
+func TestNormalizeExtractTexGensRollback(t *testing.T) {
+	// NaN never compares equal, so extraction fails verification.
+	mat := &Material{Stages: []Stage{{
+		Name:        "Stage1",
+		UVSource:    "tex",
+		UVTransform: &UVTransform{Aside: []float64{math.NaN(), 0, 0}},
+	}}}
+
+	result, _ := Normalize(mat, &NormalizeOptions{ExtractTexGens: true})
+	if result.StageUVsExtracted != 0 || result.TexGensCreated != 0 || len(mat.TexGens) != 0 {
+		t.Fatalf("expected rollback without orphan TexGen: %+v (texgens=%+v)", result, mat.TexGens)
+	}
+	if mat.Stages[0].TexGen != "" || mat.Stages[0].UVTransform == nil {
+		t.Fatalf("expected stage restored: %+v", mat.Stages[0])
+	}
+}
+
+func TestNormalizeInlineThenExtractTexGens(t *testing.T) {
+	mat, err := Generate(GenerateOptions{BaseMaterial: BaseMaterialSteel})
+	if err != nil {
+		t.Fatalf("generate material: %v", err)
+	}
+	want := effectiveUVsByStage(t, mat)
+
+	result, _ := Normalize(mat, &NormalizeOptions{InlineTexGens: true, ExtractTexGens: true})
+	if result.StageUVsExtracted != len(mat.Stages) || result.TexGensCreated != 1 {
+		t.Fatalf("unexpected normalize result: %+v", result)
+	}
+	if got := effectiveUVsByStage(t, mat); !reflect.DeepEqual(got, want) {
+		t.Fatalf("effective UV changed:\n got=%v\nwant=%v", got, want)
+	}
+
+	result, _ = Normalize(mat, &NormalizeOptions{InlineTexGens: true})
+	if result.StageUVsInlined != len(mat.Stages) || len(mat.TexGens) != 0 {
+		t.Fatalf("unexpected inline result: %+v (texgens=%d)", result, len(mat.TexGens))
+	}
+	if got := effectiveUVsByStage(t, mat); !reflect.DeepEqual(got, want) {
+		t.Fatalf("effective UV changed after inline:\n got=%v\nwant=%v", got, want)
+	}
+}
+
+// effectiveUVsByStage returns effective stage UV values keyed by stage name.
+func effectiveUVsByStage(t *testing.T, m *Material) map[string]stageUV {
+	t.Helper()
+
+	out := make(map[string]stageUV, len(m.Stages))
+	for _, st := range m.Stages {
+		uv, _ := effectiveStageUV(m, st)
+		out[st.Name] = uv
+	}
+
+	return out
+}
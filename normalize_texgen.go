@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// stageUV holds effective UV values of one stage for equivalence checks.
+type stageUV struct {
+	transform *UVTransform
+	source    string
+}
+
+// extractStageTexGens moves inline stage UV blocks into shared TexGen classes.
+//
+// Identical inline blocks share one TexGen. Existing TexGens whose resolved
+// values match a block are reused instead of creating a new class.
+func extractStageTexGens(m *Material) (extracted, created int) {
+	for i := range m.Stages {
+		st := &m.Stages[i]
+		if strings.TrimSpace(st.TexGen) != "" {
+			continue
+		}
+		if st.UVSource == "" && st.UVTransform == nil {
+			continue
+		}
+
+		before, ok := effectiveStageUV(m, *st)
+		if !ok {
+			continue
+		}
+
+		ref, ok := findMatchingTexGenRef(m, before)
+		added := !ok
+		if added {
+			tg := TexGen{
+				Name:     nextTexGenName(m.TexGens),
+				UVSource: before.source,
+			}
+			if before.transform != nil {
+				uv := cloneUVTransform(*before.transform)
+				tg.UVTransform = &uv
+			}
+
+			m.TexGens = append(m.TexGens, tg)
+			ref = texGenRefForName(tg.Name)
+			created++
+		}
+
+		saved := cloneStage(*st)
+		st.TexGen = ref
+		st.UVSource = ""
+		st.UVTransform = nil
+
+		after, ok := effectiveStageUV(m, *st)
+		if !ok || !reflect.DeepEqual(before, after) {
+			*st = saved
+			if added {
+				m.TexGens = m.TexGens[:len(m.TexGens)-1]
+				created--
+			}
+			continue
+		}
+
+		extracted++
+	}
+
+	return extracted, created
+}
+
+// inlineStageTexGens expands stage TexGen references into inline UV blocks.
+//
+// Stages with unresolved chains or chains carrying unknown fields are kept
+// as-is. TexGens left unreferenced by inlining are removed.
+func inlineStageTexGens(m *Material) (inlined, removed int) {
+	referencedBefore := referencedTexGenNames(m)
+
+	for i := range m.Stages {
+		st := &m.Stages[i]
+		if strings.TrimSpace(st.TexGen) == "" {
+			continue
+		}
+		if texGenChainHasExtras(m, st.TexGen) {
+			continue
+		}
+
+		before, ok := effectiveStageUV(m, *st)
+		if !ok {
+			continue
+		}
+
+		saved := cloneStage(*st)
+		st.TexGen = ""
+		st.UVSource = before.source
+		st.UVTransform = nil
+		if before.transform != nil {
+			uv := cloneUVTransform(*before.transform)
+			st.UVTransform = &uv
+		}
+
+		after, ok := effectiveStageUV(m, *st)
+		if !ok || !reflect.DeepEqual(before, after) {
+			*st = saved
+			continue
+		}
+
+		inlined++
+	}
+
+	if inlined == 0 {
+		return 0, 0
+	}
+
+	referencedAfter := referencedTexGenNames(m)
+	// TexGens kept without stage references still need their Base chain.
+	for _, tg := range m.TexGens {
+		key := strings.ToLower(strings.TrimSpace(tg.Name))
+		if _, wasReferenced := referencedBefore[key]; !wasReferenced && strings.TrimSpace(tg.Base) != "" {
+			addTexGenChain(m, tg.Base, referencedAfter)
+		}
+	}
+
+	kept := m.TexGens[:0]
+	for _, tg := range m.TexGens {
+		key := strings.ToLower(strings.TrimSpace(tg.Name))
+		_, wasReferenced := referencedBefore[key]
+		_, isReferenced := referencedAfter[key]
+		if wasReferenced && !isReferenced {
+			removed++
+			continue
+		}
+
+		kept = append(kept, tg)
+	}
+	m.TexGens = kept
+
+	return inlined, removed
+}
+
+// effectiveStageUV resolves effective stage UV values.
+func effectiveStageUV(m *Material, stage Stage) (stageUV, bool) {
+	source, err := EffectiveUVSource(m, stage)
+	if err != nil {
+		return stageUV{}, false
+	}
+
+	transform, err := EffectiveUVTransform(m, stage)
+	if err != nil {
+		return stageUV{}, false
+	}
+
+	return stageUV{source: source, transform: transform}, true
+}
+
+// findMatchingTexGenRef finds existing TexGen with resolved values equal to uv.
+func findMatchingTexGenRef(m *Material, uv stageUV) (string, bool) {
+	for _, tg := range m.TexGens {
+		if len(tg.extras) > 0 {
+			continue
+		}
+
+		ref := texGenRefForName(tg.Name)
+		candidate, ok := effectiveStageUV(m, Stage{TexGen: ref})
+		if !ok {
+			continue
+		}
+		if reflect.DeepEqual(candidate, uv) {
+			return ref, true
+		}
+	}
+
+	return "", false
+}
+
+// referencedTexGenNames returns lower-case names of TexGens used by stages,
+// including inherited base classes.
+func referencedTexGenNames(m *Material) map[string]struct{} {
+	out := make(map[string]struct{}, len(m.TexGens))
+	for _, st := range m.Stages {
+		if strings.TrimSpace(st.TexGen) == "" {
+			continue
+		}
+
+		leaf, ok := findTexGenByRef(m.TexGens, st.TexGen)
+		if !ok {
+			continue
+		}
+
+		addTexGenChain(m, leaf.Name, out)
+	}
+
+	return out
+}
+
+// addTexGenChain adds name and its Base chain to set.
+func addTexGenChain(m *Material, name string, set map[string]struct{}) {
+	current := name
+	for current != "" {
+		key := strings.ToLower(strings.TrimSpace(current))
+		if _, seen := set[key]; seen {
+			return
+		}
+		set[key] = struct{}{}
+
+		tg, ok := findTexGenByName(m.TexGens, current)
+		if !ok {
+			return
+		}
+		current = strings.TrimSpace(tg.Base)
+	}
+}
+
+// texGenChainHasExtras reports whether referenced TexGen chain keeps unknown fields.
+func texGenChainHasExtras(m *Material, ref string) bool {
+	leaf, ok := findTexGenByRef(m.TexGens, ref)
+	if !ok {
+		return false
+	}
+
+	chain, err := resolveTexGenChain(m.TexGens, leaf.Name)
+	if err != nil {
+		return false
+	}
+
+	for _, tg := range chain {
+		if len(tg.extras) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextTexGenName returns first free canonical "TexGenN" name.
+func nextTexGenName(texgens []TexGen) string {
+	next := 0
+	for _, tg := range texgens {
+		if n, ok := parseIndexedName(tg.Name, "texgen"); ok && n >= next {
+			next = n + 1
+		}
+	}
+
+	return "TexGen" + strconv.Itoa(next)
+}
+
+// texGenRefForName returns stage texGen reference value for TexGen class name.
+//
+// Canonical "TexGenN" names are referenced by index, others by full name.
+func texGenRefForName(name string) string {
+	name = strings.TrimSpace(name)
+	if n, ok := parseIndexedName(name, "texgen"); ok {
+		ref := strconv.Itoa(n)
+		if strings.EqualFold(name, "TexGen"+ref) {
+			return ref
+		}
+	}
+
+	return name
+}
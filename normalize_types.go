@@ -24,6 +24,18 @@ type NormalizeResult struct {
 	// TexturePathsNormalized is count of stage textures normalized to game-style path.
 	TexturePathsNormalized int `json:"texture_paths_normalized,omitempty" yaml:"texture_paths_normalized,omitempty"`
 
+	// StageUVsExtracted is count of stages whose inline UV moved to a TexGen reference.
+	StageUVsExtracted int `json:"stage_uvs_extracted,omitempty" yaml:"stage_uvs_extracted,omitempty"`
+
+	// TexGensCreated is count of TexGen classes created by extraction.
+	TexGensCreated int `json:"texgens_created,omitempty" yaml:"texgens_created,omitempty"`
+
+	// StageUVsInlined is count of stages whose TexGen reference was expanded inline.
+	StageUVsInlined int `json:"stage_uvs_inlined,omitempty" yaml:"stage_uvs_inlined,omitempty"`
+
+	// TexGensRemoved is count of TexGen classes left unreferenced by inlining and removed.
+	TexGensRemoved int `json:"texgens_removed,omitempty" yaml:"texgens_removed,omitempty"`
+
 	// Changed indicates whether any normalization was applied.
 	Changed bool `json:"changed" yaml:"changed"`
 
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultNumberPrecision is default fraction digits for written numbers.
+	defaultNumberPrecision = 4
+	// prettyFloatDigits is fraction digits for procedural texture arguments.
+	prettyFloatDigits = 4
+)
+
+// numberLiteral is parse-time source text of one number position.
+type numberLiteral struct {
+	text       string  // Source text, written verbatim while value is unchanged
+	value      float64 // Parsed or evaluated value (0 for unresolved tokens)
+	unresolved bool    // EvalExpressions could not evaluate token
+}
+
+// numberLiterals maps lower-case key to per-element source literals.
+//
+// Only positions whose source text differs from default formatting carry
+// text, so canonical files parse without literals at all.
+type numberLiterals map[string][]numberLiteral
+
+// formatPrettyFloat formats float values without common IEEE tail noise.
+func formatPrettyFloat(v float64) string {
+	return formatFloatPrecision(v, prettyFloatDigits)
+}
+
+// formatFloatPrecision formats v with at most digits fraction digits and
+// trailing zeros trimmed; negative digits use shortest exact representation.
+func formatFloatPrecision(v float64, digits int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	short := strconv.FormatFloat(v, 'f', digits, 64)
+	if strings.Contains(short, ".") {
+		short = strings.TrimRight(short, "0")
+		short = strings.TrimRight(short, ".")
+	}
+	if short == "" || short == "-0" {
+		short = "0"
+	}
+
+	return short
+}
+
+// numberTokenLiteral returns source literal of array or scalar number token.
+//
+// Quoted expression tokens keep their quotes. ok is false when default
+// formatting of value reproduces token text.
+func numberTokenLiteral(tok token, value float64) (numberLiteral, bool) {
+	text := tok.Lit
+	if tok.Type == tokString {
+		text = quoteConfigString(tok.Lit)
+	}
+	if tok.Type == tokNumber && text == formatFloatPrecision(value, defaultNumberPrecision) {
+		return numberLiteral{}, false
+	}
+
+	return numberLiteral{text: text, value: value}, true
+}
+
+// set stores literals of key; nil list removes key.
+func (l *numberLiterals) set(key string, lits []numberLiteral) {
+	key = strings.ToLower(key)
+	if lits == nil {
+		delete(*l, key)
+		return
+	}
+	if *l == nil {
+		*l = make(numberLiterals, 2)
+	}
+
+	(*l)[key] = lits
+}
+
+// lookup returns literals of key when they still cover n elements.
+func (l numberLiterals) lookup(key string, n int) []numberLiteral {
+	lits := l[strings.ToLower(key)]
+	if len(lits) != n {
+		return nil
+	}
+
+	return lits
+}
+
+// literalText returns preserved source text of element i while value v is unchanged.
+func literalText(lits []numberLiteral, i int, v float64) (string, bool) {
+	if i >= len(lits) || lits[i].text == "" || lits[i].value != v {
+		return "", false
+	}
+
+	return lits[i].text, true
+}
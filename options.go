@@ -5,7 +5,6 @@
 package rvmat
 
 import (
-	"os"
 	"slices"
 	"strings"
 )
@@ -28,6 +27,8 @@ var defaultTrustedPrefixes = []string{`dz\`, `ca\`, `a3\`}
 
 // ParseOptions controls parsing behavior.
 type ParseOptions struct {
+	// ExpressionConstants adds names (e.g. #define macros) for EvalExpressions.
+	ExpressionConstants map[string]float64 `json:"expression_constants,omitempty" yaml:"expression_constants,omitempty"`
 	// DisableCaseInsensitive disables case-insensitive matching for known keys and class names.
 	DisableCaseInsensitive bool `json:"disable_case_insensitive,omitempty" yaml:"disable_case_insensitive,omitempty"`
 	// DisableComments disables // and /* */ comments.
@@ -35,12 +36,22 @@ type ParseOptions struct {
 	// DisableRelaxedNumbers disables non-numeric tokens in numeric arrays (parsed as 0).
 	// Useful for stats/analysis on files with expression strings in arrays.
 	DisableRelaxedNumbers bool `json:"disable_relaxed_numbers,omitempty" yaml:"disable_relaxed_numbers,omitempty"`
+	// EvalExpressions evaluates expression and macro tokens in numeric arrays
+	// and specularPower (see EvalExpression) instead of parsing them as 0.
+	// Tokens that still fail are kept as 0 and reported by Validate.
+	EvalExpressions bool `json:"eval_expressions,omitempty" yaml:"eval_expressions,omitempty"`
 }
 
 // FormatOptions controls writer formatting.
 type FormatOptions struct {
 	// Indent is the indentation string for nested blocks (default is four spaces).
 	Indent string `json:"indent,omitempty" yaml:"indent,omitempty"`
+	// Precision is maximum fraction digits for written numbers (default 4);
+	// negative value writes shortest exact representation. Unchanged parsed
+	// numbers whose source text differs from default formatting (such as
+	// "1.0", extra digits, or expression and macro tokens in arrays) are
+	// written verbatim instead.
+	Precision int `json:"precision,omitempty" yaml:"precision,omitempty"`
 	// CompactStages writes StageN classes in one line when they only contain
 	// texture and texGen assignments.
 	CompactStages bool `json:"compact_stages,omitempty" yaml:"compact_stages,omitempty"`
@@ -48,6 +59,12 @@ type FormatOptions struct {
 
 // ValidateOptions controls validation rules.
 type ValidateOptions struct {
+	// FileSystem is used for texture existence checks and sibling lookups.
+	// Nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
+	// OnFix, when set, receives machine-applicable fix suggestions for
+	// diagnostics returned by Validate.
+	OnFix func(Fix) `json:"-" yaml:"-"`
 	// GameRoot is used to resolve texture paths when file checks are enabled.
 	// For example, if GameRoot is "P:\\", and the texture path is "dz\vehicles\wheeled\offroad_02\data\offroad_02_roof_co.paa",
 	GameRoot string `json:"game_root,omitempty" yaml:"game_root,omitempty"`
@@ -66,6 +83,9 @@ type ValidateOptions struct {
 	DisableShaderNameCheck bool `json:"disable_shader_name_check,omitempty" yaml:"disable_shader_name_check,omitempty"`
 	// EnableShaderProfileCheck enables soft stage profile checks for known shaders.
 	EnableShaderProfileCheck bool `json:"enable_shader_profile_check,omitempty" yaml:"enable_shader_profile_check,omitempty"`
+	// EnableShaderCaseCheck reports known shader IDs written in non-canonical
+	// casing (e.g. "super") as RVMAT2029/RVMAT2030 info diagnostics.
+	EnableShaderCaseCheck bool `json:"enable_shader_case_check,omitempty" yaml:"enable_shader_case_check,omitempty"`
 }
 
 // TextureValidateOptions controls validation of procedural textures.
@@ -88,6 +108,19 @@ type NormalizeOptions struct {
 	TexGenOrder bool `json:"texgen_order,omitempty" yaml:"texgen_order,omitempty"`
 	// TexturePaths normalizes stage texture paths to game-style form.
 	TexturePaths bool `json:"texture_paths,omitempty" yaml:"texture_paths,omitempty"`
+	// ExtractTexGens moves inline stage uvSource/uvTransform blocks into
+	// shared TexGen classes; identical blocks reuse one TexGen.
+	ExtractTexGens bool `json:"extract_texgens,omitempty" yaml:"extract_texgens,omitempty"`
+	// InlineTexGens expands resolved stage TexGen references back into
+	// inline uvSource/uvTransform blocks.
+	// When combined with ExtractTexGens, inlining runs first.
+	InlineTexGens bool `json:"inline_texgens,omitempty" yaml:"inline_texgens,omitempty"`
+}
+
+// ApplyFixesOptions controls ApplyFixes behavior.
+type ApplyFixesOptions struct {
+	// Unsafe also applies fixes not marked as safe (e.g. duplicate stage removal).
+	Unsafe bool `json:"unsafe,omitempty" yaml:"unsafe,omitempty"`
 }
 
 // IsGameRootExist reports whether the game root exists and is a directory.
@@ -98,7 +131,7 @@ func (o *ValidateOptions) IsGameRootExist() bool {
 	if strings.TrimSpace(o.GameRoot) == "" {
 		return false
 	}
-	info, err := os.Stat(o.GameRoot)
+	info, err := fileSystemOrOS(o.FileSystem).Stat(o.GameRoot)
 	if err != nil {
 		return false
 	}
@@ -118,13 +151,16 @@ func (o *ParseOptions) normalize() ParseOptions {
 // normalize normalizes the FormatOptions.
 func (o *FormatOptions) normalize() FormatOptions {
 	if o == nil {
-		return FormatOptions{Indent: "    "}
+		return FormatOptions{Indent: "    ", Precision: defaultNumberPrecision}
 	}
 
 	out := *o
 	if out.Indent == "" {
 		out.Indent = "    "
 	}
+	if out.Precision == 0 {
+		out.Precision = defaultNumberPrecision
+	}
 
 	return out
 }
@@ -185,3 +221,12 @@ func (o *NormalizeOptions) normalize() NormalizeOptions {
 
 	return *o
 }
+
+// normalize normalizes the ApplyFixesOptions.
+func (o *ApplyFixesOptions) normalize() ApplyFixesOptions {
+	if o == nil {
+		return ApplyFixesOptions{}
+	}
+
+	return *o
+}
@@ -0,0 +1,357 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// P3DFormatMLOD marks editable (unbinarized) model.
+	P3DFormatMLOD = "MLOD"
+
+	// P3DFormatODOL marks binarized model.
+	P3DFormatODOL = "ODOL"
+
+	// p3dFaceSize is fixed MLOD face part: vertex count, 4 vertices, flags.
+	p3dFaceSize = 4 + 4*16 + 4
+
+	// p3dMaxLODs limits LOD count to reject garbage input.
+	p3dMaxLODs = 4096
+)
+
+// P3D is read-only view of model texture and material references.
+type P3D struct {
+	Format    string   `json:"format" yaml:"format"`                           // P3DFormatMLOD or P3DFormatODOL
+	LODs      []P3DLOD `json:"lods,omitempty" yaml:"lods,omitempty"`           // MLOD LODs; empty for ODOL
+	Textures  []string `json:"textures,omitempty" yaml:"textures,omitempty"`   // Unique texture paths
+	Materials []string `json:"materials,omitempty" yaml:"materials,omitempty"` // Unique material paths
+	Version   uint32   `json:"version" yaml:"version"`                         // Container version
+}
+
+// P3DLOD is one model LOD.
+type P3DLOD struct {
+	Pairs      []P3DFacePair  `json:"pairs,omitempty" yaml:"pairs,omitempty"`           // Unique texture/material pairs
+	Selections []P3DSelection `json:"selections,omitempty" yaml:"selections,omitempty"` // Named selections
+	Resolution float64        `json:"resolution" yaml:"resolution"`                     // LOD resolution or special LOD value
+}
+
+// P3DFacePair is texture and material used together by LOD faces.
+type P3DFacePair struct {
+	Texture  string `json:"texture,omitempty" yaml:"texture,omitempty"`   // Face texture path
+	Material string `json:"material,omitempty" yaml:"material,omitempty"` // Face material path
+	Faces    int    `json:"faces" yaml:"faces"`                           // Number of faces
+}
+
+// P3DSelection is named selection with materials of its faces.
+type P3DSelection struct {
+	Name      string   `json:"name" yaml:"name"`                               // Selection name (e.g. zbytek)
+	Materials []string `json:"materials,omitempty" yaml:"materials,omitempty"` // Unique materials of selected faces
+	Faces     int      `json:"faces" yaml:"faces"`                             // Number of selected faces
+}
+
+// ParseP3D reads texture/material references from MLOD or ODOL model bytes.
+//
+// MLOD models yield per-LOD face pairs and named selections. ODOL models
+// are binarized with version-specific layout; only Textures and Materials
+// are collected by scanning for NUL-terminated path strings, without LOD or
+// selection data.
+func ParseP3D(data []byte) (*P3D, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("%w: file too short", ErrInvalidP3D)
+	}
+
+	model := &P3D{Format: string(data[:4]), Version: binary.LittleEndian.Uint32(data[4:8])}
+	switch model.Format {
+	case P3DFormatMLOD:
+		r := &p3dReader{data: data, pos: 8}
+		if err := r.mlod(model); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidP3D, err)
+		}
+	case P3DFormatODOL:
+		scanP3DStrings(model, data[8:])
+	default:
+		return nil, fmt.Errorf("%w: unknown signature %q", ErrInvalidP3D, data[:4])
+	}
+
+	return model, nil
+}
+
+// DecodeP3DFile reads texture/material references from a model file.
+func DecodeP3DFile(path string) (*P3D, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseP3D(b)
+}
+
+// SelectionsForMaterial returns sorted unique names of selections whose
+// faces use material, over all LODs. Paths compare in game-path form.
+func (m *P3D) SelectionsForMaterial(material string) []string {
+	want := NormalizeGameTexturePath(material)
+	var out []string
+	for _, lod := range m.LODs {
+		for _, sel := range lod.Selections {
+			if slices.ContainsFunc(sel.Materials, func(s string) bool { return NormalizeGameTexturePath(s) == want }) &&
+				!slices.Contains(out, sel.Name) {
+				out = append(out, sel.Name)
+			}
+		}
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+// UsesMaterial reports whether any model face references material.
+func (m *P3D) UsesMaterial(material string) bool {
+	want := NormalizeGameTexturePath(material)
+	return slices.ContainsFunc(m.Materials, func(s string) bool { return NormalizeGameTexturePath(s) == want })
+}
+
+// p3dReader reads little-endian MLOD structures.
+type p3dReader struct {
+	data []byte // Model bytes
+	pos  int    // Current offset
+}
+
+// mlod reads MLOD LOD list.
+func (r *p3dReader) mlod(model *P3D) error {
+	count, err := r.uint32()
+	if err != nil {
+		return err
+	}
+	if count > p3dMaxLODs {
+		return fmt.Errorf("LOD count %d too large", count)
+	}
+
+	for i := range int(count) {
+		lod, err := r.lod()
+		if err != nil {
+			return fmt.Errorf("LOD %d: %w", i, err)
+		}
+
+		model.LODs = append(model.LODs, lod)
+		for _, pair := range lod.Pairs {
+			if pair.Texture != "" && !slices.Contains(model.Textures, pair.Texture) {
+				model.Textures = append(model.Textures, pair.Texture)
+			}
+			if pair.Material != "" && !slices.Contains(model.Materials, pair.Material) {
+				model.Materials = append(model.Materials, pair.Material)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lod reads one P3DM LOD with faces and TAGG section.
+func (r *p3dReader) lod() (P3DLOD, error) {
+	var lod P3DLOD
+	sig, err := r.bytes(4)
+	if err != nil {
+		return lod, err
+	}
+	if string(sig) != "P3DM" {
+		return lod, fmt.Errorf("unsupported LOD signature %q", sig)
+	}
+
+	// Major and minor version.
+	if _, err := r.bytes(8); err != nil {
+		return lod, err
+	}
+
+	var counts [4]uint32 // points, normals, faces, flags
+	for i := range counts {
+		if counts[i], err = r.uint32(); err != nil {
+			return lod, err
+		}
+	}
+	points, normals, faces := int(counts[0]), int(counts[1]), int(counts[2])
+	if err := r.skip(points, 16); err != nil {
+		return lod, fmt.Errorf("points: %w", err)
+	}
+	if err := r.skip(normals, 12); err != nil {
+		return lod, fmt.Errorf("normals: %w", err)
+	}
+	if faces > (len(r.data)-r.pos)/(p3dFaceSize+2) {
+		return lod, fmt.Errorf("face count %d exceeds data size", faces)
+	}
+
+	// Face materials are kept to resolve named selections.
+	faceMaterials := make([]string, faces)
+	pairIndex := make(map[[2]string]int)
+	for i := range faces {
+		if _, err := r.bytes(p3dFaceSize); err != nil {
+			return lod, err
+		}
+		texture, err := r.asciiz()
+		if err != nil {
+			return lod, err
+		}
+		material, err := r.asciiz()
+		if err != nil {
+			return lod, err
+		}
+
+		faceMaterials[i] = material
+		key := [2]string{texture, material}
+		if j, ok := pairIndex[key]; ok {
+			lod.Pairs[j].Faces++
+			continue
+		}
+		pairIndex[key] = len(lod.Pairs)
+		lod.Pairs = append(lod.Pairs, P3DFacePair{Texture: texture, Material: material, Faces: 1})
+	}
+
+	if err := r.tags(&lod, points, faceMaterials); err != nil {
+		return lod, err
+	}
+
+	resolution, err := r.uint32()
+	if err != nil {
+		return lod, err
+	}
+	lod.Resolution = float64(math.Float32frombits(resolution))
+
+	return lod, nil
+}
+
+// tags reads TAGG section and collects named selections.
+func (r *p3dReader) tags(lod *P3DLOD, points int, faceMaterials []string) error {
+	sig, err := r.bytes(4)
+	if err != nil {
+		return err
+	}
+	if string(sig) != "TAGG" {
+		return fmt.Errorf("missing TAGG section, got %q", sig)
+	}
+
+	for {
+		// Active flag.
+		if _, err := r.bytes(1); err != nil {
+			return err
+		}
+		name, err := r.asciiz()
+		if err != nil {
+			return err
+		}
+		size, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		data, err := r.bytes(int(size))
+		if err != nil {
+			return fmt.Errorf("tag %q: %w", name, err)
+		}
+
+		if name == "#EndOfFile#" {
+			return nil
+		}
+		// Named selections have no '#' prefix; data is point then face weights.
+		if strings.HasPrefix(name, "#") || len(data) != points+len(faceMaterials) {
+			continue
+		}
+
+		sel := P3DSelection{Name: name}
+		for i, w := range data[points:] {
+			if w == 0 {
+				continue
+			}
+			sel.Faces++
+			if m := faceMaterials[i]; m != "" && !slices.Contains(sel.Materials, m) {
+				sel.Materials = append(sel.Materials, m)
+			}
+		}
+		lod.Selections = append(lod.Selections, sel)
+	}
+}
+
+// uint32 reads little-endian uint32.
+func (r *p3dReader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// bytes returns next n bytes.
+func (r *p3dReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, fmt.Errorf("unexpected end of data at %d", r.pos)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+
+	return b, nil
+}
+
+// skip advances over count fixed-size records.
+func (r *p3dReader) skip(count, size int) error {
+	if count < 0 || count > (len(r.data)-r.pos)/size {
+		return fmt.Errorf("count %d exceeds data size", count)
+	}
+	r.pos += count * size
+
+	return nil
+}
+
+// asciiz reads NUL-terminated string.
+func (r *p3dReader) asciiz() (string, error) {
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		return "", errors.New("unterminated string")
+	}
+	s := string(r.data[r.pos : r.pos+end])
+	r.pos += end + 1
+
+	return s, nil
+}
+
+// scanP3DStrings collects texture and material paths from binarized data.
+// It is a heuristic: only NUL-terminated runs of path bytes with directory
+// separator and known extension are kept.
+func scanP3DStrings(model *P3D, data []byte) {
+	for chunk := range bytes.SplitSeq(data, []byte{0}) {
+		// Keep trailing path-like run; binary prefix bytes are not part of it.
+		start := len(chunk)
+		for start > 0 && isP3DPathByte(chunk[start-1]) {
+			start--
+		}
+		s := string(chunk[start:])
+		if len(s) < 5 || !strings.ContainsAny(s, `\/`) {
+			continue
+		}
+
+		ext := strings.ToLower(s[strings.LastIndexByte(s, '.')+1:])
+		switch {
+		case ext == "rvmat":
+			if !slices.Contains(model.Materials, s) {
+				model.Materials = append(model.Materials, s)
+			}
+		case hasAllowedExt(s):
+			if !slices.Contains(model.Textures, s) {
+				model.Textures = append(model.Textures, s)
+			}
+		}
+	}
+}
+
+// isP3DPathByte reports byte allowed in scanned model paths.
+func isP3DPathByte(c byte) bool {
+	return c == '_' || c == '.' || c == '\\' || c == '/' || c == '-' ||
+		isDigit(c) || isASCIIAlpha(c)
+}
@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+// destructSelectionName is named selection hidden when object is destroyed.
+const destructSelectionName = "zbytek"
+
+// ModelScanOptions controls ScanModelDirectory.
+type ModelScanOptions struct {
+	// FileSystem is walked for models and materials.
+	// Nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
+	// GameRoot resolves model material references for missing checks;
+	// empty value disables Missing report.
+	GameRoot string `json:"game_root,omitempty" yaml:"game_root,omitempty"`
+}
+
+// ModelScanEntry is one scanned model.
+type ModelScanEntry struct {
+	// Model is parsed model references.
+	Model *P3D `json:"model" yaml:"model"`
+	// Path is model file path.
+	Path string `json:"path" yaml:"path"`
+}
+
+// ModelMissingMaterial is model reference to material file that does not exist.
+type ModelMissingMaterial struct {
+	// Model is model file path.
+	Model string `json:"model" yaml:"model"`
+	// Material is referenced material path as stored in model.
+	Material string `json:"material" yaml:"material"`
+}
+
+// ModelScanFailure is model file that could not be read.
+type ModelScanFailure struct {
+	// Path is model file path.
+	Path string `json:"path" yaml:"path"`
+	// Reason explains read or parse failure.
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// ModelScan is material reference report of model directory.
+type ModelScan struct {
+	// Models lists parsed models sorted by path.
+	Models []ModelScanEntry `json:"models,omitempty" yaml:"models,omitempty"`
+	// Orphans lists .rvmat files under root not referenced by any model.
+	Orphans []string `json:"orphans,omitempty" yaml:"orphans,omitempty"`
+	// Missing lists model material references without file (needs GameRoot).
+	Missing []ModelMissingMaterial `json:"missing,omitempty" yaml:"missing,omitempty"`
+	// Failed lists models that could not be read, with reason.
+	Failed []ModelScanFailure `json:"failed,omitempty" yaml:"failed,omitempty"`
+}
+
+// ScanModelDirectory walks root recursively, reads .p3d models, and
+// reports orphaned .rvmat files and model references to missing materials.
+//
+// Model references are game paths; a material file counts as referenced
+// when its path ends with the reference (compared in game-path form).
+func ScanModelDirectory(root string, opt *ModelScanOptions) (*ModelScan, error) {
+	o := ModelScanOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	fsys := fileSystemOrOS(o.FileSystem)
+
+	var models, materials []string
+	if err := walkModelDirectory(fsys, filepath.Clean(root), &models, &materials); err != nil {
+		return nil, fmt.Errorf("scan model directory: %w", err)
+	}
+	slices.Sort(models)
+	slices.Sort(materials)
+
+	scan := &ModelScan{}
+	referenced := make(map[string]struct{})
+	resolver := PathResolver{GameRoot: o.GameRoot, FileSystem: o.FileSystem}
+	for _, path := range models {
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			scan.Failed = append(scan.Failed, ModelScanFailure{Path: path, Reason: err.Error()})
+			continue
+		}
+		model, err := ParseP3D(data)
+		if err != nil {
+			scan.Failed = append(scan.Failed, ModelScanFailure{Path: path, Reason: err.Error()})
+			continue
+		}
+
+		scan.Models = append(scan.Models, ModelScanEntry{Path: path, Model: model})
+		for _, material := range model.Materials {
+			referenced[NormalizeGameTexturePath(material)] = struct{}{}
+			if o.GameRoot != "" && !resolver.Exists(material) {
+				scan.Missing = append(scan.Missing, ModelMissingMaterial{Model: path, Material: material})
+			}
+		}
+	}
+
+	for _, path := range materials {
+		if !isReferencedGamePath(path, referenced) {
+			scan.Orphans = append(scan.Orphans, path)
+		}
+	}
+
+	return scan, nil
+}
+
+// walkModelDirectory collects .p3d and .rvmat files under dir.
+func walkModelDirectory(fsys FileSystem, dir string, models, materials *[]string) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		full := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if err := walkModelDirectory(fsys, full, models, materials); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".p3d":
+			*models = append(*models, full)
+		case ".rvmat":
+			*materials = append(*materials, full)
+		}
+	}
+
+	return nil
+}
+
+// isReferencedGamePath reports whether any tail of path at separator
+// boundary is in referenced game paths.
+func isReferencedGamePath(path string, referenced map[string]struct{}) bool {
+	key := NormalizeGameTexturePath(path)
+	for {
+		if _, ok := referenced[key]; ok {
+			return true
+		}
+
+		i := strings.IndexByte(key, '\\')
+		if i < 0 {
+			return false
+		}
+		key = key[i+1:]
+	}
+}
+
+// CheckGenerateSetModel checks generated materials against model faces.
+//
+// Main material must be used by model faces. Damage variant needs a named
+// selection with those faces to be swapped on, and destruct variant needs
+// the "zbytek" selection to contain them. Binarized models carry no parsed
+// selections, so variants are reported as unchecked instead.
+func CheckGenerateSetModel(result *GenerateSetResult, model *P3D) []lint.Diagnostic {
+	if result == nil || model == nil || result.MainOutputPath == "" {
+		return nil
+	}
+
+	material, ok := modelMaterialForOutput(model, result.MainOutputPath)
+	if !ok {
+		return []lint.Diagnostic{warningDiagnostic(
+			CodeValidateModelMaterialUnused,
+			"material not used by model",
+			result.MainOutputPath,
+		)}
+	}
+	if model.Format != P3DFormatMLOD {
+		// Binarized models carry no parsed selection data to check variants against.
+		if result.Damage == nil && result.Destruct == nil {
+			return nil
+		}
+
+		return []lint.Diagnostic{infoDiagnostic(
+			CodeValidateModelSelectionsUnavailable,
+			"binarized model has no selection data; variants not checked",
+			result.MainOutputPath,
+		)}
+	}
+
+	var out []lint.Diagnostic
+	selections := model.SelectionsForMaterial(material)
+	if result.Damage != nil && len(selections) == 0 {
+		out = append(out, warningDiagnostic(
+			CodeValidateModelVariantSelectionMissing,
+			"damage variant has no named selection using material",
+			result.DamageOutputPath,
+		))
+	}
+	if result.Destruct != nil && !slices.ContainsFunc(selections, func(s string) bool {
+		return strings.EqualFold(s, destructSelectionName)
+	}) {
+		out = append(out, warningDiagnostic(
+			CodeValidateModelVariantSelectionMissing,
+			"destruct variant has no "+destructSelectionName+" selection using material",
+			result.DestructOutputPath,
+		))
+	}
+
+	return out
+}
+
+// modelMaterialForOutput returns model material reference matching output path.
+func modelMaterialForOutput(model *P3D, output string) (string, bool) {
+	for _, material := range model.Materials {
+		if isReferencedGamePath(output, map[string]struct{}{NormalizeGameTexturePath(material): {}}) {
+			return material, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,191 @@
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// testP3DFace is one face of test MLOD LOD.
+type testP3DFace struct {
+	texture  string
+	material string
+}
+
+// testMLOD builds single-LOD MLOD with faces and named selections by face index.
+func testMLOD(faces []testP3DFace, selections map[string][]int) []byte {
+	var b bytes.Buffer
+	u32 := func(v uint32) { _ = binary.Write(&b, binary.LittleEndian, v) }
+	str := func(s string) { b.WriteString(s); b.WriteByte(0) }
+	const points = 3
+
+	b.WriteString(P3DFormatMLOD)
+	u32(257)
+	u32(1)
+
+	b.WriteString("P3DM")
+	u32(0x1c)
+	u32(0x100)
+	u32(points)
+	u32(0)
+	u32(uint32(len(faces)))
+	u32(0)
+	b.Write(make([]byte, points*16))
+	for _, f := range faces {
+		b.Write(make([]byte, p3dFaceSize))
+		str(f.texture)
+		str(f.material)
+	}
+
+	b.WriteString("TAGG")
+	names := make([]string, 0, len(selections))
+	for name := range selections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		b.WriteByte(1)
+		str(name)
+		weights := make([]byte, points+len(faces))
+		for _, i := range selections[name] {
+			weights[points+i] = 1
+		}
+		u32(uint32(len(weights)))
+		b.Write(weights)
+	}
+	b.WriteByte(1)
+	str("#Mass#")
+	u32(4)
+	u32(0)
+	b.WriteByte(1)
+	str("#EndOfFile#")
+	u32(0)
+
+	u32(math.Float32bits(1))
+	return b.Bytes()
+}
+
+func TestParseP3D(t *testing.T) {
+	data := testMLOD([]testP3DFace{
+		{texture: `mod\data\crate_co.paa`, material: `mod\data\crate.rvmat`},
+		{texture: `mod\data\crate_co.paa`, material: `mod\data\crate.rvmat`},
+		{texture: `mod\data\glass_ca.paa`, material: `mod\data\glass.rvmat`},
+	}, map[string][]int{"zbytek": {0, 1}, "glass": {2}})
+
+	model, err := ParseP3D(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if model.Format != P3DFormatMLOD || len(model.LODs) != 1 || model.LODs[0].Resolution != 1 {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+
+	lod := model.LODs[0]
+	if len(lod.Pairs) != 2 || lod.Pairs[0].Faces != 2 || lod.Pairs[1].Material != `mod\data\glass.rvmat` {
+		t.Fatalf("unexpected pairs: %+v", lod.Pairs)
+	}
+	if len(lod.Selections) != 2 || lod.Selections[1].Name != "zbytek" || lod.Selections[1].Faces != 2 {
+		t.Fatalf("unexpected selections: %+v", lod.Selections)
+	}
+	if got := model.SelectionsForMaterial("MOD/Data/Glass.rvmat"); !slices.Equal(got, []string{"glass"}) {
+		t.Fatalf("unexpected selections for glass: %v", got)
+	}
+	if !model.UsesMaterial(`\mod\data\crate.rvmat`) || model.UsesMaterial(`mod\data\other.rvmat`) {
+		t.Fatalf("unexpected UsesMaterial result")
+	}
+
+	if _, err := ParseP3D(data[:len(data)-10]); !errors.Is(err, ErrInvalidP3D) {
+		t.Fatalf("expected ErrInvalidP3D for truncated data, got %v", err)
+	}
+	if _, err := ParseP3D([]byte("NOPE\x00\x00\x00\x00")); !errors.Is(err, ErrInvalidP3D) {
+		t.Fatalf("expected ErrInvalidP3D for unknown signature, got %v", err)
+	}
+}
+
+func TestParseP3DBinarized(t *testing.T) {
+	data := []byte("ODOL\x31\x00\x00\x00\x02\x00\x00\x00\x07mod\\data\\crate_co.paa\x00\x01\x9fmod\\data\\crate.rvmat\x00junk\x00" +
+		"\x01mod\\data\\bad co.paa\x00nosep_co.paa\x00")
+	model, err := ParseP3D(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if model.Format != P3DFormatODOL || len(model.LODs) != 0 {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+	if !slices.Equal(model.Textures, []string{`mod\data\crate_co.paa`}) ||
+		!slices.Equal(model.Materials, []string{`mod\data\crate.rvmat`}) {
+		t.Fatalf("unexpected references: %v %v", model.Textures, model.Materials)
+	}
+}
+
+func TestScanModelDirectory(t *testing.T) {
+	fsys := NewMemFileSystem()
+	fsys.AddFile(`P:\mod\data\crate.p3d`, testMLOD([]testP3DFace{
+		{texture: `mod\data\crate_co.paa`, material: `mod\data\crate.rvmat`},
+		{texture: `mod\data\crate_co.paa`, material: `mod\data\missing.rvmat`},
+	}, nil))
+	fsys.AddFile(`P:\mod\data\broken.p3d`, []byte("MLOD"))
+	fsys.AddFile(`P:\mod\data\crate.rvmat`, []byte("PixelShaderID=\"Super\";\n"))
+	fsys.AddFile(`P:\mod\data\unused.rvmat`, []byte("PixelShaderID=\"Super\";\n"))
+
+	scan, err := ScanModelDirectory(`P:\mod`, &ModelScanOptions{FileSystem: fsys, GameRoot: `P:\`})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(scan.Models) != 1 || len(scan.Failed) != 1 {
+		t.Fatalf("unexpected models or failures: %+v", scan)
+	}
+	if len(scan.Orphans) != 1 || NormalizeGameTexturePath(scan.Orphans[0]) != `mod\data\unused.rvmat` {
+		t.Fatalf("unexpected orphans: %v", scan.Orphans)
+	}
+	if len(scan.Missing) != 1 || scan.Missing[0].Material != `mod\data\missing.rvmat` {
+		t.Fatalf("unexpected missing: %+v", scan.Missing)
+	}
+}
+
+func TestCheckGenerateSetModel(t *testing.T) {
+	model, err := ParseP3D(testMLOD([]testP3DFace{
+		{texture: `mod\data\crate_co.paa`, material: `mod\data\crate.rvmat`},
+	}, map[string][]int{"damage": {0}}))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	result := &GenerateSetResult{
+		Main:               &Material{},
+		Damage:             &Material{},
+		Destruct:           &Material{},
+		MainOutputPath:     "out/mod/data/crate.rvmat",
+		DamageOutputPath:   "out/mod/data/crate_damage.rvmat",
+		DestructOutputPath: "out/mod/data/crate_destruct.rvmat",
+	}
+	diags := CheckGenerateSetModel(result, model)
+	if len(diags) != 1 || diags[0].Code != mustRuleCode(t, CodeValidateModelVariantSelectionMissing) ||
+		diags[0].Path != result.DestructOutputPath {
+		t.Fatalf("expected missing zbytek diagnostic, got %+v", diags)
+	}
+
+	result.MainOutputPath = "out/mod/data/other.rvmat"
+	diags = CheckGenerateSetModel(result, model)
+	if len(diags) != 1 || diags[0].Code != mustRuleCode(t, CodeValidateModelMaterialUnused) {
+		t.Fatalf("expected unused material diagnostic, got %+v", diags)
+	}
+
+	odol, err := ParseP3D([]byte("ODOL\x31\x00\x00\x00\x01mod\\data\\crate.rvmat\x00"))
+	if err != nil {
+		t.Fatalf("parse ODOL: %v", err)
+	}
+	result.MainOutputPath = "out/mod/data/crate.rvmat"
+	diags = CheckGenerateSetModel(result, odol)
+	if len(diags) != 1 || diags[0].Code != mustRuleCode(t, CodeValidateModelSelectionsUnavailable) {
+		t.Fatalf("expected unchecked variants diagnostic, got %+v", diags)
+	}
+
+	result.Damage, result.Destruct = nil, nil
+	if diags = CheckGenerateSetModel(result, odol); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics without variants: %+v", diags)
+	}
+}
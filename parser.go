@@ -59,10 +59,11 @@ func parseMaterialBytes(data []byte, popt ParseOptions) (*Material, error) {
 
 // parser represents a parser for the RVMAT file.
 type parser struct {
-	l   *lexer       // Lexer for the RVMAT file
-	buf token        // Buffered token
-	has bool         // Has buffered token
-	opt ParseOptions // Options for the parser
+	l     *lexer       // Lexer for the RVMAT file
+	spans []sourceSpan // Statement spans for suppression targets
+	back  []token      // Pushed-back tokens, last one is returned first
+	last  token        // Last consumed token
+	opt   ParseOptions // Options for the parser
 }
 
 // newParser creates a new parser for the RVMAT file.
@@ -72,18 +73,46 @@ func newParser(r io.Reader, opt ParseOptions) *parser {
 
 // next returns the next token from the RVMAT file.
 func (p *parser) next() (token, error) {
-	if p.has {
-		p.has = false
-		return p.buf, nil
+	if n := len(p.back); n > 0 {
+		tok := p.back[n-1]
+		p.back = p.back[:n-1]
+		p.last = tok
+		return tok, nil
 	}
 
-	return p.l.next()
+	tok, err := p.l.next()
+	if err == nil {
+		p.last = tok
+	}
+
+	return tok, err
+}
+
+// statementStart returns first token of next statement without consuming it.
+func (p *parser) statementStart() token {
+	tok, _ := p.peek()
+	return tok
+}
+
+// recordSpan records statement span from start token to last consumed token.
+func (p *parser) recordSpan(start token) sourceSpan {
+	span := sourceSpan{
+		startLine: start.Line,
+		startCol:  start.Col,
+		endLine:   p.last.Line,
+		endCol:    p.last.Col,
+		start:     start.Offset,
+		end:       p.last.End,
+	}
+	p.spans = append(p.spans, span)
+
+	return span
 }
 
 // peek returns the next token from the RVMAT file without consuming it.
 func (p *parser) peek() (token, error) {
-	if p.has {
-		return p.buf, nil
+	if n := len(p.back); n > 0 {
+		return p.back[n-1], nil
 	}
 
 	tok, err := p.l.next()
@@ -91,43 +120,100 @@ func (p *parser) peek() (token, error) {
 		return tok, err
 	}
 
-	p.buf = tok
-	p.has = true
+	p.back = append(p.back, tok)
 	return tok, nil
 }
 
+// unread pushes consumed tokens back in reverse so they are read again in order.
+func (p *parser) unread(toks ...token) {
+	for i := len(toks) - 1; i >= 0; i-- {
+		p.back = append(p.back, toks[i])
+	}
+}
+
 // parseMaterial parses the material from the RVMAT file.
 func (p *parser) parseMaterial() (*Material, error) {
-	m := &Material{}
+	m := &Material{source: &sourceInfo{}}
+	if err := p.parseMaterialBody(m, tokEOF, nil); err != nil {
+		return nil, err
+	}
+
+	p.finishMaterial(m, 1, 1)
+	return m, nil
+}
+
+// parseMaterialBody parses material statements until end token, which is
+// left unconsumed. When seen is set, it stops before a statement whose key
+// was already seen and records keys of parsed statements.
+func (p *parser) parseMaterialBody(m *Material, end tokenType, seen map[string]struct{}) error {
 	for {
 		tok, err := p.peek()
 		if err != nil {
-			return nil, err
+			return err
 		}
-		if tok.Type == tokEOF {
-			break
+		if tok.Type == end || tok.Type == tokEOF {
+			return nil
+		}
+
+		if seen != nil {
+			key, err := p.statementKey()
+			if err != nil {
+				return err
+			}
+			if _, ok := seen[key]; ok {
+				return nil
+			}
+			seen[key] = struct{}{}
 		}
 
 		if tok.Type == tokClass {
 			// Top-level classes are either StageX/TexGenX or unknown blocks.
 			if err := p.parseTopClass(m); err != nil {
-				return nil, err
+				return err
 			}
 			continue
 		}
 
 		// Parse top-level assignments.
 		if err := p.parseTopAssign(m); err != nil {
-			return nil, err
+			return err
 		}
+		m.source.block.setKey(tok.Lit, p.recordSpan(tok))
 	}
+}
 
-	return m, nil
+// statementKey returns lower-case assignment or class name of next statement.
+func (p *parser) statementKey() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if tok.Type != tokClass {
+		p.unread(tok)
+		return strings.ToLower(tok.Lit), nil
+	}
+
+	name, err := p.peek()
+	p.unread(tok)
+	if err != nil {
+		return "", err
+	}
+
+	return "class " + strings.ToLower(name.Lit), nil
+}
+
+// finishMaterial attaches source span and collected suppressions to
+// material whose source starts at line and col.
+func (p *parser) finishMaterial(m *Material, line, col int) {
+	m.source.block.span = sourceSpan{startLine: line, startCol: col, endLine: p.last.Line, endCol: p.last.Col}
+	m.source.suppressions = p.l.directives
+	resolveSuppressionTargets(m.source.suppressions, p.spans)
 }
 
 // parseTopClass parses a top-level class.
 func (p *parser) parseTopClass(m *Material) error {
-	if _, err := p.expect(tokClass); err != nil {
+	start, err := p.expect(tokClass)
+	if err != nil {
 		return err
 	}
 
@@ -156,6 +242,7 @@ func (p *parser) parseTopClass(m *Material) error {
 			return err
 		}
 
+		st.source.span = p.recordSpan(start)
 		m.Stages = append(m.Stages, st)
 		return nil
 	}
@@ -167,6 +254,7 @@ func (p *parser) parseTopClass(m *Material) error {
 			return err
 		}
 
+		tg.source.span = p.recordSpan(start)
 		m.TexGens = append(m.TexGens, tg)
 		return nil
 	}
@@ -177,6 +265,7 @@ func (p *parser) parseTopClass(m *Material) error {
 		return err
 	}
 
+	p.recordSpan(start)
 	m.extras = append(m.extras, cn)
 	return nil
 }
@@ -226,7 +315,7 @@ func (p *parser) parseStageBody(name string) (Stage, error) {
 	}
 
 	// Parse stage body
-	st := Stage{Name: name}
+	st := Stage{Name: name, source: &sourceBlock{}}
 	for {
 		tok, err := p.peek()
 		if err != nil {
@@ -250,6 +339,7 @@ func (p *parser) parseStageBody(name string) (Stage, error) {
 		if err := p.parseStageAssign(&st); err != nil {
 			return Stage{}, err
 		}
+		st.source.setKey(tok.Lit, p.recordSpan(tok))
 	}
 
 	if _, err := p.expect(tokSemicolon); err != nil {
@@ -267,7 +357,7 @@ func (p *parser) parseTexGenBody(name, base string) (TexGen, error) {
 	}
 
 	// Parse texture generator body
-	tg := TexGen{Name: name, Base: base}
+	tg := TexGen{Name: name, Base: base, source: &sourceBlock{}}
 	for {
 		tok, err := p.peek()
 		if err != nil {
@@ -291,6 +381,7 @@ func (p *parser) parseTexGenBody(name, base string) (TexGen, error) {
 		if err := p.parseTexGenAssign(&tg); err != nil {
 			return TexGen{}, err
 		}
+		tg.source.setKey(tok.Lit, p.recordSpan(tok))
 	}
 
 	if _, err := p.expect(tokSemicolon); err != nil {
@@ -302,11 +393,14 @@ func (p *parser) parseTexGenBody(name, base string) (TexGen, error) {
 
 // parseStageClass parses the body of a stage class.
 func (p *parser) parseStageClass(st *Stage) error {
+	start := p.statementStart()
 	cn, uv, err := p.parseNestedClass()
 	if err != nil {
 		return err
 	}
 
+	st.source.setKey(nestedClassKey(cn, uv), p.recordSpan(start))
+
 	if uv != nil {
 		st.UVTransform = uv
 		return nil
@@ -318,11 +412,14 @@ func (p *parser) parseStageClass(st *Stage) error {
 
 // parseTexGenClass parses the body of a texture generator class.
 func (p *parser) parseTexGenClass(tg *TexGen) error {
+	start := p.statementStart()
 	cn, uv, err := p.parseNestedClass()
 	if err != nil {
 		return err
 	}
 
+	tg.source.setKey(nestedClassKey(cn, uv), p.recordSpan(start))
+
 	if uv != nil {
 		tg.UVTransform = uv
 		return nil
@@ -332,6 +429,15 @@ func (p *parser) parseTexGenClass(tg *TexGen) error {
 	return nil
 }
 
+// nestedClassKey returns source key for parsed nested class.
+func nestedClassKey(cn classNode, uv *UVTransform) string {
+	if uv != nil {
+		return "uvtransform"
+	}
+
+	return cn.Name
+}
+
 // parseNestedClass parses one nested class and detects inline uvTransform blocks.
 func (p *parser) parseNestedClass() (classNode, *UVTransform, error) {
 	if _, err := p.expect(tokClass); err != nil {
@@ -516,10 +622,11 @@ func (p *parser) parseTopAssign(m *Material) error {
 			matchKey(nameTok.Lit, "forceddiffuse", !p.opt.DisableCaseInsensitive),
 			matchKey(nameTok.Lit, "emmisive", !p.opt.DisableCaseInsensitive),
 			matchKey(nameTok.Lit, "specular", !p.opt.DisableCaseInsensitive):
-			vals, err := p.parseNumberArray()
+			vals, lits, err := p.parseNumberArray()
 			if err != nil {
 				return err
 			}
+			m.literals.set(nameTok.Lit, lits)
 
 			switch {
 			case matchKey(nameTok.Lit, "ambient", !p.opt.DisableCaseInsensitive):
@@ -542,11 +649,12 @@ func (p *parser) parseTopAssign(m *Material) error {
 		// Hot path: scalar fields at top-level.
 		switch {
 		case matchKey(nameTok.Lit, "specularpower", !p.opt.DisableCaseInsensitive):
-			num, err := p.parseNumberValue()
+			num, lits, err := p.parseNumberValue()
 			if err != nil {
 				return err
 			}
 			m.SpecularPower = &num
+			m.literals.set("specularPower", lits)
 			return p.expectSemicolon()
 
 		case matchKey(nameTok.Lit, "pixelshaderid", !p.opt.DisableCaseInsensitive):
@@ -616,10 +724,11 @@ func (p *parser) parseUVTransformBody() (*UVTransform, error) {
 		}
 
 		// Parse number array
-		vals, err := p.parseNumberArray()
+		vals, lits, err := p.parseNumberArray()
 		if err != nil {
 			return nil, err
 		}
+		uv.literals.set(nameTok.Lit, lits)
 
 		// Parse value
 		switch {
@@ -758,7 +867,8 @@ func (p *parser) parseValue() (value, error) {
 		if err != nil {
 			return value{}, p.errorf(tok, "invalid number")
 		}
-		return value{Kind: valueNumber, Num: f}, nil
+		lit, _ := numberTokenLiteral(tok, f)
+		return value{Kind: valueNumber, Num: f, Lit: lit.text}, nil
 
 	case tokString:
 		return value{Kind: valueString, Str: tok.Lit}, nil
@@ -817,22 +927,27 @@ func (p *parser) parseArray() ([]value, error) {
 }
 
 // parseNumberArray parses a number array.
-func (p *parser) parseNumberArray() ([]float64, error) {
+func (p *parser) parseNumberArray() ([]float64, []numberLiteral, error) {
 	return p.parseNumberArrayWithRelax(!p.opt.DisableRelaxedNumbers)
 }
 
 // parseNumberArrayWithRelax parses a number array with relaxed parsing.
-func (p *parser) parseNumberArrayWithRelax(relaxed bool) ([]float64, error) {
+//
+// Source text of elements that default formatting would change (including
+// relaxed expression and macro tokens parsed as 0) is returned as literals;
+// literals are nil when every element is canonical.
+func (p *parser) parseNumberArrayWithRelax(relaxed bool) ([]float64, []numberLiteral, error) {
 	if _, err := p.expect(tokLBrace); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Fast path for numeric arrays used in colors and transforms.
 	var arr []float64
+	var lits []numberLiteral
 	for {
 		tok, err := p.peek()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if tok.Type == tokRBrace {
@@ -842,21 +957,28 @@ func (p *parser) parseNumberArrayWithRelax(relaxed bool) ([]float64, error) {
 
 		numTok, err := p.next()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		f, ok := parseNumberToken(numTok)
-		if !ok {
-			if !relaxed {
-				return nil, p.errorf(numTok, "expected number")
+		f, ok := p.evalNumberToken(numTok)
+		if !ok && !relaxed {
+			return nil, nil, p.errorf(numTok, "expected number")
+		}
+
+		if lit, keep := numberTokenLiteral(numTok, f); keep {
+			lit.unresolved = !ok && p.opt.EvalExpressions
+			if lits == nil {
+				lits = make([]numberLiteral, len(arr), len(arr)+1)
 			}
-			f = 0
+			lits = append(lits, lit)
+		} else if lits != nil {
+			lits = append(lits, numberLiteral{})
 		}
 
 		arr = append(arr, f)
 		tok, err = p.peek()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// If comma parse next number
@@ -870,24 +992,59 @@ func (p *parser) parseNumberArrayWithRelax(relaxed bool) ([]float64, error) {
 			continue
 		}
 
-		return nil, p.errorf(tok, "expected ',' or '}' in array")
+		return nil, nil, p.errorf(tok, "expected ',' or '}' in array")
 	}
 
-	return arr, nil
+	return arr, lits, nil
 }
 
-// parseNumberValue parses a number value.
-func (p *parser) parseNumberValue() (float64, error) {
-	tok, err := p.expect(tokNumber)
+// parseNumberValue parses a number value and its non-canonical source literal.
+//
+// With EvalExpressions string and identifier tokens are evaluated; failed
+// ones become 0 in relaxed mode.
+func (p *parser) parseNumberValue() (float64, []numberLiteral, error) {
+	var tok token
+	var err error
+	if p.opt.EvalExpressions {
+		tok, err = p.next()
+		if err == nil && tok.Type != tokNumber && tok.Type != tokString && tok.Type != tokIdent {
+			err = p.errorf(tok, "expected number")
+		}
+	} else {
+		tok, err = p.expect(tokNumber)
+	}
 	if err != nil {
-		return 0, err
+		return 0, nil, err
+	}
+
+	f, ok := p.evalNumberToken(tok)
+	if !ok && (tok.Type == tokNumber || p.opt.DisableRelaxedNumbers) {
+		return 0, nil, p.errorf(tok, "invalid number")
 	}
+	if lit, keep := numberTokenLiteral(tok, f); keep {
+		lit.unresolved = !ok && p.opt.EvalExpressions
+		return f, []numberLiteral{lit}, nil
+	}
+
+	return f, nil, nil
+}
 
+// evalNumberToken parses number token, evaluating expression tokens when
+// EvalExpressions is set. Failed tokens return 0 and false.
+func (p *parser) evalNumberToken(tok token) (float64, bool) {
 	if f, ok := parseNumberToken(tok); ok {
-		return f, nil
+		return f, true
+	}
+	if !p.opt.EvalExpressions || (tok.Type != tokString && tok.Type != tokIdent) {
+		return 0, false
+	}
+
+	f, err := EvalExpression(tok.Lit, p.opt.ExpressionConstants)
+	if err != nil {
+		return 0, false
 	}
 
-	return 0, p.errorf(tok, "invalid number")
+	return f, true
 }
 
 // parseStringValue parses a string value.
@@ -0,0 +1,442 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// PBOPackingNone marks uncompressed entry data.
+	PBOPackingNone uint32 = 0
+
+	// PBOPackingCompressed marks LZSS-compressed entry data ("Cprs").
+	PBOPackingCompressed uint32 = 0x43707273
+
+	// PBOPackingVersion marks header extension entry with properties ("Vers").
+	PBOPackingVersion uint32 = 0x56657273
+
+	// pboMaxHeaderString limits header string length to reject garbage input.
+	pboMaxHeaderString = 1024
+
+	// pboMaxEntries limits file table size to reject garbage input.
+	pboMaxEntries = 1 << 20
+)
+
+// PBOProperty is one header extension key/value pair (e.g. prefix).
+type PBOProperty struct {
+	// Key is property name.
+	Key string `json:"key" yaml:"key"`
+	// Value is property value.
+	Value string `json:"value" yaml:"value"`
+}
+
+// PBOEntry is one file table entry.
+type PBOEntry struct {
+	// Name is entry path inside archive with backslash separators.
+	Name string `json:"name" yaml:"name"`
+	// dataOffset is absolute data offset in archive.
+	dataOffset int64
+	// PackingMethod is raw packing method (PBOPacking* constants).
+	PackingMethod uint32 `json:"packing_method,omitempty" yaml:"packing_method,omitempty"`
+	// OriginalSize is unpacked size; zero for uncompressed entries.
+	OriginalSize uint32 `json:"original_size,omitempty" yaml:"original_size,omitempty"`
+	// Reserved is unused header field.
+	Reserved uint32 `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+	// Timestamp is modification time as Unix seconds.
+	Timestamp uint32 `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	// DataSize is stored size in archive.
+	DataSize uint32 `json:"data_size" yaml:"data_size"`
+}
+
+// PBO is read-only PBO archive.
+type PBO struct {
+	// r reads archive bytes.
+	r io.ReaderAt
+	// closer closes archive opened by OpenPBO.
+	closer io.Closer
+	// Path is archive path when opened by OpenPBO.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Prefix is value of "prefix" property with backslash separators.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	// Properties stores header extension properties in file order.
+	Properties []PBOProperty `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Entries stores file table in archive order.
+	Entries []PBOEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// Compressed reports whether entry data is LZSS-compressed.
+func (e PBOEntry) Compressed() bool {
+	switch e.PackingMethod {
+	case PBOPackingCompressed:
+		return true
+	case PBOPackingNone:
+		// Old archives mark compressed entries only by differing sizes.
+		return e.OriginalSize != 0 && e.OriginalSize != e.DataSize
+	default:
+		return false
+	}
+}
+
+// Size returns unpacked entry size.
+func (e PBOEntry) Size() int64 {
+	if e.Compressed() {
+		return int64(e.OriginalSize)
+	}
+
+	return int64(e.DataSize)
+}
+
+// ModTime returns entry modification time.
+func (e PBOEntry) ModTime() time.Time {
+	return time.Unix(int64(e.Timestamp), 0)
+}
+
+// OpenPBO opens PBO archive from disk. Close releases the file.
+func OpenPBO(path string) (*PBO, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open pbo: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("open pbo: %w", err)
+	}
+
+	p, err := ReadPBO(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	p.Path = path
+	p.closer = f
+	return p, nil
+}
+
+// ReadPBO reads PBO header and file table from r.
+//
+// Entry data is read lazily, so r must stay valid while entries are read.
+func ReadPBO(r io.ReaderAt, size int64) (*PBO, error) {
+	br := bufio.NewReader(io.NewSectionReader(r, 0, size))
+	p := &PBO{r: r}
+
+	var offset int64
+	for {
+		name, n, err := readPBOString(br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry name: %w", ErrInvalidPBO, err)
+		}
+		offset += n
+
+		var fields [5]uint32
+		if err := binary.Read(br, binary.LittleEndian, &fields); err != nil {
+			return nil, fmt.Errorf("%w: entry %q header: %w", ErrInvalidPBO, name, err)
+		}
+		offset += int64(len(fields) * 4)
+
+		entry := PBOEntry{
+			Name:          name,
+			PackingMethod: fields[0],
+			OriginalSize:  fields[1],
+			Reserved:      fields[2],
+			Timestamp:     fields[3],
+			DataSize:      fields[4],
+		}
+
+		if name == "" && entry.PackingMethod == PBOPackingVersion {
+			n, err := p.readProperties(br)
+			if err != nil {
+				return nil, err
+			}
+			offset += n
+			continue
+		}
+		if name == "" {
+			break
+		}
+
+		if len(p.Entries) >= pboMaxEntries {
+			return nil, fmt.Errorf("%w: too many entries", ErrInvalidPBO)
+		}
+		p.Entries = append(p.Entries, entry)
+	}
+
+	for i := range p.Entries {
+		p.Entries[i].dataOffset = offset
+		offset += int64(p.Entries[i].DataSize)
+	}
+	if offset > size {
+		return nil, fmt.Errorf("%w: entry data exceeds archive size", ErrInvalidPBO)
+	}
+
+	return p, nil
+}
+
+// readProperties reads header extension pairs until empty key.
+func (p *PBO) readProperties(br *bufio.Reader) (int64, error) {
+	var total int64
+	for {
+		key, n, err := readPBOString(br)
+		if err != nil {
+			return 0, fmt.Errorf("%w: property key: %w", ErrInvalidPBO, err)
+		}
+		total += n
+		if key == "" {
+			return total, nil
+		}
+
+		value, n, err := readPBOString(br)
+		if err != nil {
+			return 0, fmt.Errorf("%w: property %q: %w", ErrInvalidPBO, key, err)
+		}
+		total += n
+
+		p.Properties = append(p.Properties, PBOProperty{Key: key, Value: value})
+		if strings.EqualFold(key, "prefix") {
+			p.Prefix = strings.Trim(strings.ReplaceAll(value, "/", `\`), `\`)
+		}
+	}
+}
+
+// readPBOString reads one NUL-terminated string and returns consumed bytes.
+func readPBOString(br *bufio.Reader) (string, int64, error) {
+	var b strings.Builder
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", 0, err
+		}
+		if c == 0 {
+			return b.String(), int64(b.Len() + 1), nil
+		}
+		if b.Len() >= pboMaxHeaderString {
+			return "", 0, errors.New("string too long")
+		}
+
+		b.WriteByte(c)
+	}
+}
+
+// Close closes archive opened by OpenPBO.
+func (p *PBO) Close() error {
+	if p == nil || p.closer == nil {
+		return nil
+	}
+
+	return p.closer.Close()
+}
+
+// Property returns header property value by case-insensitive key.
+func (p *PBO) Property(key string) (string, bool) {
+	for _, prop := range p.Properties {
+		if strings.EqualFold(prop.Key, key) {
+			return prop.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Lookup finds entry by archive-relative name (case-insensitive, any separator).
+func (p *PBO) Lookup(name string) (PBOEntry, bool) {
+	key := pboEntryKey(name)
+	for _, entry := range p.Entries {
+		if pboEntryKey(entry.Name) == key {
+			return entry, true
+		}
+	}
+
+	return PBOEntry{}, false
+}
+
+// FullName returns entry game path: prefix joined with entry name.
+func (p *PBO) FullName(entry PBOEntry) string {
+	name := strings.ReplaceAll(entry.Name, "/", `\`)
+	if p.Prefix == "" {
+		return name
+	}
+
+	return p.Prefix + `\` + name
+}
+
+// ReadEntry returns unpacked entry data.
+func (p *PBO) ReadEntry(entry PBOEntry) ([]byte, error) {
+	data := make([]byte, entry.DataSize)
+	n, err := p.r.ReadAt(data, entry.dataOffset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read pbo entry %q: %w", entry.Name, err)
+	}
+	if n < len(data) {
+		return nil, fmt.Errorf("%w: entry %q truncated: %d of %d bytes", ErrInvalidPBO, entry.Name, n, len(data))
+	}
+
+	if !entry.Compressed() {
+		return data, nil
+	}
+
+	out, err := decompressLZSS(data, int(entry.OriginalSize))
+	if err != nil {
+		return nil, fmt.Errorf("read pbo entry %q: %w", entry.Name, err)
+	}
+
+	return out, nil
+}
+
+// ReadFile returns unpacked data of entry with archive-relative name.
+func (p *PBO) ReadFile(name string) ([]byte, error) {
+	entry, ok := p.Lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return p.ReadEntry(entry)
+}
+
+// RVMATEntries returns entries with .rvmat extension in archive order.
+func (p *PBO) RVMATEntries() []PBOEntry {
+	var out []PBOEntry
+	for _, entry := range p.Entries {
+		if strings.EqualFold(filepath.Ext(strings.ReplaceAll(entry.Name, `\`, "/")), ".rvmat") {
+			out = append(out, entry)
+		}
+	}
+
+	return out
+}
+
+// ParseEntry reads and parses one entry as material.
+func (p *PBO) ParseEntry(entry PBOEntry, opt *ParseOptions) (*Material, error) {
+	data, err := p.ReadEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := Parse(data, opt)
+	if err != nil {
+		return nil, fmt.Errorf("parse pbo entry %q: %w", p.FullName(entry), err)
+	}
+
+	return m, nil
+}
+
+// ExtractRVMATs writes all .rvmat entries into dst under dir.
+//
+// Output paths keep archive prefix (dir\prefix\name), like an unpacked
+// P: drive. Returns written paths.
+func (p *PBO) ExtractRVMATs(dst WritableFileSystem, dir string) ([]string, error) {
+	dst = writableFileSystemOrOS(dst)
+
+	var written []string
+	for _, entry := range p.RVMATEntries() {
+		data, err := p.ReadEntry(entry)
+		if err != nil {
+			return written, err
+		}
+
+		// virtualPath keeps hostile "..\" names inside dir.
+		out := filepath.Join(dir, filepath.FromSlash(virtualPath(p.FullName(entry))))
+		if err := dst.MkdirAll(filepath.Dir(out), 0o750); err != nil {
+			return written, err
+		}
+		if err := dst.WriteFile(out, data, 0o600); err != nil {
+			return written, err
+		}
+
+		written = append(written, out)
+	}
+
+	return written, nil
+}
+
+// pboEntryKey returns case-insensitive slash lookup key of entry name.
+func pboEntryKey(name string) string {
+	return strings.ToLower(virtualPath(name))
+}
+
+// decompressLZSS unpacks BI LZSS stream with trailing 32-bit checksum.
+//
+// Flag bits are read LSB first: set bit is literal byte, clear bit is
+// back-reference of 12-bit distance and 4-bit length+3. Distances before
+// output start yield spaces.
+func decompressLZSS(data []byte, size int) ([]byte, error) {
+	// Each flag byte covers at most 8 references of 18 bytes.
+	out := make([]byte, 0, min(size, len(data)*18))
+	pos := 0
+	for len(out) < size {
+		if pos >= len(data) {
+			return nil, fmt.Errorf("%w: lzss: unexpected end of data", ErrInvalidPBO)
+		}
+
+		flags := data[pos]
+		pos++
+		for bit := 0; bit < 8 && len(out) < size; bit++ {
+			if flags&(1<<bit) != 0 {
+				if pos >= len(data) {
+					return nil, fmt.Errorf("%w: lzss: unexpected end of data", ErrInvalidPBO)
+				}
+				out = append(out, data[pos])
+				pos++
+				continue
+			}
+
+			if pos+1 >= len(data) {
+				return nil, fmt.Errorf("%w: lzss: unexpected end of data", ErrInvalidPBO)
+			}
+
+			distance := int(data[pos]) | int(data[pos+1]&0xF0)<<4
+			length := int(data[pos+1]&0x0F) + 3
+			pos += 2
+			if distance == 0 {
+				return nil, fmt.Errorf("%w: lzss: zero back-reference distance", ErrInvalidPBO)
+			}
+
+			from := len(out) - distance
+			for ; length > 0 && len(out) < size; length-- {
+				if from < 0 {
+					out = append(out, ' ')
+				} else {
+					out = append(out, out[from])
+				}
+				from++
+			}
+		}
+	}
+
+	if pos+4 > len(data) {
+		return nil, fmt.Errorf("%w: lzss: missing checksum", ErrInvalidPBO)
+	}
+
+	want := binary.LittleEndian.Uint32(data[pos:])
+	if got := lzssChecksum(out); got != want {
+		return nil, fmt.Errorf("%w: lzss: checksum mismatch %08x != %08x", ErrInvalidPBO, got, want)
+	}
+
+	return out, nil
+}
+
+// lzssChecksum returns 32-bit wrapping sum of bytes.
+func lzssChecksum(data []byte) uint32 {
+	var sum uint32
+	for _, b := range data {
+		sum += uint32(b)
+	}
+
+	return sum
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PBOFileSystem is read-only FileSystem over mounted PBO archives.
+//
+// Entries are addressed by game path (prefix\name), case-insensitively, so
+// it can back ValidateOptions.FileSystem for strict texture checks without
+// an unpacked P: drive. Later archives shadow earlier ones for same path.
+type PBOFileSystem struct {
+	files map[string]pboFileRef
+	dirs  map[string]struct{}
+	pbos  []*PBO
+}
+
+// pboFileRef points to one mounted archive entry.
+type pboFileRef struct {
+	pbo   *PBO
+	entry PBOEntry
+}
+
+// NewPBOFileSystem mounts archives by their prefixes.
+func NewPBOFileSystem(pbos ...*PBO) *PBOFileSystem {
+	f := &PBOFileSystem{
+		files: make(map[string]pboFileRef),
+		dirs:  map[string]struct{}{".": {}},
+	}
+	for _, p := range pbos {
+		f.Mount(p)
+	}
+
+	return f
+}
+
+// OpenPBOFileSystem opens and mounts archives from paths.
+//
+// Directories are scanned recursively for *.pbo files. Close releases
+// opened archives.
+func OpenPBOFileSystem(paths ...string) (*PBOFileSystem, error) {
+	f := NewPBOFileSystem()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(name), ".pbo") {
+				return nil
+			}
+
+			p, err := OpenPBO(name)
+			if err != nil {
+				return err
+			}
+
+			f.Mount(p)
+			return nil
+		})
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("open pbo file system: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// Mount adds archive entries under archive prefix.
+func (f *PBOFileSystem) Mount(p *PBO) {
+	if p == nil {
+		return
+	}
+
+	f.pbos = append(f.pbos, p)
+	for _, entry := range p.Entries {
+		key := pboEntryKey(p.FullName(entry))
+		f.files[key] = pboFileRef{pbo: p, entry: entry}
+		for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+			f.dirs[dir] = struct{}{}
+		}
+	}
+}
+
+// PBOs returns mounted archives in mount order.
+func (f *PBOFileSystem) PBOs() []*PBO {
+	return slices.Clone(f.pbos)
+}
+
+// Close closes all mounted archives.
+func (f *PBOFileSystem) Close() error {
+	var errs []error
+	for _, p := range f.pbos {
+		errs = append(errs, p.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Stat returns file info for game path name.
+func (f *PBOFileSystem) Stat(name string) (fs.FileInfo, error) {
+	key := pboEntryKey(name)
+	if ref, ok := f.files[key]; ok {
+		return pboFileInfo{name: path.Base(key), entry: ref.entry}, nil
+	}
+	if _, ok := f.dirs[key]; ok {
+		return pboFileInfo{name: path.Base(key), dir: true}, nil
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns directory entries of game path name sorted by name.
+//
+// Names are lower-case because lookups are case-insensitive.
+func (f *PBOFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	key := pboEntryKey(name)
+	if _, ok := f.dirs[key]; !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var out []fs.DirEntry
+	for child, ref := range f.files {
+		if path.Dir(child) == key {
+			out = append(out, pboFileInfo{name: path.Base(child), entry: ref.entry})
+		}
+	}
+	for child := range f.dirs {
+		if child != "." && path.Dir(child) == key {
+			out = append(out, pboFileInfo{name: path.Base(child), dir: true})
+		}
+	}
+	slices.SortFunc(out, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return out, nil
+}
+
+// ReadFile returns unpacked data of game path name.
+func (f *PBOFileSystem) ReadFile(name string) ([]byte, error) {
+	ref, ok := f.files[pboEntryKey(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return ref.pbo.ReadEntry(ref.entry)
+}
+
+// RVMATPaths returns sorted game paths of all mounted .rvmat entries.
+func (f *PBOFileSystem) RVMATPaths() []string {
+	var out []string
+	for _, p := range f.pbos {
+		for _, entry := range p.RVMATEntries() {
+			out = append(out, p.FullName(entry))
+		}
+	}
+	slices.SortFunc(out, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return slices.CompactFunc(out, strings.EqualFold)
+}
+
+// pboFileInfo implements fs.FileInfo and fs.DirEntry for PBOFileSystem.
+type pboFileInfo struct {
+	name  string
+	entry PBOEntry
+	dir   bool
+}
+
+// Name returns base name.
+func (i pboFileInfo) Name() string { return i.name }
+
+// Size returns unpacked file size in bytes.
+func (i pboFileInfo) Size() int64 { return i.entry.Size() }
+
+// Mode returns read-only file mode bits.
+func (i pboFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
+
+// ModTime returns entry modification time.
+func (i pboFileInfo) ModTime() time.Time {
+	if i.dir {
+		return time.Time{}
+	}
+
+	return i.entry.ModTime()
+}
+
+// IsDir reports whether entry is a directory.
+func (i pboFileInfo) IsDir() bool { return i.dir }
+
+// Sys returns PBOEntry for files and nil for directories.
+func (i pboFileInfo) Sys() any {
+	if i.dir {
+		return nil
+	}
+
+	return i.entry
+}
+
+// Type returns file type bits.
+func (i pboFileInfo) Type() fs.FileMode { return i.Mode().Type() }
+
+// Info returns file info.
+func (i pboFileInfo) Info() (fs.FileInfo, error) { return i, nil }
@@ -0,0 +1,180 @@
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testPBOEntry struct {
+	name       string
+	data       []byte
+	packed     []byte
+	compressed bool
+}
+
+func buildTestPBO(t *testing.T, prefix string, entries []testPBOEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writeHeader := func(name string, fields ...uint32) {
+		buf.WriteString(name)
+		buf.WriteByte(0)
+		if err := binary.Write(&buf, binary.LittleEndian, fields); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+	}
+
+	writeHeader("", PBOPackingVersion, 0, 0, 0, 0)
+	buf.WriteString("prefix\x00" + prefix + "\x00version\x001\x00\x00")
+	for _, entry := range entries {
+		if entry.compressed {
+			writeHeader(entry.name, PBOPackingCompressed, uint32(len(entry.data)), 0, 1700000000, uint32(len(entry.packed)))
+			continue
+		}
+		writeHeader(entry.name, PBOPackingNone, 0, 0, 1700000000, uint32(len(entry.data)))
+	}
+	writeHeader("", 0, 0, 0, 0, 0)
+
+	for _, entry := range entries {
+		if entry.compressed {
+			buf.Write(entry.packed)
+			continue
+		}
+		buf.Write(entry.data)
+	}
+	buf.WriteByte(0)
+	buf.Write(make([]byte, 20))
+
+	return buf.Bytes()
+}
+
+func lzssWithChecksum(data []byte, plain []byte) []byte {
+	return binary.LittleEndian.AppendUint32(bytes.Clone(data), lzssChecksum(plain))
+}
+
+func TestDecompressLZSS(t *testing.T) {
+	plain := []byte("abcabcabcabc")
+	// flags 0b0000_0111: three literals, then back-reference distance 3, length 9.
+	packed := lzssWithChecksum([]byte{0x07, 'a', 'b', 'c', 0x03, 0x06}, plain)
+
+	got, err := decompressLZSS(packed, len(plain))
+	if err != nil || string(got) != string(plain) {
+		t.Fatalf("decompressLZSS = %q, %v", got, err)
+	}
+
+	// Back-reference before output start yields spaces.
+	got, err = decompressLZSS(lzssWithChecksum([]byte{0x00, 0x04, 0x00}, []byte("   ")), 3)
+	if err != nil || string(got) != "   " {
+		t.Fatalf("decompressLZSS spaces = %q, %v", got, err)
+	}
+
+	packed[len(packed)-1] ^= 0xFF
+	if _, err := decompressLZSS(packed, len(plain)); !errors.Is(err, ErrInvalidPBO) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+
+	if _, err := decompressLZSS([]byte{0x01, 'A', 0x00, 0x00, 0, 0, 0, 0}, 4); !errors.Is(err, ErrInvalidPBO) {
+		t.Fatalf("expected zero distance error, got %v", err)
+	}
+}
+
+func TestReadPBO(t *testing.T) {
+	rvmatData := []byte("PixelShaderID=\"Super\";\nclass Stage1 { texture=\"mod\\data\\box_nohq.paa\"; };\n")
+	data := buildTestPBO(t, `mod\data`, []testPBOEntry{
+		{name: "box.rvmat", data: rvmatData},
+		{name: `sub\box_nohq.paa`, data: []byte("abcabcabcabc"), compressed: true,
+			packed: lzssWithChecksum([]byte{0x07, 'a', 'b', 'c', 0x03, 0x06}, []byte("abcabcabcabc"))},
+		{name: "box_co.paa", data: []byte("co")},
+	})
+
+	p, err := ReadPBO(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadPBO: %v", err)
+	}
+	if p.Prefix != `mod\data` || len(p.Properties) != 2 || len(p.Entries) != 3 {
+		t.Fatalf("unexpected header: prefix=%q props=%v entries=%d", p.Prefix, p.Properties, len(p.Entries))
+	}
+
+	packed, err := p.ReadFile("SUB/box_nohq.paa")
+	if err != nil || string(packed) != "abcabcabcabc" {
+		t.Fatalf("ReadFile compressed = %q, %v", packed, err)
+	}
+
+	entries := p.RVMATEntries()
+	if len(entries) != 1 {
+		t.Fatalf("unexpected rvmat entries: %+v", entries)
+	}
+	mat, err := p.ParseEntry(entries[0], nil)
+	if err != nil || mat.PixelShaderID != "Super" {
+		t.Fatalf("ParseEntry: %+v, %v", mat, err)
+	}
+
+	out := NewMemFileSystem()
+	written, err := p.ExtractRVMATs(out, "unpacked")
+	if err != nil || len(written) != 1 {
+		t.Fatalf("ExtractRVMATs: %v, %v", written, err)
+	}
+	if got, err := out.ReadFile("unpacked/mod/data/box.rvmat"); err != nil || !bytes.Equal(got, rvmatData) {
+		t.Fatalf("unexpected extracted data: %q, %v", got, err)
+	}
+
+	co, _ := p.Lookup("box_co.paa")
+	p.r = bytes.NewReader(data[:co.dataOffset+1])
+	if _, err := p.ReadEntry(co); !errors.Is(err, ErrInvalidPBO) {
+		t.Fatalf("expected truncated entry error, got %v", err)
+	}
+
+	if _, err := ReadPBO(bytes.NewReader(data[:40]), 40); !errors.Is(err, ErrInvalidPBO) {
+		t.Fatalf("expected truncated archive error, got %v", err)
+	}
+}
+
+func TestValidateWithPBOFileSystem(t *testing.T) {
+	root := t.TempDir()
+	data := buildTestPBO(t, `mod\data`, []testPBOEntry{
+		{name: "box_co.paa", data: []byte("co")},
+		{name: "box.rvmat", data: []byte("class Stage1 { texture=\"mod\\data\\box_co.paa\"; };\n")},
+	})
+	if err := os.MkdirAll(filepath.Join(root, "addons"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "addons", "data.pbo"), data, 0o600); err != nil {
+		t.Fatalf("write pbo: %v", err)
+	}
+
+	fsys, err := OpenPBOFileSystem(root)
+	if err != nil {
+		t.Fatalf("OpenPBOFileSystem: %v", err)
+	}
+	defer func() { _ = fsys.Close() }()
+
+	if paths := fsys.RVMATPaths(); len(paths) != 1 || paths[0] != `mod\data\box.rvmat` {
+		t.Fatalf("unexpected rvmat paths: %v", paths)
+	}
+
+	mat := &Material{Stages: []Stage{
+		{Name: "Stage1", Texture: ParseTextureRef(`Mod\Data\Box_CO.paa`)},
+		{Name: "Stage2", Texture: ParseTextureRef(`mod\data\box_dt.paa`)},
+	}}
+	missing := 0
+	for _, issue := range Validate(mat, &ValidateOptions{
+		TexturePathMode: TexturePathModeStrict,
+		FileSystem:      fsys,
+	}) {
+		if issue.Code == mustRuleCode(t, CodeValidateTextureFileNotFound) {
+			missing++
+		}
+	}
+	if missing != 1 {
+		t.Fatalf("missing textures=%d, want 1", missing)
+	}
+
+	entries, err := fsys.ReadDir("mod")
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		t.Fatalf("unexpected ReadDir: %v, %v", entries, err)
+	}
+}
@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const (
+	// defaultPBRGlossMin keeps gloss away from black, which breaks highlights.
+	defaultPBRGlossMin = 0.05
+	// defaultPBRGlossMax is SMDI blue for zero roughness.
+	defaultPBRGlossMax = 1.0
+	// defaultPBRGlossGamma is exponent of inverted roughness curve.
+	defaultPBRGlossGamma = 1.0
+	// defaultPBRDielectricSpecular is SMDI green for non-metal surfaces.
+	defaultPBRDielectricSpecular = 0.1
+	// defaultPBRMetalSpecular is SMDI green for white metal surfaces.
+	defaultPBRMetalSpecular = 0.8
+	// defaultPBRTextureSize is output size when no input image is given.
+	defaultPBRTextureSize = 8
+)
+
+// PBRMaterial is metal/rough material in glTF/Substance convention.
+//
+// Factors multiply matching images like in glTF; nil factors default to 1.
+// Images are sampled with wrap, so inputs of different sizes can be mixed.
+type PBRMaterial struct {
+	// BaseColorImage is base color (albedo) map.
+	BaseColorImage image.Image `json:"-" yaml:"-"`
+	// MetallicRoughnessImage is glTF packed map: G roughness, B metallic.
+	MetallicRoughnessImage image.Image `json:"-" yaml:"-"`
+	// MetallicImage is separate metallic map (R channel); overrides packed map.
+	MetallicImage image.Image `json:"-" yaml:"-"`
+	// RoughnessImage is separate roughness map (R channel); overrides packed map.
+	RoughnessImage image.Image `json:"-" yaml:"-"`
+	// NormalImage is tangent-space normal map.
+	NormalImage image.Image `json:"-" yaml:"-"`
+	// OcclusionImage is ambient occlusion map (R channel).
+	OcclusionImage image.Image `json:"-" yaml:"-"`
+	// MetallicFactor scales metallic (default 1).
+	MetallicFactor *float64 `json:"metallic_factor,omitempty" yaml:"metallic_factor,omitempty"`
+	// RoughnessFactor scales roughness (default 1).
+	RoughnessFactor *float64 `json:"roughness_factor,omitempty" yaml:"roughness_factor,omitempty"`
+	// OcclusionStrength blends occlusion towards 1 (default 1).
+	OcclusionStrength *float64 `json:"occlusion_strength,omitempty" yaml:"occlusion_strength,omitempty"`
+	// BaseColorFactor scales base color RGBA (default 1,1,1,1).
+	BaseColorFactor []float64 `json:"base_color_factor,omitempty" yaml:"base_color_factor,omitempty"`
+	// EmissiveFactor is emissive RGB; its maximum becomes EmissiveIntensity.
+	EmissiveFactor []float64 `json:"emissive_factor,omitempty" yaml:"emissive_factor,omitempty"`
+	// NormalDirectX marks normal map already in DirectX (Y-) convention.
+	NormalDirectX bool `json:"normal_directx,omitempty" yaml:"normal_directx,omitempty"`
+}
+
+// PBRCurves calibrates metal/rough to SMDI conversion; zero values use defaults.
+type PBRCurves struct {
+	// GlossMin is SMDI blue for roughness 1 (default 0.05).
+	GlossMin float64 `json:"gloss_min,omitempty" yaml:"gloss_min,omitempty"`
+	// GlossMax is SMDI blue for roughness 0 (default 1).
+	GlossMax float64 `json:"gloss_max,omitempty" yaml:"gloss_max,omitempty"`
+	// GlossGamma shapes gloss as (1-roughness)^GlossGamma (default 1).
+	GlossGamma float64 `json:"gloss_gamma,omitempty" yaml:"gloss_gamma,omitempty"`
+	// DielectricSpecular is SMDI green for metallic 0 (default 0.1).
+	DielectricSpecular float64 `json:"dielectric_specular,omitempty" yaml:"dielectric_specular,omitempty"`
+	// MetalSpecular is SMDI green for metallic 1 and white base color (default 0.8).
+	MetalSpecular float64 `json:"metal_specular,omitempty" yaml:"metal_specular,omitempty"`
+}
+
+// PBRConvertOptions controls metal/rough to Super conversion.
+type PBRConvertOptions struct {
+	// Generate configures output material; BaseTexture derives _smdi/_as/_nohq
+	// paths. Default BaseMaterial and Finish are inferred from metal/rough.
+	Generate GenerateOptions `json:"generate" yaml:"generate"`
+	// Curves calibrates SMDI channels.
+	Curves PBRCurves `json:"curves" yaml:"curves"`
+	// Width overrides output texture width; default is largest input width.
+	Width int `json:"width,omitempty" yaml:"width,omitempty"`
+	// Height overrides output texture height; default is largest input height.
+	Height int `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+// PBRConversion is result of metal/rough to Super conversion.
+type PBRConversion struct {
+	// SMDI is specular map: R=1, G specular intensity, B gloss.
+	SMDI *image.NRGBA `json:"-" yaml:"-"`
+	// AS is ambient shadow map with occlusion in all color channels.
+	AS *image.NRGBA `json:"-" yaml:"-"`
+	// NOHQ is normal map in DirectX (Y-) convention.
+	NOHQ *image.NRGBA `json:"-" yaml:"-"`
+	// Material is generated Super material referencing converted textures.
+	Material *Material `json:"material,omitempty" yaml:"material,omitempty"`
+	// TexturePaths maps role (smdi/as/nohq) to material texture path.
+	TexturePaths map[string]string `json:"texture_paths,omitempty" yaml:"texture_paths,omitempty"`
+	// Metallic is mean metallic used for profile inference.
+	Metallic float64 `json:"metallic" yaml:"metallic"`
+	// Roughness is mean roughness used for profile inference.
+	Roughness float64 `json:"roughness" yaml:"roughness"`
+}
+
+// ConvertPBR converts metal/rough material into _smdi, _as and _nohq images
+// and generated Super material.
+//
+// SMDI green blends Curves.DielectricSpecular to Curves.MetalSpecular scaled
+// by base color luminance by metallic; SMDI blue remaps inverted roughness
+// into Curves.GlossMin..GlossMax. Normal green channel is flipped from
+// OpenGL (Y+) to DirectX (Y-) unless NormalDirectX is set. Emissive images
+// have no Super stage and are ignored.
+func ConvertPBR(in PBRMaterial, opt *PBRConvertOptions) (*PBRConversion, error) {
+	o := PBRConvertOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	curves := o.Curves.normalize()
+
+	width, height := o.Width, o.Height
+	for _, img := range []image.Image{
+		in.BaseColorImage, in.MetallicRoughnessImage, in.MetallicImage,
+		in.RoughnessImage, in.NormalImage, in.OcclusionImage,
+	} {
+		if img == nil {
+			continue
+		}
+		if o.Width <= 0 {
+			width = max(width, img.Bounds().Dx())
+		}
+		if o.Height <= 0 {
+			height = max(height, img.Bounds().Dy())
+		}
+	}
+	if width <= 0 {
+		width = defaultPBRTextureSize
+	}
+	if height <= 0 {
+		height = defaultPBRTextureSize
+	}
+	if width*height > maxTextureImagePixels {
+		return nil, fmt.Errorf("%w: pbr texture size %dx%d", ErrInvalidGenerateOption, width, height)
+	}
+
+	sample := func(img image.Image) proceduralSampler {
+		if img == nil {
+			return nil
+		}
+		return imageSampler(img)
+	}
+	baseColor, packed := sample(in.BaseColorImage), sample(in.MetallicRoughnessImage)
+	metallicMap, roughnessMap := sample(in.MetallicImage), sample(in.RoughnessImage)
+	normalMap, occlusionMap := sample(in.NormalImage), sample(in.OcclusionImage)
+
+	baseFactor := [4]float64{1, 1, 1, 1}
+	for i := range min(len(in.BaseColorFactor), 4) {
+		baseFactor[i] = in.BaseColorFactor[i]
+	}
+	metallicFactor := optionalFactor(in.MetallicFactor)
+	roughnessFactor := optionalFactor(in.RoughnessFactor)
+	occlusionStrength := optionalFactor(in.OcclusionStrength)
+
+	out := &PBRConversion{
+		SMDI: image.NewNRGBA(image.Rect(0, 0, width, height)),
+		AS:   image.NewNRGBA(image.Rect(0, 0, width, height)),
+		NOHQ: image.NewNRGBA(image.Rect(0, 0, width, height)),
+	}
+
+	var sumMetallic, sumRoughness float64
+	for y := range height {
+		v := (float64(y) + 0.5) / float64(height)
+		for x := range width {
+			u := (float64(x) + 0.5) / float64(width)
+
+			base := baseFactor
+			if baseColor != nil {
+				r, g, b, a := baseColor(u, v)
+				base = [4]float64{base[0] * r, base[1] * g, base[2] * b, base[3] * a}
+			}
+
+			metallic, roughness := metallicFactor, roughnessFactor
+			if packed != nil {
+				_, g, b, _ := packed(u, v)
+				metallic, roughness = metallic*b, roughness*g
+			}
+			if metallicMap != nil {
+				r, _, _, _ := metallicMap(u, v)
+				metallic = metallicFactor * r
+			}
+			if roughnessMap != nil {
+				r, _, _, _ := roughnessMap(u, v)
+				roughness = roughnessFactor * r
+			}
+			metallic, roughness = clamp01(metallic), clamp01(roughness)
+			sumMetallic += metallic
+			sumRoughness += roughness
+
+			metalSpec := curves.MetalSpecular * luminance(base[0], base[1], base[2])
+			spec := curves.DielectricSpecular + (metalSpec-curves.DielectricSpecular)*metallic
+			gloss := curves.GlossMin + (curves.GlossMax-curves.GlossMin)*math.Pow(1-roughness, curves.GlossGamma)
+			out.SMDI.SetNRGBA(x, y, color.NRGBA{R: 255, G: unitToByte(spec), B: unitToByte(gloss), A: 255})
+
+			ao := 1.0
+			if occlusionMap != nil {
+				r, _, _, _ := occlusionMap(u, v)
+				ao = 1 + occlusionStrength*(r-1)
+			}
+			aoByte := unitToByte(ao)
+			out.AS.SetNRGBA(x, y, color.NRGBA{R: aoByte, G: aoByte, B: aoByte, A: 255})
+
+			normal := color.NRGBA{R: 128, G: 128, B: 255, A: 255}
+			if normalMap != nil {
+				r, g, b, _ := normalMap(u, v)
+				if !in.NormalDirectX {
+					g = 1 - g
+				}
+				normal = color.NRGBA{R: unitToByte(r), G: unitToByte(g), B: unitToByte(b), A: 255}
+			}
+			out.NOHQ.SetNRGBA(x, y, normal)
+		}
+	}
+	out.Metallic = sumMetallic / float64(width*height)
+	out.Roughness = sumRoughness / float64(width*height)
+
+	gen := o.Generate
+	inferredMaterial, inferredFinish := InferGenerateProfile(out.Metallic, out.Roughness)
+	if gen.BaseMaterial == BaseMaterialDefault && gen.Profile == "" {
+		gen.BaseMaterial = inferredMaterial
+	}
+	if gen.Finish == FinishDefault {
+		gen.Finish = inferredFinish
+	}
+	if gen.EmissiveIntensity <= 0 {
+		for _, e := range in.EmissiveFactor {
+			gen.EmissiveIntensity = max(gen.EmissiveIntensity, e)
+		}
+	}
+
+	m, err := Generate(gen)
+	if err != nil {
+		return nil, fmt.Errorf("generate pbr material: %w", err)
+	}
+	out.Material = m
+
+	out.TexturePaths = map[string]string{}
+	for _, role := range []string{"smdi", "as", "nohq"} {
+		stageName, _ := stageNameForTextureRole(role)
+		if raw := textureOverride(gen.TextureOverrides, stageName, role); raw != "" {
+			out.TexturePaths[role] = raw
+		} else if raw := derivedTextureForRole(gen.BaseTexture, role); raw != "" {
+			out.TexturePaths[role] = raw
+		}
+	}
+
+	return out, nil
+}
+
+// WritePNG writes converted images as PNG next to material texture paths
+// (same stem, ".png" extension) resolved against root.
+//
+// Nil fsys uses OS filesystem. Written paths are returned in role order
+// smdi, as, nohq; roles without texture path are skipped.
+func (c *PBRConversion) WritePNG(fsys WritableFileSystem, root string) ([]string, error) {
+	images := map[string]*image.NRGBA{"smdi": c.SMDI, "as": c.AS, "nohq": c.NOHQ}
+	resolver := PathResolver{GameRoot: root}
+
+	var written []string
+	for _, role := range []string{"smdi", "as", "nohq"} {
+		raw := c.TexturePaths[role]
+		if raw == "" || images[role] == nil {
+			continue
+		}
+
+		name := resolver.ResolvePath(textureWithExtension(raw, ".png"))
+		if err := writeImagePNG(fsys, name, images[role]); err != nil {
+			return written, fmt.Errorf("write pbr %s texture: %w", role, err)
+		}
+		written = append(written, name)
+	}
+
+	return written, nil
+}
+
+// InferGenerateProfile picks generator base material and finish from mean
+// metallic and roughness.
+//
+// Metallic 0.5 and above maps to steel, otherwise plastic. Roughness below
+// 0.2, 0.4 and 0.65 maps to polished, gloss and satin, the rest to matte.
+func InferGenerateProfile(metallic, roughness float64) (BaseMaterial, Finish) {
+	material := BaseMaterialPlastic
+	if metallic >= 0.5 {
+		material = BaseMaterialSteel
+	}
+
+	switch {
+	case roughness < 0.2:
+		return material, FinishPolished
+	case roughness < 0.4:
+		return material, FinishGloss
+	case roughness < 0.65:
+		return material, FinishSatin
+	default:
+		return material, FinishMatte
+	}
+}
+
+// normalize fills zero curve values with defaults.
+func (c PBRCurves) normalize() PBRCurves {
+	if c.GlossMin <= 0 {
+		c.GlossMin = defaultPBRGlossMin
+	}
+	if c.GlossMax <= 0 {
+		c.GlossMax = defaultPBRGlossMax
+	}
+	if c.GlossGamma <= 0 {
+		c.GlossGamma = defaultPBRGlossGamma
+	}
+	if c.DielectricSpecular <= 0 {
+		c.DielectricSpecular = defaultPBRDielectricSpecular
+	}
+	if c.MetalSpecular <= 0 {
+		c.MetalSpecular = defaultPBRMetalSpecular
+	}
+
+	return c
+}
+
+// optionalFactor returns factor value or 1 for nil.
+func optionalFactor(v *float64) float64 {
+	if v == nil {
+		return 1
+	}
+
+	return *v
+}
+
+// textureWithExtension replaces texture path extension.
+func textureWithExtension(raw, ext string) string {
+	lastDot := strings.LastIndex(raw, ".")
+	if lastDot > strings.LastIndexAny(raw, `/\`) {
+		raw = raw[:lastDot]
+	}
+
+	return raw + ext
+}
@@ -0,0 +1,89 @@
+package rvmat
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestConvertPBR(t *testing.T) {
+	// Left texel rough dielectric, right texel smooth metal (glTF packed: G roughness, B metallic).
+	mr := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	mr.SetNRGBA(0, 0, color.NRGBA{G: 255, B: 0, A: 255})
+	mr.SetNRGBA(1, 0, color.NRGBA{G: 0, B: 255, A: 255})
+
+	normal := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	normal.SetNRGBA(0, 0, color.NRGBA{R: 128, G: 200, B: 255, A: 255})
+
+	occlusion := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	occlusion.SetNRGBA(0, 0, color.NRGBA{R: 128, A: 255})
+	half := 0.5
+
+	conv, err := ConvertPBR(PBRMaterial{
+		MetallicRoughnessImage: mr,
+		NormalImage:            normal,
+		OcclusionImage:         occlusion,
+		OcclusionStrength:      &half,
+	}, &PBRConvertOptions{
+		Generate: GenerateOptions{BaseTexture: `mod\data\crate_co.paa`},
+		// Nearest-like sampling: one output texel per input texel.
+		Width:  2,
+		Height: 1,
+	})
+	if err != nil {
+		t.Fatalf("ConvertPBR: %v", err)
+	}
+
+	rough := conv.SMDI.NRGBAAt(0, 0)
+	metal := conv.SMDI.NRGBAAt(1, 0)
+	if rough.R != 255 || rough.B != unitToByte(defaultPBRGlossMin) || rough.G != unitToByte(defaultPBRDielectricSpecular) {
+		t.Fatalf("unexpected rough dielectric smdi: %+v", rough)
+	}
+	if metal.B != 255 || metal.G != unitToByte(defaultPBRMetalSpecular) {
+		t.Fatalf("unexpected smooth metal smdi: %+v", metal)
+	}
+
+	if c := conv.NOHQ.NRGBAAt(0, 0); c.G != 55 || c.R != 128 {
+		t.Fatalf("expected flipped normal green: %+v", c)
+	}
+	if c := conv.AS.NRGBAAt(0, 0); c.G < 190 || c.G > 192 {
+		t.Fatalf("unexpected ambient shadow with half strength: %+v", c)
+	}
+
+	if conv.TexturePaths["smdi"] != `mod\data\crate_smdi.paa` {
+		t.Fatalf("unexpected texture paths: %v", conv.TexturePaths)
+	}
+	if st := findMaterialStageByName(conv.Material, "Stage5"); st == nil || st.Texture.Raw != conv.TexturePaths["smdi"] {
+		t.Fatalf("expected Stage5 to reference converted smdi: %+v", st)
+	}
+
+	out := NewMemFileSystem()
+	written, err := conv.WritePNG(out, "")
+	if err != nil || len(written) != 3 {
+		t.Fatalf("WritePNG: %v, %v", written, err)
+	}
+	img, err := LoadTextureImage(out, "mod/data/crate_nohq.png")
+	if err != nil || img.Bounds().Dx() != 2 {
+		t.Fatalf("expected readable nohq png: %v", err)
+	}
+}
+
+func TestInferGenerateProfile(t *testing.T) {
+	tests := []struct {
+		metallic, roughness float64
+		material            BaseMaterial
+		finish              Finish
+	}{
+		{1, 0.1, BaseMaterialSteel, FinishPolished},
+		{0.9, 0.3, BaseMaterialSteel, FinishGloss},
+		{0, 0.5, BaseMaterialPlastic, FinishSatin},
+		{0.2, 0.9, BaseMaterialPlastic, FinishMatte},
+	}
+
+	for _, tt := range tests {
+		material, finish := InferGenerateProfile(tt.metallic, tt.roughness)
+		if material != tt.material || finish != tt.finish {
+			t.Fatalf("InferGenerateProfile(%v, %v) = %s/%s", tt.metallic, tt.roughness, material, finish)
+		}
+	}
+}
@@ -0,0 +1,612 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	// PreviewBaseImageKey is PreviewOptions.Images key for diffuse (_co) texture.
+	PreviewBaseImageKey = "base"
+
+	// defaultPreviewSize is preview width and height when not set.
+	defaultPreviewSize = 256
+
+	// defaultPreviewAmbientLight is ambient light intensity when not set.
+	defaultPreviewAmbientLight = 0.35
+
+	// previewGamma converts between sRGB-like texture values and linear light.
+	previewGamma = 2.2
+)
+
+// defaultPreviewLight is direction towards key light in view space
+// (X right, Y up, Z towards viewer).
+var defaultPreviewLight = [3]float64{-0.45, 0.55, 0.7}
+
+// previewLookupFns are procedural functions indexed by N dot V instead of UV.
+var previewLookupFns = map[string]struct{}{
+	"fresnel":         {},
+	"fresnelglass":    {},
+	"irradiance":      {},
+	"waterirradiance": {},
+	"treecrown":       {},
+	"treecrownamb":    {},
+}
+
+// PreviewShape selects preview geometry.
+type PreviewShape uint8
+
+const (
+	// PreviewShapeSphere renders unit sphere with equirectangular UV.
+	PreviewShapeSphere PreviewShape = iota
+	// PreviewShapePlane renders view-facing plane with UV 0..1.
+	PreviewShapePlane
+)
+
+// String returns human-readable shape name.
+func (s PreviewShape) String() string {
+	switch s {
+	case PreviewShapeSphere:
+		return "sphere"
+	case PreviewShapePlane:
+		return "plane"
+	default:
+		return fmt.Sprintf("preview_shape(%d)", s)
+	}
+}
+
+// PreviewOptions controls CPU material preview rendering.
+type PreviewOptions struct {
+	// FileSystem is used to load stage textures; nil value uses OS filesystem.
+	FileSystem FileSystem `json:"-" yaml:"-"`
+	// Images provides already decoded textures by stage name (e.g. "Stage5")
+	// or PreviewBaseImageKey; they take priority over stage texture paths.
+	Images map[string]image.Image `json:"-" yaml:"-"`
+	// GameRoot is prepended to relative texture paths.
+	GameRoot string `json:"game_root,omitempty" yaml:"game_root,omitempty"`
+	// BaseTexture is diffuse (_co) texture path; mid-gray is used when empty.
+	BaseTexture string `json:"base_texture,omitempty" yaml:"base_texture,omitempty"`
+	// LightDirection is direction towards light in view space; zero uses default.
+	LightDirection [3]float64 `json:"light_direction,omitempty" yaml:"light_direction,omitempty"`
+	// Width is preview width in pixels (default 256).
+	Width int `json:"width,omitempty" yaml:"width,omitempty"`
+	// Height is preview height in pixels (default 256).
+	Height int `json:"height,omitempty" yaml:"height,omitempty"`
+	// AmbientLight is ambient light intensity (default 0.35).
+	AmbientLight float64 `json:"ambient_light,omitempty" yaml:"ambient_light,omitempty"`
+	// Shape selects preview geometry (PreviewShape* constants).
+	Shape PreviewShape `json:"shape,omitempty" yaml:"shape,omitempty"`
+}
+
+// PreviewResult is rendered material preview.
+type PreviewResult struct {
+	// Image is rendered preview; pixels outside geometry are transparent.
+	Image *image.NRGBA `json:"-" yaml:"-"`
+	// Unresolved maps stage name (or PreviewBaseImageKey) to load error
+	// for textures replaced by neutral defaults.
+	Unresolved map[string]string `json:"unresolved,omitempty" yaml:"unresolved,omitempty"`
+}
+
+// previewSurface is one shaded point of preview geometry.
+type previewSurface struct {
+	normal    [3]float64 // Geometric normal
+	tangent   [3]float64 // Direction of increasing U
+	bitangent [3]float64 // Direction of increasing V
+	u, v      float64    // Texture coordinates
+}
+
+// previewStage is one texture input of preview shading.
+type previewStage struct {
+	sample    proceduralSampler // Texture sampler; nil uses role default
+	transform *UVTransform      // Effective stage uvTransform
+	lookup    bool              // Sampler is indexed by N dot V instead of UV
+}
+
+// previewRenderer holds resolved inputs for one material preview.
+type previewRenderer struct {
+	unresolved map[string]string
+	stages     map[string]previewStage
+	base       previewStage
+	ambient    [3]float64
+	diffuse    [3]float64
+	forced     [3]float64
+	emissive   [3]float64
+	specular   [3]float64
+	light      [3]float64
+	power      float64
+	ambLight   float64
+}
+
+// RenderPreview renders material on sphere or plane with approximated
+// Super shader lighting.
+//
+// Stage roles follow Super layout: Stage1 normal map (_nohq), Stage2 detail
+// (_dt), Stage3 macro (_mc), Stage4 ambient shadow (_as, green channel),
+// Stage5 specular (_smdi: green intensity, blue gloss scaling specularPower),
+// Stage6 fresnel lookup weighting Stage7 environment sphere map reflection. Procedural stages
+// are evaluated directly; path stages are loaded through FileSystem with
+// PNG/TGA/PAA fallbacks by extension priority. Missing textures are replaced
+// by neutral defaults and reported in PreviewResult.Unresolved.
+func RenderPreview(m *Material, opt *PreviewOptions) (*PreviewResult, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: preview", ErrMaterialNotFound)
+	}
+
+	o := PreviewOptions{}
+	if opt != nil {
+		o = *opt
+	}
+	if o.Width <= 0 {
+		o.Width = defaultPreviewSize
+	}
+	if o.Height <= 0 {
+		o.Height = defaultPreviewSize
+	}
+	if !rasterSizeFits(o.Width, o.Height) {
+		return nil, fmt.Errorf("%w: size %dx%d", ErrInvalidPreviewOption, o.Width, o.Height)
+	}
+	if o.Shape != PreviewShapeSphere && o.Shape != PreviewShapePlane {
+		return nil, fmt.Errorf("%w: shape %s", ErrInvalidPreviewOption, o.Shape)
+	}
+	for key, img := range o.Images {
+		if img != nil && img.Bounds().Empty() {
+			return nil, fmt.Errorf("%w: image %q is empty", ErrInvalidPreviewOption, key)
+		}
+	}
+
+	r := newPreviewRenderer(m, o)
+	img := image.NewNRGBA(image.Rect(0, 0, o.Width, o.Height))
+	for y := range o.Height {
+		for x := range o.Width {
+			surface, ok := previewGeometry(o.Shape, x, y, o.Width, o.Height)
+			if !ok {
+				continue
+			}
+
+			img.SetNRGBA(x, y, r.shade(surface))
+		}
+	}
+
+	result := &PreviewResult{Image: img}
+	if len(r.unresolved) > 0 {
+		result.Unresolved = r.unresolved
+	}
+
+	return result, nil
+}
+
+// RenderPreviewPNG renders material preview and writes it as PNG.
+func RenderPreviewPNG(w io.Writer, m *Material, opt *PreviewOptions) error {
+	result, err := RenderPreview(m, opt)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, result.Image)
+}
+
+// RenderPreviewCompare renders materials side by side in one row.
+func RenderPreviewCompare(materials []*Material, opt *PreviewOptions) (*image.NRGBA, error) {
+	return renderPreviewGrid([][]*Material{materials}, opt)
+}
+
+// RenderFinishConditionPreview renders generated material variants as grid
+// with finishes in columns and conditions in rows.
+//
+// Empty finishes or conditions use all non-default values.
+func RenderFinishConditionPreview(
+	gen GenerateOptions,
+	finishes []Finish,
+	conditions []Condition,
+	opt *PreviewOptions,
+) (*image.NRGBA, error) {
+	if len(finishes) == 0 {
+		finishes = []Finish{FinishMatte, FinishSatin, FinishGloss, FinishPolished}
+	}
+	if len(conditions) == 0 {
+		conditions = []Condition{ConditionClean, ConditionWorn, ConditionDirty, ConditionOxidized}
+	}
+
+	rows := make([][]*Material, 0, len(conditions))
+	for _, condition := range conditions {
+		row := make([]*Material, 0, len(finishes))
+		for _, finish := range finishes {
+			variant := gen
+			variant.Finish = finish
+			variant.Condition = condition
+
+			m, err := Generate(variant)
+			if err != nil {
+				return nil, fmt.Errorf("generate %s/%s preview: %w", finish, condition, err)
+			}
+			row = append(row, m)
+		}
+		rows = append(rows, row)
+	}
+
+	return renderPreviewGrid(rows, opt)
+}
+
+// renderPreviewGrid renders material previews into one image grid.
+func renderPreviewGrid(rows [][]*Material, opt *PreviewOptions) (*image.NRGBA, error) {
+	cellW, cellH := defaultPreviewSize, defaultPreviewSize
+	if opt != nil && opt.Width > 0 {
+		cellW = opt.Width
+	}
+	if opt != nil && opt.Height > 0 {
+		cellH = opt.Height
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if !rasterSizeFits(cellW, cellH, columns, len(rows)) {
+		return nil, fmt.Errorf("%w: grid %dx%d of %dx%d", ErrInvalidPreviewOption, columns, len(rows), cellW, cellH)
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, cellW*columns, cellH*len(rows)))
+	for y, row := range rows {
+		for x, m := range row {
+			result, err := RenderPreview(m, opt)
+			if err != nil {
+				return nil, fmt.Errorf("preview %d,%d: %w", x, y, err)
+			}
+
+			cell := image.Rect(x*cellW, y*cellH, (x+1)*cellW, (y+1)*cellH)
+			draw.Draw(out, cell, result.Image, image.Point{}, draw.Src)
+		}
+	}
+
+	return out, nil
+}
+
+// newPreviewRenderer resolves material parameters and stage textures.
+func newPreviewRenderer(m *Material, o PreviewOptions) *previewRenderer {
+	r := &previewRenderer{
+		unresolved: map[string]string{},
+		stages:     map[string]previewStage{},
+		ambient:    previewColor(m.Ambient, 1),
+		diffuse:    previewColor(m.Diffuse, 1),
+		forced:     previewColor(m.ForcedDiffuse, 0),
+		emissive:   previewColor(m.Emissive, 0),
+		specular:   previewColor(m.Specular, 0),
+		light:      normalize3(o.LightDirection),
+		power:      1,
+		ambLight:   o.AmbientLight,
+	}
+	if m.SpecularPower != nil && *m.SpecularPower > 1 {
+		r.power = *m.SpecularPower
+	}
+	if o.LightDirection == ([3]float64{}) {
+		r.light = normalize3(defaultPreviewLight)
+	}
+	if r.ambLight <= 0 {
+		r.ambLight = defaultPreviewAmbientLight
+	}
+
+	if img := previewImage(o.Images, PreviewBaseImageKey); img != nil {
+		r.base = previewStage{sample: imageSampler(img)}
+	} else if o.BaseTexture != "" {
+		r.base = r.loadStage(o, PreviewBaseImageKey, ParseTextureRef(o.BaseTexture))
+	}
+
+	for _, stage := range m.Stages {
+		role, ok := textureRoleForStageName(stage.Name)
+		if !ok {
+			continue
+		}
+
+		var ps previewStage
+		if img := previewImage(o.Images, stage.Name); img != nil {
+			ps = previewStage{sample: imageSampler(img)}
+		} else {
+			ps = r.loadStage(o, stage.Name, stage.Texture)
+		}
+		if tr, err := EffectiveUVTransform(m, stage); err == nil {
+			ps.transform = tr
+		}
+		r.stages[role] = ps
+	}
+
+	return r
+}
+
+// loadStage builds sampler for procedural or path texture.
+func (r *previewRenderer) loadStage(o PreviewOptions, key string, tex TextureRef) previewStage {
+	if tex.IsProcedural() {
+		if !tex.ParsedOK || tex.Procedural == nil {
+			r.unresolved[key] = "procedural texture parse failed"
+			return previewStage{}
+		}
+
+		sample, err := proceduralEvaluator(tex.Procedural)
+		if err != nil {
+			r.unresolved[key] = err.Error()
+			return previewStage{}
+		}
+		if strings.EqualFold(strings.TrimSpace(tex.Procedural.Format), "ai") {
+			sample = alphaIntensitySampler(sample)
+		}
+
+		_, lookup := previewLookupFns[strings.ToLower(tex.Procedural.Func)]
+		return previewStage{sample: sample, lookup: lookup}
+	}
+
+	if tex.Raw == "" {
+		return previewStage{}
+	}
+
+	resolver := PathResolver{FileSystem: o.FileSystem, GameRoot: o.GameRoot}
+	img, err := loadTextureImageAnyExt(o.FileSystem, resolver.ResolvePath(tex.Raw))
+	if err != nil {
+		r.unresolved[key] = err.Error()
+		return previewStage{}
+	}
+
+	return previewStage{sample: imageSampler(img)}
+}
+
+// shade returns preview color for one surface point.
+func (r *previewRenderer) shade(s previewSurface) color.NRGBA {
+	view := [3]float64{0, 0, 1}
+
+	// Base color is composed in texture space, then converted to linear.
+	base := [4]float64{0.5, 0.5, 0.5, 1}
+	if c, ok := r.sample(r.base, s, 0); ok {
+		base = c
+	}
+	if dt, ok := r.sample(r.stages["dt"], s, 0); ok {
+		for i := range 3 {
+			base[i] *= 2 * dt[i]
+		}
+	}
+	if mc, ok := r.sample(r.stages["mc"], s, 0); ok {
+		for i := range 3 {
+			base[i] += (mc[i] - base[i]) * clamp01(mc[3])
+		}
+	}
+	for i := range 3 {
+		base[i] = math.Pow(clamp01(base[i]), previewGamma)
+	}
+
+	normal := s.normal
+	if n, ok := r.sample(r.stages["nohq"], s, 0); ok {
+		tx, ty, tz := 2*n[0]-1, 2*n[1]-1, 2*n[2]-1
+		for i := range 3 {
+			normal[i] = s.tangent[i]*tx + s.bitangent[i]*ty + s.normal[i]*tz
+		}
+		normal = normalize3(normal)
+	}
+
+	ao := 1.0
+	if as, ok := r.sample(r.stages["as"], s, 0); ok {
+		ao = as[1]
+	}
+
+	specIntensity, gloss := 1.0, 1.0
+	if smdi, ok := r.sample(r.stages["smdi"], s, 0); ok {
+		specIntensity, gloss = smdi[1], smdi[2]
+	}
+
+	nDotV := math.Max(dot3(normal, view), 0)
+	fresnel := 1.0
+	if f, ok := r.sample(r.stages["fresnel"], s, nDotV); ok {
+		fresnel = f[0]
+	}
+
+	// Environment is used as linear multiplier, like HDR color(4,4,4,1) stages.
+	env := [3]float64{0.5, 0.5, 0.5}
+	reflected := reflect3(view, normal)
+	if e, ok := r.sample(r.stages["env"], sphereMapSurface(reflected), 0); ok {
+		env = [3]float64{math.Max(e[0], 0), math.Max(e[1], 0), math.Max(e[2], 0)}
+	}
+
+	nDotL := math.Max(dot3(normal, r.light), 0)
+	half := normalize3([3]float64{r.light[0] + view[0], r.light[1] + view[1], r.light[2] + view[2]})
+	// Energy-normalized Blinn-Phong keeps tight highlights visible at the
+	// low specular values typical for game materials.
+	highlight := 0.0
+	if nDotL > 0 {
+		power := math.Max(r.power*gloss, 1)
+		highlight = math.Pow(math.Max(dot3(normal, half), 0), power) * (power + 2) / 8
+	}
+
+	var out color.NRGBA
+	out.A = unitToByte(base[3])
+	channels := [3]*uint8{&out.R, &out.G, &out.B}
+	for i := range 3 {
+		lit := base[i]*(r.diffuse[i]*nDotL+r.ambient[i]*r.ambLight*ao+r.forced[i]) +
+			r.specular[i]*specIntensity*(highlight+fresnel*env[i]*ao) +
+			r.emissive[i]
+		*channels[i] = unitToByte(math.Pow(clamp01(lit), 1/previewGamma))
+	}
+
+	return out
+}
+
+// sample evaluates stage at surface point; lookup stages use nDotV.
+func (r *previewRenderer) sample(st previewStage, s previewSurface, nDotV float64) ([4]float64, bool) {
+	if st.sample == nil {
+		return [4]float64{}, false
+	}
+	if st.lookup {
+		cr, cg, cb, ca := st.sample(nDotV, 0.5)
+		return [4]float64{cr, cg, cb, ca}, true
+	}
+
+	u, v := s.u, s.v
+	if t := st.transform; t != nil {
+		u, v = transformUV(t, s.u, s.v)
+	}
+
+	cr, cg, cb, ca := st.sample(u, v)
+	return [4]float64{cr, cg, cb, ca}, true
+}
+
+// previewGeometry returns surface for pixel or false outside geometry.
+func previewGeometry(shape PreviewShape, x, y, width, height int) (previewSurface, bool) {
+	if shape == PreviewShapePlane {
+		return previewSurface{
+			normal:    [3]float64{0, 0, 1},
+			tangent:   [3]float64{1, 0, 0},
+			bitangent: [3]float64{0, -1, 0},
+			u:         (float64(x) + 0.5) / float64(width),
+			v:         (float64(y) + 0.5) / float64(height),
+		}, true
+	}
+
+	radius := 0.95 * float64(min(width, height)) / 2
+	px := (float64(x) + 0.5 - float64(width)/2) / radius
+	py := -(float64(y) + 0.5 - float64(height)/2) / radius
+	r2 := px*px + py*py
+	if r2 > 1 {
+		return previewSurface{}, false
+	}
+
+	normal := [3]float64{px, py, math.Sqrt(1 - r2)}
+	tangent := [3]float64{1, 0, 0}
+	if math.Abs(normal[0])+math.Abs(normal[2]) > 1e-9 {
+		tangent = normalize3([3]float64{normal[2], 0, -normal[0]})
+	}
+
+	return previewSurface{
+		normal:    normal,
+		tangent:   tangent,
+		bitangent: cross3(tangent, normal),
+		u:         0.5 + math.Atan2(normal[0], normal[2])/(2*math.Pi),
+		v:         math.Acos(math.Max(math.Min(normal[1], 1), -1)) / math.Pi,
+	}, true
+}
+
+// sphereMapSurface returns environment sphere map coordinates of direction.
+func sphereMapSurface(dir [3]float64) previewSurface {
+	m := 2 * math.Sqrt(dir[0]*dir[0]+dir[1]*dir[1]+(dir[2]+1)*(dir[2]+1))
+	if m == 0 {
+		return previewSurface{u: 0.5, v: 0.5}
+	}
+
+	return previewSurface{u: dir[0]/m + 0.5, v: -dir[1]/m + 0.5}
+}
+
+// transformUV applies uvTransform aside/up/pos to texture coordinates.
+func transformUV(t *UVTransform, u, v float64) (float64, float64) {
+	at := func(values []float64, i int, fallback float64) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return fallback
+	}
+
+	return at(t.Aside, 0, 1)*u + at(t.Up, 0, 0)*v + at(t.Pos, 0, 0),
+		at(t.Aside, 1, 0)*u + at(t.Up, 1, 1)*v + at(t.Pos, 1, 0)
+}
+
+// imageSampler returns bilinear wrapping sampler of image in 0..1 range.
+// Empty image yields nil sampler, same as missing texture.
+func imageSampler(img image.Image) proceduralSampler {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+
+	texel := func(x, y int) [4]float64 {
+		x, y = ((x%w)+w)%w, ((y%h)+h)%h
+		c := nrgba.NRGBAAt(x, y)
+		return [4]float64{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255}
+	}
+
+	return func(u, v float64) (float64, float64, float64, float64) {
+		fx, fy := u*float64(w)-0.5, v*float64(h)-0.5
+		x0, y0 := math.Floor(fx), math.Floor(fy)
+		tx, ty := fx-x0, fy-y0
+		ix, iy := int(x0), int(y0)
+
+		c00, c10 := texel(ix, iy), texel(ix+1, iy)
+		c01, c11 := texel(ix, iy+1), texel(ix+1, iy+1)
+		var out [4]float64
+		for i := range out {
+			top := c00[i] + (c10[i]-c00[i])*tx
+			bottom := c01[i] + (c11[i]-c01[i])*tx
+			out[i] = top + (bottom-top)*ty
+		}
+
+		return out[0], out[1], out[2], out[3]
+	}
+}
+
+// alphaIntensitySampler converts sampler output to "ai" gray intensity.
+func alphaIntensitySampler(sample proceduralSampler) proceduralSampler {
+	return func(u, v float64) (float64, float64, float64, float64) {
+		r, g, b, a := sample(u, v)
+		i := luminance(r, g, b)
+		return i, i, i, a
+	}
+}
+
+// previewImage returns image by exact or case-insensitive key.
+func previewImage(images map[string]image.Image, key string) image.Image {
+	if img, ok := images[key]; ok {
+		return img
+	}
+	for k, img := range images {
+		if strings.EqualFold(k, key) {
+			return img
+		}
+	}
+
+	return nil
+}
+
+// previewColor returns RGB of material color array or fallback.
+func previewColor(values []float64, fallback float64) [3]float64 {
+	out := [3]float64{fallback, fallback, fallback}
+	for i := range min(len(values), 3) {
+		out[i] = values[i]
+	}
+
+	return out
+}
+
+// dot3 returns dot product of vectors.
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// cross3 returns cross product of vectors.
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// normalize3 returns unit vector or zero vector for zero input.
+func normalize3(v [3]float64) [3]float64 {
+	l := math.Sqrt(dot3(v, v))
+	if l == 0 {
+		return v
+	}
+
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
+
+// reflect3 reflects view direction around normal.
+func reflect3(view, normal [3]float64) [3]float64 {
+	d := 2 * dot3(normal, view)
+	return [3]float64{d*normal[0] - view[0], d*normal[1] - view[1], d*normal[2] - view[2]}
+}
@@ -0,0 +1,113 @@
+package rvmat
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func maxLuma(img *image.NRGBA, rect image.Rectangle) int {
+	best := 0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			best = max(best, int(c.R)+int(c.G)+int(c.B))
+		}
+	}
+
+	return best
+}
+
+func TestRenderPreview(t *testing.T) {
+	base := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := range base.Pix {
+		base.Pix[i] = 200
+		if i%4 == 3 {
+			base.Pix[i] = 255
+		}
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, base); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	fsys := NewMemFileSystem()
+	fsys.AddFile("mod/data/box_co.png", encoded.Bytes())
+
+	m, err := Generate(GenerateOptions{BaseMaterial: BaseMaterialSteel})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	result, err := RenderPreview(m, &PreviewOptions{
+		FileSystem:  fsys,
+		BaseTexture: `mod\data\box_co.paa`,
+		Width:       32,
+		Height:      32,
+	})
+	if err != nil {
+		t.Fatalf("RenderPreview: %v", err)
+	}
+	if _, ok := result.Unresolved[PreviewBaseImageKey]; ok {
+		t.Fatalf("expected base texture fallback to png: %v", result.Unresolved)
+	}
+	if _, ok := result.Unresolved["Stage7"]; !ok {
+		t.Fatalf("expected missing environment texture to be reported: %v", result.Unresolved)
+	}
+	if c := result.Image.NRGBAAt(16, 16); c.A != 255 || c.R == 0 {
+		t.Fatalf("unexpected sphere center: %+v", c)
+	}
+	if c := result.Image.NRGBAAt(0, 0); c != (color.NRGBA{}) {
+		t.Fatalf("expected transparent corner: %+v", c)
+	}
+
+	plane, err := RenderPreview(m, &PreviewOptions{Shape: PreviewShapePlane, Width: 8, Height: 4})
+	if err != nil || plane.Image.NRGBAAt(0, 0).A != 255 {
+		t.Fatalf("unexpected plane preview: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := RenderPreviewPNG(&out, m, &PreviewOptions{Width: 8, Height: 8}); err != nil || out.Len() == 0 {
+		t.Fatalf("RenderPreviewPNG: %d bytes, %v", out.Len(), err)
+	}
+
+	if _, err := RenderPreview(m, &PreviewOptions{Shape: PreviewShape(9)}); !errors.Is(err, ErrInvalidPreviewOption) {
+		t.Fatalf("expected ErrInvalidPreviewOption, got %v", err)
+	}
+	empty := map[string]image.Image{PreviewBaseImageKey: image.NewNRGBA(image.Rect(0, 0, 0, 0))}
+	if _, err := RenderPreview(m, &PreviewOptions{Images: empty}); !errors.Is(err, ErrInvalidPreviewOption) {
+		t.Fatalf("expected ErrInvalidPreviewOption for empty image, got %v", err)
+	}
+	if _, err := RenderPreview(m, &PreviewOptions{Width: 1 << 32, Height: 1 << 32}); !errors.Is(err, ErrInvalidPreviewOption) {
+		t.Fatalf("expected ErrInvalidPreviewOption for overflowing size, got %v", err)
+	}
+}
+
+func TestRenderFinishConditionPreview(t *testing.T) {
+	img, err := RenderFinishConditionPreview(
+		GenerateOptions{BaseMaterial: BaseMaterialPlastic},
+		[]Finish{FinishMatte, FinishPolished},
+		[]Condition{ConditionClean, ConditionDirty},
+		&PreviewOptions{Width: 48, Height: 48},
+	)
+	if err != nil {
+		t.Fatalf("RenderFinishConditionPreview: %v", err)
+	}
+	if img.Bounds().Dx() != 96 || img.Bounds().Dy() != 96 {
+		t.Fatalf("unexpected grid size: %v", img.Bounds())
+	}
+
+	matte := maxLuma(img, image.Rect(0, 0, 48, 48))
+	polished := maxLuma(img, image.Rect(48, 0, 96, 48))
+	dirty := maxLuma(img, image.Rect(48, 48, 96, 96))
+	if polished <= matte || dirty >= polished {
+		t.Fatalf("unexpected highlights: matte=%d polished=%d dirty-polished=%d", matte, polished, dirty)
+	}
+
+	if _, err := RenderPreviewCompare(nil, nil); !errors.Is(err, ErrInvalidPreviewOption) {
+		t.Fatalf("expected empty compare error, got %v", err)
+	}
+}
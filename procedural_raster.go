@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const (
+	// defaultFresnelGlassIOR is refractive index used by fresnelGlass() without arguments.
+	defaultFresnelGlassIOR = 1.5
+
+	// maxRasterPixels limits rasterized image size.
+	maxRasterPixels = 4096 * 4096
+)
+
+// RasterizeOptions controls procedural texture rasterization.
+type RasterizeOptions struct {
+	// Width overrides header width when > 0 (e.g. for thumbnails).
+	Width int `json:"width,omitempty" yaml:"width,omitempty"`
+	// Height overrides header height when > 0.
+	Height int `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+// Rasterize evaluates procedural texture reference into image.
+func (t TextureRef) Rasterize(opt *RasterizeOptions) (*image.NRGBA, error) {
+	if !t.IsProcedural() || !t.ParsedOK || t.Procedural == nil {
+		return nil, fmt.Errorf("%w: %q is not a parsed procedural texture", ErrUnsupportedProcedural, t.Raw)
+	}
+
+	return RasterizeProcedural(t.Procedural, opt)
+}
+
+// RasterizeProcedural evaluates procedural texture into image.
+//
+// Format "argb" (and "dxt1"/"dxt5", which only select storage compression)
+// writes RGBA as is; "ai" writes alpha+intensity as gray RGB with alpha.
+// Values are clamped to 0..1, so HDR colors such as color(4,4,4,1) saturate.
+//
+// Lookup functions are evaluated along X with cosine of view angle
+// (N dot V) going from 0 at left edge to 1 at right edge; rows are equal:
+//
+//   - fresnel(n,k): unpolarized conductor Fresnel reflectance for complex
+//     refractive index n+ik; intensity is reflectance, alpha is 1,
+//   - fresnelGlass(n): dielectric reflectance (n defaults to 1.5);
+//     intensity is reflectance, alpha is transmittance 1-R,
+//   - irradiance(p), waterIrradiance(p): specular lobe cos^p,
+//   - treeCrown(d): Beer-Lambert transmittance exp(-d*x) through crown depth,
+//   - treeCrownAmb(d): transmittance averaged over depth 0..x.
+//
+// perlinNoise(sx,sy,min,max) is deterministic gradient noise with periods
+// sx/sy given in header texels, mapped to min..max. point(r,g,b,a) is color
+// with linear radial falloff from texture center to edge.
+func RasterizeProcedural(pt *ProceduralTexture, opt *RasterizeOptions) (*image.NRGBA, error) {
+	if pt == nil {
+		return nil, fmt.Errorf("%w: nil procedural texture", ErrUnsupportedProcedural)
+	}
+
+	width, height := pt.Width, pt.Height
+	if opt != nil && opt.Width > 0 {
+		width = opt.Width
+	}
+	if opt != nil && opt.Height > 0 {
+		height = opt.Height
+	}
+	if !rasterSizeFits(width, height) {
+		return nil, fmt.Errorf("%w: invalid size %dx%d", ErrUnsupportedProcedural, width, height)
+	}
+
+	alphaIntensity := false
+	switch strings.ToLower(strings.TrimSpace(pt.Format)) {
+	case "argb", "dxt1", "dxt5":
+	case "ai":
+		alphaIntensity = true
+	default:
+		return nil, fmt.Errorf("%w: format %q", ErrUnsupportedProcedural, pt.Format)
+	}
+
+	eval, err := proceduralEvaluator(pt)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		v := (float64(y) + 0.5) / float64(height)
+		for x := range width {
+			u := (float64(x) + 0.5) / float64(width)
+			r, g, b, a := eval(u, v)
+			if alphaIntensity {
+				i := luminance(r, g, b)
+				r, g, b = i, i, i
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: unitToByte(r),
+				G: unitToByte(g),
+				B: unitToByte(b),
+				A: unitToByte(a),
+			})
+		}
+	}
+
+	return img, nil
+}
+
+// proceduralSampler returns RGBA for normalized texel coordinates.
+type proceduralSampler func(u, v float64) (r, g, b, a float64)
+
+// proceduralEvaluator returns sampler for parsed procedural function.
+func proceduralEvaluator(pt *ProceduralTexture) (proceduralSampler, error) {
+	fn := strings.ToLower(strings.TrimSpace(pt.Func))
+	switch {
+	case fn == "color" && pt.Color != nil:
+		c := *pt.Color
+		return func(_, _ float64) (float64, float64, float64, float64) {
+			return c.R, c.G, c.B, c.A
+		}, nil
+
+	case fn == "fresnel" && pt.Fresnel != nil:
+		n, k := pt.Fresnel.A, pt.Fresnel.B
+		return func(u, _ float64) (float64, float64, float64, float64) {
+			r := FresnelReflectance(n, k, u)
+			return r, r, r, 1
+		}, nil
+
+	case fn == "fresnelglass" && (pt.Fresnel != nil || len(pt.Args) == 0):
+		n := defaultFresnelGlassIOR
+		if pt.Fresnel != nil {
+			n = pt.Fresnel.A
+		}
+		return func(u, _ float64) (float64, float64, float64, float64) {
+			r := FresnelReflectance(n, 0, u)
+			return r, r, r, 1 - r
+		}, nil
+
+	case fn == "point" && pt.Color != nil:
+		c := *pt.Color
+		return func(u, v float64) (float64, float64, float64, float64) {
+			f := math.Max(1-2*math.Hypot(u-0.5, v-0.5), 0)
+			return c.R * f, c.G * f, c.B * f, c.A * f
+		}, nil
+
+	case fn == "perlinnoise" && pt.PerlinNoise != nil &&
+		pt.PerlinNoise.XScale > 0 && pt.PerlinNoise.YScale > 0:
+		p := *pt.PerlinNoise
+		sx := float64(max(pt.Width, 1)) / p.XScale
+		sy := float64(max(pt.Height, 1)) / p.YScale
+		return func(u, v float64) (float64, float64, float64, float64) {
+			i := p.Min + (p.Max-p.Min)*perlinNoise2D(u*sx, v*sy)
+			return i, i, i, 1
+		}, nil
+
+	case fn == "treecrown" && pt.TreeCrown != nil:
+		density := math.Max(pt.TreeCrown.Density, 0)
+		return func(u, _ float64) (float64, float64, float64, float64) {
+			i := math.Exp(-density * u)
+			return i, i, i, 1
+		}, nil
+
+	case fn == "treecrownamb" && pt.TreeCrown != nil:
+		density := math.Max(pt.TreeCrown.Density, 0)
+		return func(u, _ float64) (float64, float64, float64, float64) {
+			i := 1.0
+			if x := density * u; x > 0 {
+				i = (1 - math.Exp(-x)) / x
+			}
+			return i, i, i, 1
+		}, nil
+
+	case (fn == "irradiance" || fn == "waterirradiance") && pt.Irradiance != nil:
+		power := math.Max(pt.Irradiance.Value, 0)
+		return func(u, _ float64) (float64, float64, float64, float64) {
+			i := math.Pow(u, power)
+			return i, i, i, 1
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s(%s)", ErrUnsupportedProcedural, pt.Func, strings.Join(pt.Args, ","))
+	}
+}
+
+// perlinNoise2D returns gradient noise in 0..1 at point x,y.
+func perlinNoise2D(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	ix, iy := int64(x0), int64(y0)
+
+	n00 := perlinGradient(ix, iy, fx, fy)
+	n10 := perlinGradient(ix+1, iy, fx-1, fy)
+	n01 := perlinGradient(ix, iy+1, fx, fy-1)
+	n11 := perlinGradient(ix+1, iy+1, fx-1, fy-1)
+
+	sx, sy := perlinFade(fx), perlinFade(fy)
+	n0 := n00 + sx*(n10-n00)
+	n1 := n01 + sx*(n11-n01)
+
+	// 2D gradient noise stays within -sqrt(0.5)..sqrt(0.5).
+	return math.Min(math.Max((n0+sy*(n1-n0))*math.Sqrt2/2+0.5, 0), 1)
+}
+
+// perlinGradient returns dot product of lattice gradient and offset.
+func perlinGradient(ix, iy int64, dx, dy float64) float64 {
+	h := uint64(ix)*0x9E3779B97F4A7C15 ^ uint64(iy)*0xC2B2AE3D27D4EB4F
+	h ^= h >> 29
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 32
+
+	angle := float64(h&0xFFFF) / 0x10000 * 2 * math.Pi
+	return math.Cos(angle)*dx + math.Sin(angle)*dy
+}
+
+// perlinFade returns quintic interpolation weight.
+func perlinFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// FresnelReflectance returns unpolarized Fresnel reflectance for
+// complex refractive index n+ik at incidence angle with given cosine.
+//
+// k = 0 gives dielectric reflectance. Result is clamped to 0..1.
+func FresnelReflectance(n, k, cosTheta float64) float64 {
+	c := math.Min(math.Max(cosTheta, 0), 1)
+	s2 := 1 - c*c
+
+	// Conductor form with a^2+b^2 = sqrt((n^2-k^2-sin^2)^2 + 4n^2k^2).
+	t := n*n - k*k - s2
+	a2b2 := math.Sqrt(t*t + 4*n*n*k*k)
+	a := math.Sqrt(math.Max((a2b2+t)/2, 0))
+
+	rsNum := a2b2 + c*c - 2*a*c
+	rsDen := a2b2 + c*c + 2*a*c
+	if rsDen == 0 {
+		return 1
+	}
+	rs := rsNum / rsDen
+
+	rpNum := a2b2*c*c + s2*s2 - 2*a*c*s2
+	rpDen := a2b2*c*c + s2*s2 + 2*a*c*s2
+	rp := rs
+	if rpDen != 0 {
+		rp = rs * rpNum / rpDen
+	}
+
+	return math.Min(math.Max((rs+rp)/2, 0), 1)
+}
+
+// BaseMaterialFresnel returns Stage6 fresnel texture used by generator profile.
+func BaseMaterialFresnel(material BaseMaterial) (TextureRef, error) {
+	material, err := normalizeBaseMaterial(material)
+	if err != nil {
+		return TextureRef{}, err
+	}
+
+	return materialSeedFresnel(materialCatalog[material]), nil
+}
+
+// RasterizeFresnelPresets renders fresnel curves of all generator base
+// materials as stacked rows of rowHeight pixels, in BaseMaterial order.
+//
+// Returned slice maps row index to base material.
+func RasterizeFresnelPresets(width, rowHeight int) (*image.NRGBA, []BaseMaterial, error) {
+	materials := make([]BaseMaterial, 0, len(materialCatalog))
+	for material := BaseMaterialTextile; material <= BaseMaterialSkin; material++ {
+		if _, ok := materialCatalog[material]; ok {
+			materials = append(materials, material)
+		}
+	}
+	if !rasterSizeFits(width, rowHeight, len(materials)) {
+		return nil, nil, fmt.Errorf("%w: invalid size %dx%d", ErrUnsupportedProcedural, width, rowHeight)
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, width, rowHeight*len(materials)))
+	for row, material := range materials {
+		tex := materialSeedFresnel(materialCatalog[material])
+		img, err := tex.Rasterize(&RasterizeOptions{Width: width, Height: rowHeight})
+		if err != nil {
+			return nil, nil, fmt.Errorf("rasterize %s fresnel: %w", material, err)
+		}
+
+		for y := range rowHeight {
+			copy(out.Pix[out.PixOffset(0, row*rowHeight+y):], img.Pix[img.PixOffset(0, y):img.PixOffset(0, y+1)])
+		}
+	}
+
+	return out, materials, nil
+}
+
+// rasterSizeFits reports whether dimensions are positive and their product
+// stays within maxRasterPixels without overflow.
+func rasterSizeFits(dims ...int) bool {
+	total := 1
+	for _, d := range dims {
+		if d <= 0 || d > maxRasterPixels/total {
+			return false
+		}
+		total *= d
+	}
+
+	return true
+}
+
+// luminance returns Rec.601 luma of linear RGB.
+func luminance(r, g, b float64) float64 {
+	return 0.299*r + 0.587*g + 0.114*b
+}
+
+// unitToByte converts 0..1 value to clamped 8-bit channel.
+func unitToByte(v float64) uint8 {
+	if math.IsNaN(v) || v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 255
+	}
+
+	return uint8(math.Round(v * 255))
+}
@@ -0,0 +1,140 @@
+package rvmat
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRasterizeColor(t *testing.T) {
+	img, err := ParseTextureRef("#(argb,8,8,3)color(1,0.5,0,0.5)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize: %v", err)
+	}
+	if img.Bounds().Dx() != 8 || img.Bounds().Dy() != 8 {
+		t.Fatalf("unexpected size: %v", img.Bounds())
+	}
+	if c := img.NRGBAAt(3, 5); c.R != 255 || c.G != 128 || c.B != 0 || c.A != 128 {
+		t.Fatalf("unexpected argb pixel: %+v", c)
+	}
+
+	img, err = ParseTextureRef("#(ai,64,64,1)color(1,0,0,1)").Rasterize(&RasterizeOptions{Width: 4, Height: 2})
+	if err != nil {
+		t.Fatalf("Rasterize ai: %v", err)
+	}
+	if img.Bounds().Dx() != 4 || img.Bounds().Dy() != 2 {
+		t.Fatalf("size override ignored: %v", img.Bounds())
+	}
+	if c := img.NRGBAAt(0, 0); c.R != c.G || c.G != c.B || c.R != 76 || c.A != 255 {
+		t.Fatalf("unexpected ai pixel: %+v", c)
+	}
+}
+
+func TestRasterizeLookupCurves(t *testing.T) {
+	img, err := ParseTextureRef("#(ai,64,1,1)fresnel(1.5,4.2)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize fresnel: %v", err)
+	}
+	if img.NRGBAAt(0, 0).R < 240 {
+		t.Fatalf("expected near-total reflection at grazing angle: %+v", img.NRGBAAt(0, 0))
+	}
+	// Conductor reflectance dips slightly before grazing angle, so only
+	// check that normal incidence matches ((n-1)^2+k^2)/((n+1)^2+k^2).
+	want := ((0.5*0.5 + 4.2*4.2) / (2.5*2.5 + 4.2*4.2))
+	if r := FresnelReflectance(1.5, 4.2, 1); math.Abs(r-want) > 1e-9 {
+		t.Fatalf("normal incidence reflectance = %v, want %v", r, want)
+	}
+	if c := img.NRGBAAt(63, 0); c.R >= img.NRGBAAt(0, 0).R || c.A != 255 {
+		t.Fatalf("unexpected normal incidence pixel: %+v", c)
+	}
+
+	img, err = ParseTextureRef("#(ai,32,1,1)fresnelGlass()").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize fresnelGlass: %v", err)
+	}
+	if c := img.NRGBAAt(31, 0); int(c.R)+int(c.A) < 254 || int(c.R)+int(c.A) > 256 {
+		t.Fatalf("expected alpha = 1-R: %+v", c)
+	}
+
+	if r := FresnelReflectance(1.5, 0, 1); math.Abs(r-0.04) > 1e-9 {
+		t.Fatalf("normal incidence glass reflectance = %v, want 0.04", r)
+	}
+
+	img, err = ParseTextureRef("#(ai,16,1,1)irradiance(8)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize irradiance: %v", err)
+	}
+	if img.NRGBAAt(0, 0).R != 0 || img.NRGBAAt(15, 0).R < 150 {
+		t.Fatalf("unexpected irradiance lobe: %+v %+v", img.NRGBAAt(0, 0), img.NRGBAAt(15, 0))
+	}
+}
+
+func TestRasterizeEngineFunctions(t *testing.T) {
+	img, err := ParseTextureRef("#(ai,64,64,1)perlinNoise(16,16,0.25,0.75)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize perlinNoise: %v", err)
+	}
+	lo, hi := uint8(255), uint8(0)
+	for i := 0; i < len(img.Pix); i += 4 {
+		lo, hi = min(lo, img.Pix[i]), max(hi, img.Pix[i])
+	}
+	if lo < 63 || hi > 192 || hi-lo < 20 {
+		t.Fatalf("perlin noise out of min..max or flat: %d..%d", lo, hi)
+	}
+
+	img, err = ParseTextureRef("#(argb,9,9,1)point(1,1,1,1)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize point: %v", err)
+	}
+	if img.NRGBAAt(4, 4).A != 255 || img.NRGBAAt(0, 0).A != 0 {
+		t.Fatalf("unexpected point falloff: center %+v corner %+v", img.NRGBAAt(4, 4), img.NRGBAAt(0, 0))
+	}
+
+	img, err = ParseTextureRef("#(ai,16,1,1)treeCrown(2)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize treeCrown: %v", err)
+	}
+	amb, err := ParseTextureRef("#(ai,16,1,1)treeCrownAmb(2)").Rasterize(nil)
+	if err != nil {
+		t.Fatalf("Rasterize treeCrownAmb: %v", err)
+	}
+	if img.NRGBAAt(15, 0).R >= amb.NRGBAAt(15, 0).R {
+		t.Fatalf("expected ambient crown term above direct: %+v %+v", img.NRGBAAt(15, 0), amb.NRGBAAt(15, 0))
+	}
+}
+
+func TestRasterizeErrors(t *testing.T) {
+	for _, raw := range []string{
+		"mod/data/box_co.paa",
+		"#(argb,8,8,3)unknownFn(1)",
+		"#(rgb,8,8,3)color(1,1,1,1)",
+		"#(argb,4294967296,4294967296,1)color(1,1,1,1)",
+	} {
+		if _, err := ParseTextureRef(raw).Rasterize(nil); !errors.Is(err, ErrUnsupportedProcedural) {
+			t.Fatalf("%s: expected ErrUnsupportedProcedural, got %v", raw, err)
+		}
+	}
+	if _, err := ParseTextureRef("#(argb,8,8,3)color(1,1,1,1)").Rasterize(
+		&RasterizeOptions{Width: 8192, Height: 8192},
+	); !errors.Is(err, ErrUnsupportedProcedural) {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+}
+
+func TestRasterizeFresnelPresets(t *testing.T) {
+	img, materials, err := RasterizeFresnelPresets(32, 4)
+	if err != nil {
+		t.Fatalf("RasterizeFresnelPresets: %v", err)
+	}
+	if len(materials) != len(materialCatalog) || img.Bounds().Dy() != 4*len(materials) {
+		t.Fatalf("unexpected presets: %d materials, bounds %v", len(materials), img.Bounds())
+	}
+	if _, _, err := RasterizeFresnelPresets(1<<32, 1<<32); !errors.Is(err, ErrUnsupportedProcedural) {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+
+	tex, err := BaseMaterialFresnel(BaseMaterialGlass)
+	if err != nil || tex.Procedural == nil || !tex.ParsedOK {
+		t.Fatalf("BaseMaterialFresnel glass: %+v, %v", tex, err)
+	}
+}
@@ -0,0 +1,337 @@
+package rvmat
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigStringQuoting(t *testing.T) {
+	input := "PixelShaderID='Su''per';\n" +
+		"VertexShaderID=\"Say \"\"hi\"\"\";\n" +
+		"class Stage1\n{\n    texture=\"dz\\данные\\\xe8\xe9_co.paa\";\n    texGen=\"0\";\n};\n" +
+		"xPath=\"dir\\\";\n"
+
+	m, err := Parse([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if m.PixelShaderID != "Su'per" {
+		t.Fatalf("single-quoted string: got %q", m.PixelShaderID)
+	}
+	if m.VertexShaderID != `Say "hi"` {
+		t.Fatalf("doubled quotes: got %q", m.VertexShaderID)
+	}
+	if want := "dz\\данные\\\xe8\xe9_co.paa"; m.Stages[0].Texture.Raw != want {
+		t.Fatalf("non-ASCII path: got %q, want %q", m.Stages[0].Texture.Raw, want)
+	}
+
+	out, err := Format(m, nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	for _, want := range []string{
+		`PixelShaderID="Su'per";`,
+		`VertexShaderID="Say ""hi""";`,
+		"texture=\"dz\\данные\\\xe8\xe9_co.paa\";",
+		`xPath="dir\";`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+// TestRoundTripProperty checks Parse(Format(m)) == m for random materials
+// with extras. Canonical materials (at most 3 decimals) use default format
+// options; wide materials use full-precision numbers (Precision -1),
+// mixed-case and non-ASCII texture paths, which compare equal after the
+// writer's texture path normalization (NormalizeGameTexturePath).
+func TestRoundTripProperty(t *testing.T) {
+	iterations := 500
+	if testing.Short() {
+		iterations = 50
+	}
+
+	for _, wide := range []bool{false, true} {
+		var opt *FormatOptions
+		if wide {
+			opt = &FormatOptions{Precision: -1}
+		}
+
+		for seed := range uint64(iterations) {
+			g := materialGen{r: rand.New(rand.NewPCG(seed, 0x5eed)), wide: wide}
+			m := g.material()
+
+			out, err := Format(m, opt)
+			if err != nil {
+				t.Fatalf("wide=%v seed %d: format: %v", wide, seed, err)
+			}
+			got, err := Parse(out, nil)
+			if err != nil {
+				t.Fatalf("wide=%v seed %d: parse: %v\n%s", wide, seed, err, out)
+			}
+
+			want := normalizedTexturePaths(m)
+			if !reflect.DeepEqual(withoutLiterals(withoutSource(got)), want) {
+				t.Fatalf("wide=%v seed %d: round-trip mismatch\nwant %#v\ngot  %#v\n%s", wide, seed, want, got, out)
+			}
+		}
+	}
+}
+
+// normalizedTexturePaths returns m with stage textures in writer form.
+func normalizedTexturePaths(m *Material) *Material {
+	for i := range m.Stages {
+		m.Stages[i].Texture = ParseTextureRef(NormalizeGameTexturePath(m.Stages[i].Texture.Raw))
+	}
+
+	return m
+}
+
+// withoutLiterals clears parse-time number source text of m, which keeps
+// full-precision numbers verbatim but is not part of material values.
+func withoutLiterals(m *Material) *Material {
+	m.literals = nil
+	for _, uv := range materialUVTransforms(m) {
+		uv.literals = nil
+	}
+	m.extras = withoutNodeLiterals(m.extras)
+	for i := range m.Stages {
+		m.Stages[i].extras = withoutNodeLiterals(m.Stages[i].extras)
+	}
+	for i := range m.TexGens {
+		m.TexGens[i].extras = withoutNodeLiterals(m.TexGens[i].extras)
+	}
+
+	return m
+}
+
+// materialUVTransforms returns stage and TexGen uvTransform blocks of m.
+func materialUVTransforms(m *Material) []*UVTransform {
+	var out []*UVTransform
+	for _, st := range m.Stages {
+		if st.UVTransform != nil {
+			out = append(out, st.UVTransform)
+		}
+	}
+	for _, tg := range m.TexGens {
+		if tg.UVTransform != nil {
+			out = append(out, tg.UVTransform)
+		}
+	}
+
+	return out
+}
+
+// withoutNodeLiterals clears number source text of values in nodes.
+func withoutNodeLiterals(nodes []node) []node {
+	for i, n := range nodes {
+		switch typed := n.(type) {
+		case assignNode:
+			typed.Value = withoutValueLiteral(typed.Value)
+			nodes[i] = typed
+		case classNode:
+			typed.Body = withoutNodeLiterals(typed.Body)
+			nodes[i] = typed
+		}
+	}
+
+	return nodes
+}
+
+// withoutValueLiteral clears number source text of v and its elements.
+func withoutValueLiteral(v value) value {
+	if v.Kind == valueNumber {
+		v.Lit = ""
+	}
+	for i := range v.Array {
+		v.Array[i] = withoutValueLiteral(v.Array[i])
+	}
+
+	return v
+}
+
+// randomStringRunes is alphabet for random config strings.
+var randomStringRunes = []string{
+	"a", "Z", "0", " ", "_", ".", "\\", "/", `"`, "'", "#", "(", ")", ",", ";",
+	"{", "}", "=", "//", "/*", "é", "ж", "ü", "日", "\xe8", "\xff",
+}
+
+// randomLowerRunes is alphabet for random canonical texture paths.
+var randomLowerRunes = []string{"a", "z", "0", "_", "é", "ж", "ü"}
+
+// randomPathRunes is alphabet for random mixed-case texture paths.
+var randomPathRunes = []string{"a", "Z", "0", "_", "é", "Ж", "Ü", "日", "\xe8", "/"}
+
+// materialGen generates random materials; wide mode adds values that only
+// round-trip with full precision or after texture path normalization.
+type materialGen struct {
+	r    *rand.Rand
+	wide bool
+}
+
+func (g materialGen) material() *Material {
+	r := g.r
+	m := &Material{
+		Ambient:       g.numbers(4),
+		Diffuse:       g.numbers(4),
+		ForcedDiffuse: g.numbers(4),
+		Emissive:      g.numbers(4),
+		Specular:      g.numbers(4),
+	}
+	if r.IntN(2) == 0 {
+		v := g.number()
+		m.SpecularPower = &v
+	}
+	if r.IntN(4) > 0 {
+		m.PixelShaderID = randomString(r, randomStringRunes, 8)
+		m.VertexShaderID = randomString(r, randomStringRunes, 8)
+	}
+
+	for i := range r.IntN(3) {
+		tg := TexGen{Name: "TexGen" + string(rune('0'+i)), UVSource: randomString(r, randomStringRunes, 4)}
+		if r.IntN(2) == 0 {
+			tg.Base = randomIdent(r)
+		}
+		if r.IntN(2) == 0 {
+			tg.UVTransform = g.uvTransform()
+		}
+		tg.extras = g.nodes(2, 1)
+		m.TexGens = append(m.TexGens, tg)
+	}
+
+	pathRunes := randomLowerRunes
+	if g.wide {
+		pathRunes = randomPathRunes
+	}
+	for i := range r.IntN(4) {
+		st := Stage{
+			Name:    "Stage" + string(rune('1'+i)),
+			Texture: ParseTextureRef(`dz\` + randomString(r, pathRunes, 6) + `\` + randomString(r, pathRunes, 6) + ".paa"),
+		}
+		if r.IntN(2) == 0 {
+			st.TexGen = string(rune('0' + r.IntN(3)))
+		} else {
+			st.UVSource = "tex"
+			st.UVTransform = g.uvTransform()
+		}
+		st.extras = g.nodes(2, 1)
+		m.Stages = append(m.Stages, st)
+	}
+
+	m.extras = g.nodes(4, 2)
+	return m
+}
+
+func (g materialGen) uvTransform() *UVTransform {
+	return &UVTransform{
+		Aside: g.numbers(3),
+		Up:    g.numbers(3),
+		Dir:   g.numbers(3),
+		Pos:   g.numbers(3),
+	}
+}
+
+func (g materialGen) nodes(limit, depth int) []node {
+	r := g.r
+	var out []node
+	for range r.IntN(limit + 1) {
+		if depth > 0 && r.IntN(3) == 0 {
+			cn := classNode{Name: "x" + randomIdent(r), Body: g.nodes(limit, depth-1)}
+			if r.IntN(2) == 0 {
+				cn.Base = randomIdent(r)
+			}
+			out = append(out, cn)
+			continue
+		}
+
+		isArray := r.IntN(2) == 0
+		val := g.value(2)
+		if isArray {
+			val = value{Kind: valueArray, Array: g.values(2)}
+		} else if val.Kind == valueArray {
+			isArray = true
+		}
+		out = append(out, assignNode{Name: "x" + randomIdent(r), Value: val, IsArray: isArray})
+	}
+
+	return out
+}
+
+func (g materialGen) values(depth int) []value {
+	n := g.r.IntN(4)
+	if n == 0 {
+		return nil
+	}
+
+	out := make([]value, n)
+	for i := range out {
+		out[i] = g.value(depth - 1)
+	}
+
+	return out
+}
+
+func (g materialGen) value(depth int) value {
+	switch g.r.IntN(4) {
+	case 0:
+		return value{Kind: valueNumber, Num: g.number()}
+	case 1:
+		return value{Kind: valueIdent, Str: randomIdent(g.r)}
+	case 2:
+		if depth > 0 {
+			return value{Kind: valueArray, Array: g.values(depth)}
+		}
+		fallthrough
+	default:
+		return value{Kind: valueString, Str: randomString(g.r, randomStringRunes, 8)}
+	}
+}
+
+// numbers returns nil or 1..n random numbers.
+func (g materialGen) numbers(n int) []float64 {
+	k := g.r.IntN(n + 1)
+	if k == 0 {
+		return nil
+	}
+
+	out := make([]float64, k)
+	for i := range out {
+		out[i] = g.number()
+	}
+
+	return out
+}
+
+// number returns value with at most 3 decimals, or in wide mode also any
+// finite value from 1e-12 to 1e12 magnitude.
+func (g materialGen) number() float64 {
+	if g.wide && g.r.IntN(2) == 0 {
+		return g.r.NormFloat64() * math.Pow(10, float64(g.r.IntN(25)-12))
+	}
+
+	return float64(g.r.IntN(20001)-10000) / 1000
+}
+
+func randomIdent(r *rand.Rand) string {
+	const letters = "abcXYZ_"
+	const tail = "abcXYZ_019"
+	b := []byte{letters[r.IntN(len(letters))]}
+	for range r.IntN(6) {
+		b = append(b, tail[r.IntN(len(tail))])
+	}
+
+	return string(b)
+}
+
+func randomString(r *rand.Rand, alphabet []string, limit int) string {
+	var b strings.Builder
+	for range r.IntN(limit) + 1 {
+		b.WriteString(alphabet[r.IntN(len(alphabet))])
+	}
+
+	return b.String()
+}
@@ -73,6 +73,52 @@ func TestProceduralTextureParse(t *testing.T) {
 	}
 }
 
+func TestProceduralEngineFunctionsParse(t *testing.T) {
+	tests := []struct {
+		tex   TextureRef
+		check func(pt *ProceduralTexture) bool
+		want  string
+	}{
+		{
+			tex:   NewProceduralPerlinNoise("ai", 512, 512, 1, 256, 128, 0.2, 1),
+			check: func(pt *ProceduralTexture) bool { return pt.PerlinNoise != nil && pt.PerlinNoise.YScale == 128 },
+			want:  "#(ai,512,512,1)perlinNoise(256,128,0.2,1)",
+		},
+		{
+			tex:   NewProceduralTreeCrown("ai", 32, 32, 1, 0.8),
+			check: func(pt *ProceduralTexture) bool { return pt.TreeCrown != nil && pt.TreeCrown.Density == 0.8 },
+			want:  "#(ai,32,32,1)treeCrown(0.8)",
+		},
+		{
+			tex:   NewProceduralTreeCrownAmb("ai", 32, 32, 1, 0.5),
+			check: func(pt *ProceduralTexture) bool { return pt.TreeCrown != nil && pt.TreeCrown.Density == 0.5 },
+			want:  "#(ai,32,32,1)treeCrownAmb(0.5)",
+		},
+		{
+			tex:   NewProceduralWaterIrradiance("ai", 32, 128, 1, 100),
+			check: func(pt *ProceduralTexture) bool { return pt.Irradiance != nil && pt.Irradiance.Value == 100 },
+			want:  "#(ai,32,128,1)waterIrradiance(100)",
+		},
+		{
+			tex:   NewProceduralPoint("argb", 16, 16, 1, 1, 0.5, 0, 1, "ca"),
+			check: func(pt *ProceduralTexture) bool { return pt.Color != nil && pt.Color.Tag == "ca" },
+			want:  "#(argb,16,16,1)point(1,0.5,0,1,ca)",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.tex.Raw != tt.want || !tt.tex.ParsedOK || !tt.check(tt.tex.Procedural) {
+			t.Fatalf("unexpected procedural %q: %+v", tt.tex.Raw, tt.tex.Procedural)
+		}
+		if diags := tt.tex.Validate(nil); len(diags) != 0 {
+			t.Fatalf("%s: unexpected diagnostics: %+v", tt.want, diags)
+		}
+		if _, err := tt.tex.Rasterize(&RasterizeOptions{Width: 8, Height: 8}); err != nil {
+			t.Fatalf("%s: Rasterize: %v", tt.want, err)
+		}
+	}
+}
+
 func TestPathResolver(t *testing.T) {
 	root := "P:\\"
 	if runtime.GOOS != "windows" {
@@ -132,7 +178,7 @@ func TestRoundTripMinimalMaterial(t *testing.T) {
 	if len(issues) != 0 {
 		t.Fatalf("unexpected validation issues: %v", issues)
 	}
-	if !reflect.DeepEqual(got, want) {
+	if !reflect.DeepEqual(withoutSource(got), want) {
 		t.Fatalf("round-trip mismatch")
 	}
 }
@@ -227,7 +273,7 @@ func TestRoundTripFullMaterial(t *testing.T) {
 	if len(issues) != 0 {
 		t.Fatalf("unexpected validation issues: %v", issues)
 	}
-	if !reflect.DeepEqual(got, want) {
+	if !reflect.DeepEqual(withoutSource(got), want) {
 		t.Fatalf("round-trip mismatch")
 	}
 }
@@ -706,6 +752,34 @@ func TestValidateTextureTable(t *testing.T) {
 			wantWarn: 1,
 			wantErr:  0,
 		},
+		{
+			name:     "valid_engine_functions_dxt5",
+			tex:      ParseTextureRef(`#(dxt5,512,512,1)perlinNoise(256,256,0,1)`),
+			opt:      &TextureValidateOptions{},
+			wantWarn: 0,
+			wantErr:  0,
+		},
+		{
+			name:     "perlin_noise_zero_scale",
+			tex:      ParseTextureRef(`#(ai,64,64,1)perlinNoise(0,32,0,1)`),
+			opt:      &TextureValidateOptions{},
+			wantWarn: 1,
+			wantErr:  0,
+		},
+		{
+			name:     "tree_crown_args_count",
+			tex:      ParseTextureRef(`#(ai,32,32,1)treeCrownAmb(0.5,1)`),
+			opt:      &TextureValidateOptions{},
+			wantWarn: 2,
+			wantErr:  0,
+		},
+		{
+			name:     "point_unknown_tag",
+			tex:      ParseTextureRef(`#(argb,8,8,1)point(1,1,1,1,wat)`),
+			opt:      &TextureValidateOptions{},
+			wantWarn: 1,
+			wantErr:  0,
+		},
 		{
 			name: "parse_failed_reports",
 			tex:  ParseTextureRef(`#(argb,8,8,3)color(1,1,1,`),
@@ -791,6 +865,85 @@ VertexShaderID = "Super";
 	}
 }
 
+func TestNumberLiteralsRoundTrip(t *testing.T) {
+	input := `ambient[]={1.0, 1, 1, 1};
+diffuse[]={0.1326, "0.5*_k", MACRO_W, 0.0001};
+specular[]={0.132647, 0, 0, 1};
+specularPower=055;
+PixelShaderID="Super";
+VertexShaderID="Super";
+class Stage1
+{
+    texture="dz\x\y_nohq.paa";
+    uvSource="tex";
+    class uvTransform
+    {
+        aside[]={1.000, 0, 0};
+        up[]={0, 1, 0};
+        dir[]={0, 0, 0};
+        pos[]={0, 0, 0};
+    };
+};
+extra=0.50;
+`
+	m, err := Parse([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if m.Diffuse[1] != 0 || m.Diffuse[2] != 0 {
+		t.Fatalf("expected relaxed tokens parsed as 0, got %v", m.Diffuse)
+	}
+
+	out, err := Format(m, nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if string(out) != input {
+		t.Fatalf("round-trip changed source:\n%s", out)
+	}
+
+	// Changed values drop their literal; untouched non-canonical neighbours
+	// (including digits beyond default precision) keep theirs, canonical
+	// ones follow Precision.
+	m.Diffuse[1] = 0.25
+	m.Stages[0].UVTransform.Aside[0] = 2
+	out, err = Format(m, &FormatOptions{Precision: 2})
+	if err != nil {
+		t.Fatalf("format changed: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{
+		`ambient[]={1.0, 1, 1, 1};`,
+		`diffuse[]={0.13, 0.25, MACRO_W, 0};`,
+		`specular[]={0.132647, 0, 0, 1};`,
+		`specularPower=055;`,
+		`aside[]={2, 0, 0};`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("expected %q in output:\n%s", want, s)
+		}
+	}
+
+	generated := &Material{Specular: []float64{0.132647, 1.0 / 3}}
+	for _, tt := range []struct {
+		want      string
+		precision int
+	}{
+		{want: `specular[]={0.1326, 0.3333};`},
+		{want: `specular[]={0.13, 0.33};`, precision: 2},
+		{want: `specular[]={0.132647, 0.333333};`, precision: 6},
+		{want: `specular[]={0.132647, 0.3333333333333333};`, precision: -1},
+	} {
+		out, err := Format(generated, &FormatOptions{Precision: tt.precision})
+		if err != nil {
+			t.Fatalf("format generated: %v", err)
+		}
+		if !strings.Contains(string(out), tt.want) {
+			t.Fatalf("precision %d: expected %q in %s", tt.precision, tt.want, out)
+		}
+	}
+}
+
 func TestUnknownFieldsRoundTrip(t *testing.T) {
 	input := `mainLight = "Sun";
 ambient[] = { 1, 1, 1, 1 };
@@ -823,3 +976,17 @@ class CustomTop { bar = 2; };
 		t.Fatalf("expected ExtraBlock in output")
 	}
 }
+
+// withoutSource drops parse-time source data so parsed material compares
+// equal to hand-built one.
+func withoutSource(m *Material) *Material {
+	m.source = nil
+	for i := range m.Stages {
+		m.Stages[i].source = nil
+	}
+	for i := range m.TexGens {
+		m.TexGens[i].source = nil
+	}
+
+	return m
+}
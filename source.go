@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+// sourceSpan stores 1-based source range of one parsed statement.
+type sourceSpan struct {
+	startLine int   // Start line
+	startCol  int   // Start column
+	endLine   int   // End line
+	endCol    int   // End column
+	start     int64 // Start byte offset
+	end       int64 // Byte offset just past last token
+}
+
+// sourceBlock stores source spans of one class and its known keys.
+type sourceBlock struct {
+	keys map[string]sourceSpan // Lower-case key spans
+	span sourceSpan            // Whole class or file span
+}
+
+// sourceInfo stores parse-time source data attached to material.
+type sourceInfo struct {
+	suppressions []suppressionDirective // Inline suppression directives
+	block        sourceBlock            // Top-level key spans
+}
+
+// isZero reports whether span has no source position.
+func (s sourceSpan) isZero() bool {
+	return s.startLine == 0
+}
+
+// contains reports whether line is inside span.
+func (s sourceSpan) contains(line int) bool {
+	return !s.isZero() && line >= s.startLine && line <= s.endLine
+}
+
+// setKey records source span for one key.
+func (b *sourceBlock) setKey(key string, span sourceSpan) {
+	if b.keys == nil {
+		b.keys = make(map[string]sourceSpan, 4)
+	}
+
+	b.keys[strings.ToLower(key)] = span
+}
+
+// keySpan returns source span of key, falling back to whole block span.
+func (b *sourceBlock) keySpan(key string) sourceSpan {
+	if b == nil {
+		return sourceSpan{}
+	}
+
+	if span, ok := b.keys[strings.ToLower(key)]; ok {
+		return span
+	}
+
+	return b.span
+}
+
+// materialKeySpan returns source span of top-level material key.
+func materialKeySpan(m *Material, key string) sourceSpan {
+	if m == nil || m.source == nil {
+		return sourceSpan{}
+	}
+
+	span, ok := m.source.block.keys[strings.ToLower(key)]
+	if !ok {
+		return sourceSpan{}
+	}
+
+	return span
+}
+
+// withSourceSpan sets diagnostic start/end positions from source span.
+func withSourceSpan(diagnostic lint.Diagnostic, span sourceSpan) lint.Diagnostic {
+	if span.isZero() {
+		return diagnostic
+	}
+
+	diagnostic.Start = lint.Position{Line: span.startLine, Column: span.startCol}
+	diagnostic.End = lint.Position{Line: span.endLine, Column: span.endCol}
+	return diagnostic
+}
+
+// withSourceSpans sets positions for diagnostics without one.
+func withSourceSpans(diagnostics []lint.Diagnostic, span sourceSpan) []lint.Diagnostic {
+	for i := range diagnostics {
+		if diagnostics[i].Start.Line != 0 {
+			continue
+		}
+
+		diagnostics[i] = withSourceSpan(diagnostics[i], span)
+	}
+
+	return diagnostics
+}
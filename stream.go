@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"strings"
+)
+
+// MaterialRecord is one material yielded by DecodeStream.
+type MaterialRecord struct {
+	Material  *Material // Parsed material
+	Name      string    // Material class name; empty for bare top-level materials
+	Base      string    // Material class base name
+	Container string    // Enclosing container class name, e.g. CfgMaterials
+	Offset    int64     // Byte offset of first material token in input
+}
+
+// DecodeStream parses materials one by one from huge or concatenated dumps.
+//
+// Input may mix bare top-level materials (plain .rvmat files written back to
+// back) with container classes such as "class CfgMaterials { class X {...}; };".
+// A class is a container when its name starts with "Cfg" or its first child
+// is a class that is not StageX/TexGenX; inside containers every such class is a material and
+// container assignments are skipped. A bare material ends where a top-level
+// key or class name repeats.
+//
+// Only the current material is kept in memory, and lexer buffers are reused.
+// Iteration stops after first error.
+func DecodeStream(r io.Reader, opt *ParseOptions) iter.Seq2[*MaterialRecord, error] {
+	return func(yield func(*MaterialRecord, error) bool) {
+		popt := opt.normalize()
+		br := toBufferedReader(r)
+		if isBinaryRVMAT(br) {
+			yield(nil, ErrBinaryRVMAT)
+			return
+		}
+
+		s := &materialStream{p: newParser(br, popt), yield: yield}
+		if err := s.run(); err != nil && !errors.Is(err, errStreamStopped) {
+			yield(nil, err)
+		}
+	}
+}
+
+// errStreamStopped signals that consumer stopped iteration.
+var errStreamStopped = errors.New("stream stopped")
+
+// materialStream walks stream tokens and emits materials.
+type materialStream struct {
+	p     *parser                           // Shared parser over whole input
+	yield func(*MaterialRecord, error) bool // Consumer callback
+}
+
+// run parses top level until EOF.
+func (s *materialStream) run() error {
+	for {
+		tok, err := s.p.peek()
+		if err != nil {
+			return err
+		}
+		if tok.Type == tokEOF {
+			return nil
+		}
+
+		if tok.Type == tokClass {
+			header, name, ok, err := s.containerHeader()
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := s.parseContainer(name); err != nil {
+					return err
+				}
+				continue
+			}
+			s.p.unread(header...)
+		}
+
+		if err := s.parseBare(); err != nil {
+			return err
+		}
+	}
+}
+
+// parseBare parses bare top-level material until a repeated key, a
+// container class, or EOF.
+func (s *materialStream) parseBare() error {
+	start := s.p.statementStart()
+	m := &Material{source: &sourceInfo{}}
+	seen := make(map[string]struct{})
+	for {
+		if err := s.p.parseMaterialBody(m, tokClass, seen); err != nil {
+			return err
+		}
+
+		tok, err := s.p.peek()
+		if err != nil {
+			return err
+		}
+		if tok.Type != tokClass {
+			break
+		}
+
+		// Stop before container or repeated class; otherwise it belongs here.
+		header, _, ok, err := s.containerHeader()
+		if err != nil {
+			return err
+		}
+		s.p.unread(header...)
+		if ok {
+			break
+		}
+
+		key, err := s.p.statementKey()
+		if err != nil {
+			return err
+		}
+		if _, dup := seen[key]; dup {
+			break
+		}
+		seen[key] = struct{}{}
+		if err := s.p.parseTopClass(m); err != nil {
+			return err
+		}
+	}
+
+	return s.emit(m, start, "", "", "")
+}
+
+// parseContainer parses container body after its opening brace.
+func (s *materialStream) parseContainer(container string) error {
+	for {
+		tok, err := s.p.peek()
+		if err != nil {
+			return err
+		}
+
+		switch tok.Type {
+		case tokRBrace:
+			_, _ = s.p.next()
+			return s.p.expectSemicolon()
+		case tokClass:
+			header, name, ok, err := s.containerHeader()
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := s.parseContainer(name); err != nil {
+					return err
+				}
+				continue
+			}
+			// Forward declaration "class Name;" carries no material data.
+			if len(header) == 3 && header[2].Type == tokSemicolon {
+				continue
+			}
+			s.p.unread(header...)
+			if err := s.parseMaterialClass(container); err != nil {
+				return err
+			}
+		case tokIdent:
+			if !strings.EqualFold(tok.Lit, "delete") {
+				// Container-level assignments are not material data.
+				if _, err := s.p.parseNode(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// "delete Name;" removes inherited class; nothing to emit.
+			_, _ = s.p.next()
+			if _, err := s.p.expect(tokIdent); err != nil {
+				return err
+			}
+			if err := s.p.expectSemicolon(); err != nil {
+				return err
+			}
+		default:
+			if _, err := s.p.parseNode(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseMaterialClass parses "class Name[: Base] { material };".
+func (s *materialStream) parseMaterialClass(container string) error {
+	start := s.p.statementStart()
+	if _, err := s.p.expect(tokClass); err != nil {
+		return err
+	}
+
+	nameTok, err := s.p.expect(tokIdent)
+	if err != nil {
+		return err
+	}
+
+	base := ""
+	if tok, _ := s.p.peek(); tok.Type == tokColon {
+		_, _ = s.p.next()
+		btok, err := s.p.expect(tokIdent)
+		if err != nil {
+			return err
+		}
+		base = btok.Lit
+	}
+
+	if _, err := s.p.expect(tokLBrace); err != nil {
+		return err
+	}
+
+	m := &Material{source: &sourceInfo{}}
+	if err := s.p.parseMaterialBody(m, tokRBrace, nil); err != nil {
+		return err
+	}
+	if _, err := s.p.expect(tokRBrace); err != nil {
+		return err
+	}
+	if err := s.p.expectSemicolon(); err != nil {
+		return err
+	}
+
+	return s.emit(m, start, nameTok.Lit, base, container)
+}
+
+// containerHeader reads class header and first body token.
+//
+// When class is a container, its header and opening brace stay consumed and
+// ok is true. Otherwise consumed tokens are returned for unread.
+func (s *materialStream) containerHeader() (header []token, name string, ok bool, err error) {
+	read := func(want tokenType) bool {
+		if err != nil {
+			return false
+		}
+
+		var tok token
+		tok, err = s.p.next()
+		if err != nil {
+			return false
+		}
+
+		header = append(header, tok)
+		return tok.Type == want
+	}
+
+	if !read(tokClass) || !read(tokIdent) {
+		return header, "", false, err
+	}
+	name = header[1].Lit
+	if isStageName(name, s.p.opt) || isTexGenName(name, s.p.opt) {
+		return header, name, false, err
+	}
+
+	brace := read(tokLBrace)
+	if !brace && header[len(header)-1].Type == tokColon {
+		brace = read(tokIdent) && read(tokLBrace)
+	}
+	if brace && hasPrefixKey(name, "cfg", true) {
+		return nil, name, true, nil
+	}
+	if !brace || !read(tokClass) || !read(tokIdent) {
+		return header, name, false, err
+	}
+
+	child := header[len(header)-1].Lit
+	if isStageName(child, s.p.opt) || isTexGenName(child, s.p.opt) {
+		return header, name, false, nil
+	}
+
+	// Keep first child class for container body.
+	s.p.unread(header[len(header)-2:]...)
+	return nil, name, true, nil
+}
+
+// emit finishes material and hands it to consumer.
+func (s *materialStream) emit(m *Material, start token, name, base, container string) error {
+	s.p.finishMaterial(m, start.Line, start.Col)
+
+	// Material owns collected state now; keep memory bounded per material.
+	s.p.spans = s.p.spans[:0]
+	s.p.l.directives = nil
+
+	rec := &MaterialRecord{Material: m, Name: name, Base: base, Container: container, Offset: start.Offset}
+	if !s.yield(rec, nil) {
+		return errStreamStopped
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package rvmat
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStreamConcatenated(t *testing.T) {
+	one := "ambient[]={1,1,1,1};\nPixelShaderID=\"Super\";\nclass Stage1\n{\n    texture=\"a_nohq.paa\";\n};\n"
+	two := "ambient[]={0.5,0.5,0.5,1};\nclass Stage1\n{\n    texture=\"b_nohq.paa\";\n};\n"
+	input := one + two
+
+	var got []*MaterialRecord
+	for rec, err := range DecodeStream(strings.NewReader(input), nil) {
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		got = append(got, rec)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 materials, got %d", len(got))
+	}
+	if got[0].Offset != 0 || got[1].Offset != int64(len(one)) {
+		t.Fatalf("unexpected offsets %d, %d", got[0].Offset, got[1].Offset)
+	}
+	if got[0].Material.PixelShaderID != "Super" || got[1].Material.Stages[0].Texture.Raw != "b_nohq.paa" {
+		t.Fatalf("unexpected materials %#v, %#v", got[0].Material, got[1].Material)
+	}
+
+	want, err := Parse([]byte(one), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got[0].Name != "" || !equalMaterialText(t, got[0].Material, want) {
+		t.Fatalf("bare material differs from Parse result")
+	}
+}
+
+func TestDecodeStreamContainers(t *testing.T) {
+	input := `class CfgMaterials
+{
+	access=0;
+	class Base
+	{
+		PixelShaderID="Super";
+		class Stage1 { texture="base_nohq.paa"; };
+	};
+	class Derived: Base
+	{
+		specularPower=40;
+	};
+	class Nested
+	{
+		class Inner { ambient[]={1,1,1,1}; };
+	};
+	class Empty {};
+};
+class Stage1 { texture="bare_nohq.paa"; };
+`
+
+	type result struct {
+		name, base, container string
+	}
+	var got []result
+	var offsets []int64
+	for rec, err := range DecodeStream(strings.NewReader(input), nil) {
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		got = append(got, result{rec.Name, rec.Base, rec.Container})
+		offsets = append(offsets, rec.Offset)
+	}
+
+	want := []result{
+		{"Base", "", "CfgMaterials"},
+		{"Derived", "Base", "CfgMaterials"},
+		{"Inner", "", "Nested"},
+		{"Empty", "", "CfgMaterials"},
+		{"", "", ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("record %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	if want := int64(strings.Index(input, "class Derived")); offsets[1] != want {
+		t.Fatalf("Derived offset %d, want %d", offsets[1], want)
+	}
+}
+
+func TestDecodeStreamContainerDeclarations(t *testing.T) {
+	input := `class CfgMaterials
+{
+	class Base;
+	delete Old;
+	class X: Base
+	{
+		specularPower=40;
+	};
+};
+`
+
+	var names []string
+	for rec, err := range DecodeStream(strings.NewReader(input), nil) {
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		names = append(names, rec.Name+":"+rec.Base)
+	}
+	if len(names) != 1 || names[0] != "X:Base" {
+		t.Fatalf("unexpected records: %v", names)
+	}
+}
+
+func TestDecodeStreamStopsEarly(t *testing.T) {
+	input := strings.Repeat("ambient[]={1,1,1,1};\n", 3) + "ambient[]={"
+	count := 0
+	for _, err := range DecodeStream(strings.NewReader(input), nil) {
+		if err != nil {
+			t.Fatalf("unexpected error before break: %v", err)
+		}
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Fatalf("expected break after 2 materials, got %d", count)
+	}
+
+	var last error
+	for _, err := range DecodeStream(strings.NewReader(input), nil) {
+		last = err
+	}
+	if !errors.Is(last, ErrParse) && !errors.Is(last, ErrLex) {
+		t.Fatalf("expected trailing parse error, got %v", last)
+	}
+}
+
+// equalMaterialText compares materials by formatted output.
+func equalMaterialText(t *testing.T, a, b *Material) bool {
+	t.Helper()
+	ao, err := Format(a, nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	bo, err := Format(b, nil)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+
+	return string(ao) == string(bo)
+}
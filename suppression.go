@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+const (
+	// suppressionDirectivePrefix starts next-statement suppression comments.
+	suppressionDirectivePrefix = "rvmat-ignore"
+
+	// suppressionFileDirectivePrefix starts file-wide suppression comments.
+	suppressionFileDirectivePrefix = "rvmat-ignore-file"
+
+	// suppressionSource marks inline suppressions in lintkit audit data.
+	suppressionSource = "inline"
+)
+
+// textureValidationCodes lists codes produced only by texture validation.
+var textureValidationCodes = map[lint.Code]struct{}{
+	CodeValidateProceduralTextureParseFailed:             {},
+	CodeValidateUnknownProceduralFunction:                {},
+	CodeValidateUnknownProceduralTextureFormat:           {},
+	CodeValidateInvalidProceduralTextureHeaderDimensions: {},
+	CodeValidateUnexpectedProceduralArgumentCount:        {},
+	CodeValidateInvalidProceduralNumericArguments:        {},
+	CodeValidateUnknownTextureTag:                        {},
+}
+
+// suppressionDirective stores one parsed rvmat-ignore comment.
+type suppressionDirective struct {
+	reason string     // Optional reason after "--"
+	codes  []string   // Code or rule ID tokens; empty means all rules
+	line   int        // Directive line
+	col    int        // Directive column
+	target sourceSpan // Statement following next-line directive
+	file   bool       // File-wide scope
+}
+
+// parseSuppressionDirective parses one comment body into suppression directive.
+//
+// Supported forms:
+//
+//	rvmat-ignore RVMAT2019
+//	rvmat-ignore RVMAT2011, RVMAT2018 -- legacy stage layout
+//	rvmat-ignore-file RVMAT2011
+func parseSuppressionDirective(text string, line, col int) (suppressionDirective, bool) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSpace(strings.TrimLeft(text, "*/"))
+
+	directive := suppressionDirective{line: line, col: col}
+	var rest string
+	switch {
+	case hasDirectivePrefix(text, suppressionFileDirectivePrefix):
+		directive.file = true
+		rest = text[len(suppressionFileDirectivePrefix):]
+	case hasDirectivePrefix(text, suppressionDirectivePrefix):
+		rest = text[len(suppressionDirectivePrefix):]
+	default:
+		return suppressionDirective{}, false
+	}
+
+	if before, after, ok := strings.Cut(rest, "--"); ok {
+		rest = before
+		directive.reason = strings.TrimSpace(after)
+	}
+
+	directive.codes = strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	return directive, true
+}
+
+// hasDirectivePrefix reports whether text starts with directive word.
+func hasDirectivePrefix(text, prefix string) bool {
+	if !strings.HasPrefix(text, prefix) {
+		return false
+	}
+
+	if len(text) == len(prefix) {
+		return true
+	}
+
+	switch text[len(prefix)] {
+	case ' ', '\t', ',':
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveSuppressionTargets binds next-line directives to following statements.
+//
+// Target is the outermost statement starting on the first statement line
+// after the directive.
+func resolveSuppressionTargets(directives []suppressionDirective, spans []sourceSpan) {
+	for i := range directives {
+		directive := &directives[i]
+		if directive.file {
+			continue
+		}
+
+		var target sourceSpan
+		for _, span := range spans {
+			if span.startLine <= directive.line {
+				continue
+			}
+
+			switch {
+			case target.isZero(), span.startLine < target.startLine:
+				target = span
+			case span.startLine == target.startLine && span.endLine > target.endLine:
+				target = span
+			}
+		}
+
+		directive.target = target
+	}
+}
+
+// matchRule returns index of matching code token, 0 for catch-all, or -1.
+func (d suppressionDirective) matchRule(publicCode, ruleID string) int {
+	if len(d.codes) == 0 {
+		return 0
+	}
+
+	for i, token := range d.codes {
+		if publicCode != "" && strings.EqualFold(token, publicCode) {
+			return i
+		}
+		if ruleID == "" {
+			continue
+		}
+		if strings.EqualFold(token, ruleID) {
+			return i
+		}
+		if code, ok := lint.ParsePublicCode(token); ok && LintRuleID(code) == ruleID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// coversLine reports whether directive scope includes source line.
+func (d suppressionDirective) coversLine(line int) bool {
+	if d.file {
+		return true
+	}
+
+	return d.target.contains(line)
+}
+
+// scope returns lintkit suppression scope for directive.
+func (d suppressionDirective) scope() lint.SuppressionScope {
+	switch {
+	case d.file:
+		return lint.SuppressionScopeFile
+	case d.target.endLine > d.target.startLine:
+		return lint.SuppressionScopeBlock
+	default:
+		return lint.SuppressionScopeLine
+	}
+}
+
+// applyInlineSuppressions drops suppressed diagnostics and reports unused directives.
+//
+// When textureChecks is false, unused tokens for texture-only codes are not
+// reported because the producing checks did not run.
+func applyInlineSuppressions(
+	m *Material,
+	diagnostics []lint.Diagnostic,
+	textureChecks bool,
+) []lint.Diagnostic {
+	if m == nil || m.source == nil || len(m.source.suppressions) == 0 {
+		return diagnostics
+	}
+
+	directives := m.source.suppressions
+	used := make([][]bool, len(directives))
+	for i := range directives {
+		used[i] = make([]bool, max(len(directives[i].codes), 1))
+	}
+
+	out := diagnostics[:0]
+	for _, diagnostic := range diagnostics {
+		suppressed := false
+		for i, directive := range directives {
+			index := directive.matchRule(diagnostic.Code, diagnostic.RuleID)
+			if index < 0 || !directive.coversLine(diagnostic.Start.Line) {
+				continue
+			}
+
+			used[i][index] = true
+			suppressed = true
+			break
+		}
+
+		if !suppressed {
+			out = append(out, diagnostic)
+		}
+	}
+
+	for i, directive := range directives {
+		for index, isUsed := range used[i] {
+			if isUsed {
+				continue
+			}
+
+			path := ""
+			if len(directive.codes) > 0 {
+				path = directive.codes[index]
+				code, ok := lint.ParsePublicCode(path)
+				if _, textureOnly := textureValidationCodes[code]; ok && textureOnly && !textureChecks {
+					continue
+				}
+			}
+
+			out = append(out, withSourceSpan(warningDiagnostic(
+				CodeValidateUnusedSuppression,
+				"unused rvmat-ignore suppression",
+				path,
+			), sourceSpan{
+				startLine: directive.line,
+				startCol:  directive.col,
+				endLine:   directive.line,
+				endCol:    directive.col,
+			}))
+		}
+	}
+
+	return out
+}
+
+// LintSuppressions returns inline rvmat-ignore directives as lintkit suppression set.
+//
+// Use it as linting.RunOptions.Suppressions so rule runners honour the same
+// comments as Validate. Materials not produced by the parser have no directives.
+func (m *Material) LintSuppressions() lint.SuppressionSet {
+	return lint.SuppressionDecisionFunc(func(
+		ruleID string,
+		_ string,
+		start lint.Position,
+		_ lint.Position,
+	) lint.SuppressionDecision {
+		if m == nil || m.source == nil {
+			return lint.SuppressionDecision{}
+		}
+
+		for _, directive := range m.source.suppressions {
+			if directive.matchRule("", ruleID) < 0 || !directive.coversLine(start.Line) {
+				continue
+			}
+
+			return lint.SuppressionDecision{
+				Suppressed: true,
+				Scope:      directive.scope(),
+				Reason:     directive.reason,
+				Source:     suppressionSource,
+			}
+		}
+
+		return lint.SuppressionDecision{}
+	})
+}
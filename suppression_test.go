@@ -0,0 +1,184 @@
+package rvmat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/woozymasta/lintkit/lint"
+	"github.com/woozymasta/lintkit/linting"
+)
+
+const suppressionTestMaterial = `// rvmat-ignore-file RVMAT2011
+// rvmat-ignore RVMAT2019 -- overbright legacy Multi ambient
+ambient[]={4,4,4};
+diffuse[]={1,1,1,1};
+PixelShaderID="Super";
+VertexShaderID="Super";
+class StageX
+{
+	texture="#(argb,8,8,3)color(1,1,1,1)";
+	uvSource="tex";
+	/* rvmat-ignore RVMAT2021 */
+	class uvTransform
+	{
+		aside[]={1,0};
+		up[]={0,1,0};
+		dir[]={0,0,0};
+		pos[]={0,0,0};
+	};
+};
+// rvmat-ignore RVMAT2018
+class Stage2
+{
+	texture="#(argb,8,8,3)color(1,1,1,1)";
+	uvSource="tex";
+	// rvmat-ignore RVMAT2022
+	class uvTransform
+	{
+		aside[]={1,0,0};
+		up[]={0,1,0};
+		dir[]={0,0,0};
+		pos[]={0,0,0};
+	};
+};
+`
+
+func TestValidateInlineSuppressions(t *testing.T) {
+	mat, err := Parse([]byte(suppressionTestMaterial), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	issues := Validate(mat, nil)
+	codes := make(map[string][]lint.Diagnostic, len(issues))
+	for _, issue := range issues {
+		codes[issue.Code] = append(codes[issue.Code], issue)
+	}
+
+	for _, code := range []lint.Code{
+		CodeValidateUnknownStageName,
+		CodeValidateColorComponentCount,
+		CodeValidateUVTransformVectorComponentCount,
+	} {
+		if got := codes[mustRuleCode(t, code)]; len(got) != 0 {
+			t.Fatalf("expected %d to be suppressed: %v", code, got)
+		}
+	}
+
+	unused := codes[mustRuleCode(t, CodeValidateUnusedSuppression)]
+	if len(unused) != 1 || unused[0].Path != "RVMAT2018" || unused[0].Start.Line != 20 {
+		t.Fatalf("unexpected unused suppressions: %+v", unused)
+	}
+
+	// Texture-only codes are reported as unused only when texture checks ran.
+	issues = ValidateWithTextureOptions(mat, nil, &TextureValidateOptions{})
+	unusedCount := 0
+	for _, issue := range issues {
+		if issue.Code == mustRuleCode(t, CodeValidateUnusedSuppression) {
+			unusedCount++
+		}
+	}
+	if unusedCount != 2 {
+		t.Fatalf("unused suppressions with texture checks=%d, want 2", unusedCount)
+	}
+}
+
+func TestValidateDiagnosticPositions(t *testing.T) {
+	mat, err := Parse([]byte(suppressionTestMaterial), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	mat.source.suppressions = nil
+	for _, issue := range Validate(mat, nil) {
+		if issue.Code != mustRuleCode(t, CodeValidateColorComponentCount) {
+			continue
+		}
+		if issue.Start.Line != 3 || issue.End.Line != 3 {
+			t.Fatalf("unexpected color diagnostic position: %+v", issue)
+		}
+
+		return
+	}
+
+	t.Fatal("expected color component diagnostic")
+}
+
+func TestParseWithoutSuppressionsKeepsSourceInfo(t *testing.T) {
+	mat, err := Parse([]byte("// plain comment\nPixelShaderID=\"Super\";\nclass Stage1 { texture=\"a.paa\"; };\n"), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if span := materialKeySpan(mat, "PixelShaderID"); span.startLine != 2 || mat.Stages[0].source == nil {
+		t.Fatalf("expected source spans without directives, got %+v", span)
+	}
+}
+
+func TestParseSuppressionDirective(t *testing.T) {
+	tests := []struct {
+		text   string
+		codes  []string
+		reason string
+		ok     bool
+		file   bool
+	}{
+		{text: " rvmat-ignore RVMAT2019", codes: []string{"RVMAT2019"}, ok: true},
+		{text: "rvmat-ignore RVMAT2011,RVMAT2018 -- legacy", codes: []string{"RVMAT2011", "RVMAT2018"}, reason: "legacy", ok: true},
+		{text: "rvmat-ignore-file RVMAT2011", codes: []string{"RVMAT2011"}, ok: true, file: true},
+		{text: "rvmat-ignore", ok: true},
+		{text: "rvmat-ignored RVMAT2011"},
+		{text: "see rvmat-ignore RVMAT2011"},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseSuppressionDirective(tt.text, 1, 1)
+		if ok != tt.ok || got.file != tt.file || got.reason != tt.reason || len(got.codes) != len(tt.codes) {
+			t.Fatalf("parseSuppressionDirective(%q) = %+v, %v", tt.text, got, ok)
+		}
+		for i := range tt.codes {
+			if got.codes[i] != tt.codes[i] {
+				t.Fatalf("parseSuppressionDirective(%q) codes=%v, want %v", tt.text, got.codes, tt.codes)
+			}
+		}
+	}
+}
+
+func TestLintSuppressionsWithEngine(t *testing.T) {
+	mat, err := Parse([]byte(suppressionTestMaterial), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	engine := linting.NewEngine()
+	if err := RegisterLintRules(engine); err != nil {
+		t.Fatalf("RegisterLintRules() error: %v", err)
+	}
+
+	mat.source.suppressions = mat.source.suppressions[:1]
+	runContext := lint.RunContext{TargetPath: "material.rvmat"}
+	AttachLintDiagnostics(&runContext, validateMaterial(mat, nil))
+
+	countUnknownStage := func(options *linting.RunOptions) int {
+		result, err := engine.Run(context.Background(), runContext, options)
+		if err != nil {
+			t.Fatalf("engine.Run() error: %v", err)
+		}
+
+		count := 0
+		for _, diagnostic := range result.Diagnostics {
+			if diagnostic.RuleID == LintRuleID(CodeValidateUnknownStageName) {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	if got := countUnknownStage(&linting.RunOptions{}); got != 1 {
+		t.Fatalf("unknown stage diagnostics without suppressions=%d, want 1", got)
+	}
+	if got := countUnknownStage(&linting.RunOptions{Suppressions: mat.LintSuppressions()}); got != 0 {
+		t.Fatalf("unknown stage diagnostics with suppressions=%d, want 0", got)
+	}
+}
@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+// Surface represents a parsed .bisurf surface definition.
+type Surface struct {
+	Density             *float64 `json:"density,omitempty" yaml:"density,omitempty"`                           // Density in kg/m^3
+	Rough               *float64 `json:"rough,omitempty" yaml:"rough,omitempty"`                               // Roughness (0..1)
+	Dust                *float64 `json:"dust,omitempty" yaml:"dust,omitempty"`                                 // Dust amount (0..1)
+	BulletPenetrability *float64 `json:"bullet_penetrability,omitempty" yaml:"bullet_penetrability,omitempty"` // Bullet penetrability
+	Thickness           *float64 `json:"thickness,omitempty" yaml:"thickness,omitempty"`                       // Thickness
+	Deflection          *float64 `json:"deflection,omitempty" yaml:"deflection,omitempty"`                     // Bullet deflection
+	Transparency        *float64 `json:"transparency,omitempty" yaml:"transparency,omitempty"`                 // Transparency (0..1)
+	SoundEnviron        string   `json:"sound_environ,omitempty" yaml:"sound_environ,omitempty"`               // Footstep sound environment
+	SoundHit            string   `json:"sound_hit,omitempty" yaml:"sound_hit,omitempty"`                       // Hit sound set
+	Impact              string   `json:"impact,omitempty" yaml:"impact,omitempty"`                             // Impact effect class
+	Character           string   `json:"character,omitempty" yaml:"character,omitempty"`                       // Surface character (clutter)
+	extras              []node   // Extra nodes
+}
+
+// softGroundSurfaceHints lists lower-case sound and impact tokens of soft ground.
+var softGroundSurfaceHints = map[string]struct{}{
+	"grass": {}, "dirt": {}, "sand": {}, "mud": {}, "soil": {}, "forest": {},
+	"earth": {}, "ground": {}, "snow": {}, "gravel": {}, "soft": {},
+}
+
+// rigidSurfaceMaterials lists generator base materials that cannot be soft ground.
+var rigidSurfaceMaterials = map[BaseMaterial]struct{}{
+	BaseMaterialGlass:    {},
+	BaseMaterialSteel:    {},
+	BaseMaterialRust:     {},
+	BaseMaterialConcrete: {},
+	BaseMaterialStone:    {},
+}
+
+// ParseSurface parses a .bisurf surface definition from bytes.
+func ParseSurface(data []byte, opt *ParseOptions) (*Surface, error) {
+	return DecodeSurface(bytes.NewReader(data), opt)
+}
+
+// DecodeSurface parses a .bisurf surface definition from reader.
+func DecodeSurface(r io.Reader, opt *ParseOptions) (*Surface, error) {
+	p := newParser(r, opt.normalize())
+	s := &Surface{}
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Type == tokEOF {
+			return s, nil
+		}
+
+		if tok.Type == tokClass {
+			n, err := p.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			s.extras = append(s.extras, n)
+			continue
+		}
+
+		if err := p.parseSurfaceAssign(s); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// DecodeSurfaceFile parses a .bisurf surface definition from a file.
+func DecodeSurfaceFile(path string, opt *ParseOptions) (*Surface, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSurface(b, opt)
+}
+
+// parseSurfaceAssign parses one top-level surface assignment.
+func (p *parser) parseSurfaceAssign(s *Surface) error {
+	tok, err := p.peek()
+	if err != nil {
+		return err
+	}
+
+	ci := !p.opt.DisableCaseInsensitive
+	var num **float64
+	var str *string
+	switch {
+	case matchKey(tok.Lit, "density", ci):
+		num = &s.Density
+	case matchKey(tok.Lit, "rough", ci):
+		num = &s.Rough
+	case matchKey(tok.Lit, "dust", ci):
+		num = &s.Dust
+	case matchKey(tok.Lit, "bulletpenetrability", ci):
+		num = &s.BulletPenetrability
+	case matchKey(tok.Lit, "thickness", ci):
+		num = &s.Thickness
+	case matchKey(tok.Lit, "deflection", ci):
+		num = &s.Deflection
+	case matchKey(tok.Lit, "transparency", ci):
+		num = &s.Transparency
+	case matchKey(tok.Lit, "soundenviron", ci):
+		str = &s.SoundEnviron
+	case matchKey(tok.Lit, "soundhit", ci):
+		str = &s.SoundHit
+	case matchKey(tok.Lit, "impact", ci):
+		str = &s.Impact
+	case matchKey(tok.Lit, "character", ci):
+		str = &s.Character
+	default:
+		n, err := p.parseAssign()
+		if err != nil {
+			return err
+		}
+		s.extras = append(s.extras, n)
+		return nil
+	}
+
+	_, _ = p.next()
+	if _, err := p.expect(tokEqual); err != nil {
+		return err
+	}
+	if num != nil {
+		v, _, err := p.parseNumberValue()
+		if err != nil {
+			return err
+		}
+		*num = &v
+	} else {
+		v, err := p.parseStringValue()
+		if err != nil {
+			return err
+		}
+		*str = v
+	}
+
+	return p.expectSemicolon()
+}
+
+// SurfaceInfo returns surfaceInfo path of material, or empty string.
+func (m *Material) SurfaceInfo() string {
+	for _, n := range m.extras {
+		an, ok := n.(assignNode)
+		if ok && !an.IsArray && asciiEqualFold(an.Name, "surfaceInfo") &&
+			(an.Value.Kind == valueString || an.Value.Kind == valueIdent) {
+			return an.Value.Str
+		}
+	}
+
+	return ""
+}
+
+// ValidateSurface checks surface values for well-formed ranges.
+func ValidateSurface(s *Surface) []lint.Diagnostic {
+	var out []lint.Diagnostic
+	check := func(name string, v *float64, unit bool) {
+		switch {
+		case v == nil:
+		case math.IsNaN(*v) || *v < 0:
+			out = append(out, warningDiagnostic(
+				CodeValidateInvalidSurfaceFile,
+				fmt.Sprintf("surface %s %s is negative", name, formatPrettyFloat(*v)),
+				name,
+			))
+		case unit && *v > 1:
+			out = append(out, warningDiagnostic(
+				CodeValidateInvalidSurfaceFile,
+				fmt.Sprintf("surface %s %s exceeds 1", name, formatPrettyFloat(*v)),
+				name,
+			))
+		}
+	}
+
+	if s.Density == nil {
+		out = append(out, warningDiagnostic(
+			CodeValidateInvalidSurfaceFile,
+			"surface density missing",
+			"density",
+		))
+	}
+	check("density", s.Density, false)
+	check("rough", s.Rough, true)
+	check("dust", s.Dust, true)
+	check("transparency", s.Transparency, true)
+	check("thickness", s.Thickness, false)
+	check("bulletPenetrability", s.BulletPenetrability, false)
+
+	return out
+}
+
+// validateSurfaceInfo checks surfaceInfo file and its pairing with material.
+func validateSurfaceInfo(m *Material, vopt ValidateOptions) []lint.Diagnostic {
+	raw := m.SurfaceInfo()
+	if raw == "" || vopt.TexturePathMode == TexturePathModeIgnore || shouldExcludePath(raw, vopt.ExcludePaths) {
+		return nil
+	}
+	if vopt.TexturePathMode == TexturePathModeTrust && hasTrustedGameRootPrefix(raw, vopt.TrustedPrefixes) {
+		return nil
+	}
+
+	span := materialKeySpan(m, "surfaceInfo")
+	resolver := PathResolver{GameRoot: vopt.GameRoot, FileSystem: vopt.FileSystem}
+	p := resolver.ResolvePath(raw)
+	data, err := fileSystemOrOS(vopt.FileSystem).ReadFile(p)
+	if err != nil {
+		return []lint.Diagnostic{withSourceSpan(warningDiagnostic(
+			CodeValidateSurfaceFileNotFound,
+			"surface file not found",
+			p,
+		), span)}
+	}
+
+	s, err := ParseSurface(data, nil)
+	if err != nil {
+		return []lint.Diagnostic{withSourceSpan(warningDiagnostic(
+			CodeValidateInvalidSurfaceFile,
+			"surface file is malformed: "+err.Error(),
+			p,
+		), span)}
+	}
+
+	out := withSourceSpans(ValidateSurface(s), span)
+	for i := range out {
+		out[i].Path = p
+	}
+
+	material, token := materialSurfaceKind(m)
+	if _, rigid := rigidSurfaceMaterials[material]; rigid && s.isSoftGround() {
+		out = append(out, withSourceSpan(warningDiagnostic(
+			CodeValidateSurfaceMaterialMismatch,
+			fmt.Sprintf("%s material uses soft-ground surface", token),
+			p,
+		), span))
+	}
+
+	return out
+}
+
+// materialSurfaceKind infers base material of rvmat from shader and textures.
+func materialSurfaceKind(m *Material) (BaseMaterial, string) {
+	if strings.Contains(strings.ToLower(m.PixelShaderID), "glass") {
+		return BaseMaterialGlass, "glass"
+	}
+
+	for _, st := range m.Stages {
+		if st.Texture.Raw == "" || st.Texture.IsProcedural() {
+			continue
+		}
+		if material, token := inferBaseMaterialFromName(st.Texture.Raw); token != "" {
+			return material, token
+		}
+	}
+
+	return BaseMaterialDefault, ""
+}
+
+// isSoftGround reports surface with soft-ground sound or impact.
+func (s *Surface) isSoftGround() bool {
+	for _, field := range []string{s.SoundEnviron, s.Impact, s.Character} {
+		tokens := strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+		for _, token := range tokens {
+			if _, ok := softGroundSurfaceHints[token]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
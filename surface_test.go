@@ -0,0 +1,107 @@
+package rvmat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/lintkit/lint"
+)
+
+func TestParseSurface(t *testing.T) {
+	input := `density=2500;
+rough=0.02;
+dust=0.05;
+bulletPenetrability=200;
+thickness=10;
+soundEnviron="glass";
+soundHit="hard_ground";
+character="Empty";
+impact="Hit_Glass";
+isLiquid="false";
+`
+	s, err := ParseSurface([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if s.Density == nil || *s.Density != 2500 || s.Rough == nil || *s.Rough != 0.02 {
+		t.Fatalf("unexpected numbers: %+v", s)
+	}
+	if s.SoundEnviron != "glass" || s.Impact != "Hit_Glass" || len(s.extras) != 1 {
+		t.Fatalf("unexpected strings or extras: %+v", s)
+	}
+	if diags := ValidateSurface(s); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+
+	bad, err := ParseSurface([]byte("rough=1.5;\ndust=-1;\n"), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if diags := ValidateSurface(bad); len(diags) != 3 {
+		t.Fatalf("expected density, rough, and dust diagnostics, got %+v", diags)
+	}
+}
+
+func TestValidateSurfaceInfo(t *testing.T) {
+	fsys := NewMemFileSystem()
+	fsys.AddFile(`P:\mod\data\grass.bisurf`, []byte("density=1200;\nsoundEnviron=\"grass\";\nimpact=\"Hit_Grass\";\n"))
+	fsys.AddFile(`P:\mod\data\broken.bisurf`, []byte("density=;\n"))
+	opt := &ValidateOptions{GameRoot: `P:\`, TexturePathMode: TexturePathModeStrict, FileSystem: fsys}
+
+	tests := []struct {
+		name    string
+		input   string
+		code    lint.Code
+		message string
+	}{
+		{
+			name:    "missing",
+			input:   `surfaceInfo="mod\data\metal.bisurf";`,
+			code:    CodeValidateSurfaceFileNotFound,
+			message: "surface file not found",
+		},
+		{
+			name:    "malformed",
+			input:   `surfaceInfo="mod\data\broken.bisurf";`,
+			code:    CodeValidateInvalidSurfaceFile,
+			message: "surface file is malformed",
+		},
+		{
+			name:    "glass on grass",
+			input:   "PixelShaderID=\"Glass\";\nsurfaceInfo=\"mod\\data\\grass.bisurf\";",
+			code:    CodeValidateSurfaceMaterialMismatch,
+			message: "glass material uses soft-ground surface",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.input), nil)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			var found bool
+			for _, d := range Validate(m, opt) {
+				if d.Code == mustRuleCode(t, tt.code) && strings.HasPrefix(d.Message, tt.message) {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("expected %q diagnostic, got %+v", tt.message, Validate(m, opt))
+			}
+		})
+	}
+
+	// Soft-ground surface is fine for non-rigid material.
+	m, err := Parse([]byte(`surfaceInfo="mod\data\grass.bisurf";`), nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if m.SurfaceInfo() != `mod\data\grass.bisurf` {
+		t.Fatalf("unexpected SurfaceInfo %q", m.SurfaceInfo())
+	}
+	if diags := Validate(m, opt); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+}
@@ -6,10 +6,13 @@ package rvmat
 
 import (
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/woozymasta/lintkit/lint"
 )
@@ -47,12 +50,16 @@ func (t TextureRef) Validate(opt *TextureValidateOptions) []lint.Diagnostic {
 // - Format/Width/Height/Mip come from the header "#(argb,8,8,3)".
 // - Func/Args come from "color(...)".
 type ProceduralTexture struct {
-	// Color is procedural texture color(r,g,b,a[,tag]).
+	// Color is procedural texture color(r,g,b,a[,tag]) and point(r,g,b,a[,tag]).
 	Color *ProceduralColor `json:"color,omitempty" yaml:"color,omitempty"`
 	// Fresnel is procedural texture fresnel(a,b) and fresnelGlass(a,b?).
 	Fresnel *ProceduralFresnel `json:"fresnel,omitempty" yaml:"fresnel,omitempty"`
-	// Irradiance is procedural texture irradiance(x).
+	// Irradiance is procedural texture irradiance(x) and waterIrradiance(x).
 	Irradiance *ProceduralIrradiance `json:"irradiance,omitempty" yaml:"irradiance,omitempty"`
+	// PerlinNoise is procedural texture perlinNoise(xScale,yScale,min,max).
+	PerlinNoise *ProceduralPerlinNoise `json:"perlin_noise,omitempty" yaml:"perlin_noise,omitempty"`
+	// TreeCrown is procedural texture treeCrown(density) and treeCrownAmb(density).
+	TreeCrown *ProceduralTreeCrown `json:"tree_crown,omitempty" yaml:"tree_crown,omitempty"`
 
 	Format string   `json:"format,omitempty" yaml:"format,omitempty"` // Procedural texture format
 	Func   string   `json:"func,omitempty" yaml:"func,omitempty"`     // Procedural function name
@@ -78,11 +85,24 @@ type ProceduralFresnel struct {
 	B float64 `json:"b,omitempty" yaml:"b,omitempty"` // Fresnel parameter b
 }
 
-// ProceduralIrradiance is procedural texture irradiance(x).
+// ProceduralIrradiance is procedural texture irradiance(x) and waterIrradiance(x).
 type ProceduralIrradiance struct {
 	Value float64 `json:"value,omitempty" yaml:"value,omitempty"` // Irradiance value
 }
 
+// ProceduralPerlinNoise is procedural texture perlinNoise(xScale,yScale,min,max).
+type ProceduralPerlinNoise struct {
+	XScale float64 `json:"x_scale,omitempty" yaml:"x_scale,omitempty"` // Horizontal noise period in texels
+	YScale float64 `json:"y_scale,omitempty" yaml:"y_scale,omitempty"` // Vertical noise period in texels
+	Min    float64 `json:"min,omitempty" yaml:"min,omitempty"`         // Output value at noise minimum
+	Max    float64 `json:"max,omitempty" yaml:"max,omitempty"`         // Output value at noise maximum
+}
+
+// ProceduralTreeCrown is procedural texture treeCrown(density) and treeCrownAmb(density).
+type ProceduralTreeCrown struct {
+	Density float64 `json:"density,omitempty" yaml:"density,omitempty"` // Crown foliage density
+}
+
 // NewProcedural creates a procedural texture reference from parts.
 // Args can be strings or numbers; numeric args are formatted consistently.
 func NewProcedural(format string, width, height, mip int, fn string, args ...any) TextureRef {
@@ -117,6 +137,35 @@ func NewProceduralIrradiance(format string, width, height, mip int, value float6
 	return NewProcedural(format, width, height, mip, "irradiance", value)
 }
 
+// NewProceduralWaterIrradiance creates a waterIrradiance(x) procedural texture reference.
+func NewProceduralWaterIrradiance(format string, width, height, mip int, value float64) TextureRef {
+	return NewProcedural(format, width, height, mip, "waterIrradiance", value)
+}
+
+// NewProceduralPerlinNoise creates a perlinNoise(xScale,yScale,min,max) procedural texture reference.
+func NewProceduralPerlinNoise(format string, width, height, mip int, xScale, yScale, minValue, maxValue float64) TextureRef {
+	return NewProcedural(format, width, height, mip, "perlinNoise", xScale, yScale, minValue, maxValue)
+}
+
+// NewProceduralTreeCrown creates a treeCrown(density) procedural texture reference.
+func NewProceduralTreeCrown(format string, width, height, mip int, density float64) TextureRef {
+	return NewProcedural(format, width, height, mip, "treeCrown", density)
+}
+
+// NewProceduralTreeCrownAmb creates a treeCrownAmb(density) procedural texture reference.
+func NewProceduralTreeCrownAmb(format string, width, height, mip int, density float64) TextureRef {
+	return NewProcedural(format, width, height, mip, "treeCrownAmb", density)
+}
+
+// NewProceduralPoint creates a point(r,g,b,a[,tag]) procedural texture reference.
+func NewProceduralPoint(format string, width, height, mip int, r, g, b, a float64, tag string) TextureRef {
+	args := []any{r, g, b, a}
+	if tag != "" {
+		args = append(args, tag)
+	}
+	return NewProcedural(format, width, height, mip, "point", args...)
+}
+
 // ParseTextureRef parses a texture reference string.
 func ParseTextureRef(raw string) TextureRef {
 	raw = NormalizeTextureRaw(raw)
@@ -147,6 +196,9 @@ func (t TextureRef) IsPath() bool { return t.Kind == TextureKindPath }
 
 // PathResolver resolves texture paths relative to GameRoot.
 type PathResolver struct {
+	// FileSystem is used by Stat and Exists; nil value uses OS filesystem.
+	FileSystem FileSystem
+	// GameRoot is prepended to relative texture paths.
 	GameRoot string
 }
 
@@ -178,6 +230,22 @@ func (r PathResolver) ResolvePath(raw string) string {
 	return filepath.Clean(filepath.Join(r.GameRoot, norm))
 }
 
+// Stat resolves raw path against GameRoot and returns its file info.
+func (r PathResolver) Stat(raw string) (fs.FileInfo, error) {
+	resolved := r.ResolvePath(raw)
+	if resolved == "" {
+		return nil, &fs.PathError{Op: "stat", Path: raw, Err: fs.ErrNotExist}
+	}
+
+	return fileSystemOrOS(r.FileSystem).Stat(resolved)
+}
+
+// Exists reports whether raw path resolves to existing regular file.
+func (r PathResolver) Exists(raw string) bool {
+	info, err := r.Stat(raw)
+	return err == nil && !info.IsDir()
+}
+
 // hasVolume checks if the path has a volume.
 func hasVolume(p string) bool {
 	if len(p) >= 2 && p[1] == ':' {
@@ -287,7 +355,7 @@ func NormalizeGameTexturePath(raw string) string {
 		return s
 	}
 
-	s = strings.ToLower(s)
+	s = lowerKeepInvalid(s)
 	s = strings.ReplaceAll(s, "/", `\`)
 	s = trimWindowsDrivePrefix(s)
 	s = strings.TrimLeft(s, `\`)
@@ -298,6 +366,28 @@ func NormalizeGameTexturePath(raw string) string {
 	return s
 }
 
+// lowerKeepInvalid lower-cases valid UTF-8 runes and keeps invalid bytes
+// (legacy code-page paths) unchanged, unlike strings.ToLower.
+func lowerKeepInvalid(s string) string {
+	if utf8.ValidString(s) {
+		return strings.ToLower(s)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && size == 1 {
+			b.WriteByte(s[0])
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		s = s[size:]
+	}
+
+	return b.String()
+}
+
 // trimWindowsDrivePrefix removes a leading drive prefix like "P:".
 func trimWindowsDrivePrefix(path string) string {
 	if len(path) < 2 || path[1] != ':' {
@@ -361,6 +451,16 @@ func parseProceduralArgs(pt *ProceduralTexture) {
 		parseProceduralFresnel(pt)
 	case strings.EqualFold(pt.Func, "irradiance"):
 		parseProceduralIrradiance(pt)
+	case strings.EqualFold(pt.Func, "waterIrradiance"):
+		parseProceduralIrradiance(pt)
+	case strings.EqualFold(pt.Func, "perlinNoise"):
+		parseProceduralPerlinNoise(pt)
+	case strings.EqualFold(pt.Func, "treeCrown"):
+		parseProceduralTreeCrown(pt)
+	case strings.EqualFold(pt.Func, "treeCrownAmb"):
+		parseProceduralTreeCrown(pt)
+	case strings.EqualFold(pt.Func, "point"):
+		parseProceduralColor(pt)
 	}
 }
 
@@ -458,6 +558,43 @@ func parseProceduralIrradiance(pt *ProceduralTexture) {
 	pt.Irradiance = &ProceduralIrradiance{Value: v}
 }
 
+// parseProceduralPerlinNoise parses a perlinNoise(xScale,yScale,min,max) procedural texture.
+func parseProceduralPerlinNoise(pt *ProceduralTexture) {
+	if len(pt.Args) != 4 {
+		return
+	}
+
+	var values [4]float64
+	for i, arg := range pt.Args {
+		v, ok := parseFloatArg(arg)
+		if !ok {
+			return
+		}
+		values[i] = v
+	}
+
+	pt.PerlinNoise = &ProceduralPerlinNoise{
+		XScale: values[0],
+		YScale: values[1],
+		Min:    values[2],
+		Max:    values[3],
+	}
+}
+
+// parseProceduralTreeCrown parses a treeCrown(density) procedural texture.
+func parseProceduralTreeCrown(pt *ProceduralTexture) {
+	if len(pt.Args) != 1 {
+		return
+	}
+
+	v, ok := parseFloatArg(pt.Args[0])
+	if !ok {
+		return
+	}
+
+	pt.TreeCrown = &ProceduralTreeCrown{Density: v}
+}
+
 // parseFloatArg parses a string to a float64 value.
 func parseFloatArg(s string) (float64, bool) {
 	s = strings.TrimSpace(s)
@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 WoozyMasta
+// Source: github.com/woozymasta/rvmat
+
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// paaTypeDXT1 marks DXT1 (BC1) compressed PAA data.
+	paaTypeDXT1 uint16 = 0xFF01
+	// paaTypeDXT5 marks DXT5 (BC3) compressed PAA data.
+	paaTypeDXT5 uint16 = 0xFF05
+	// paaTypeARGB4444 marks 16-bit ARGB 4:4:4:4 PAA data.
+	paaTypeARGB4444 uint16 = 0x4444
+	// paaTypeARGB1555 marks 16-bit ARGB 1:5:5:5 PAA data.
+	paaTypeARGB1555 uint16 = 0x1555
+	// paaTypeARGB8888 marks 32-bit ARGB PAA data.
+	paaTypeARGB8888 uint16 = 0x8888
+	// paaTypeAI88 marks 16-bit alpha+intensity PAA data.
+	paaTypeAI88 uint16 = 0x8080
+
+	// paaLZOFlag marks LZO-compressed DXT mipmap in width field.
+	paaLZOFlag uint16 = 0x8000
+
+	// maxTextureImagePixels limits decoded texture size.
+	maxTextureImagePixels = 8192 * 8192
+)
+
+// DecodeTextureImage decodes texture data by file extension of name.
+//
+// Supported formats are PNG, TGA (uncompressed and RLE true-color or gray),
+// and PAA/PAX (DXT1, DXT5, ARGB8888, ARGB4444, ARGB1555, AI88; top mipmap).
+func DecodeTextureImage(name string, data []byte) (image.Image, error) {
+	switch strings.ToLower(path.Ext(strings.ReplaceAll(name, `\`, "/"))) {
+	case ".png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTextureImage, name, err)
+		}
+		return img, nil
+	case ".tga":
+		return decodeTGA(name, data)
+	case ".paa", ".pax":
+		return decodePAA(name, data)
+	default:
+		return nil, fmt.Errorf("%w: %s: unsupported extension", ErrInvalidTextureImage, name)
+	}
+}
+
+// LoadTextureImage reads and decodes texture at path from file system.
+//
+// Nil fsys uses OS filesystem.
+func LoadTextureImage(fsys FileSystem, name string) (image.Image, error) {
+	data, err := fileSystemOrOS(fsys).ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeTextureImage(name, data)
+}
+
+// loadTextureImageAnyExt loads texture at path or, when missing, its
+// siblings with known texture extensions (e.g. .png next to .paa reference).
+func loadTextureImageAnyExt(fsys FileSystem, name string) (image.Image, error) {
+	stem := strings.TrimSuffix(name, path.Ext(strings.ReplaceAll(name, `\`, "/")))
+	candidates := []string{name}
+	for _, ext := range textureExtensionsByPriority() {
+		candidates = append(candidates, stem+ext)
+	}
+
+	var firstErr error
+	for _, candidate := range candidates {
+		img, err := LoadTextureImage(fsys, candidate)
+		if err == nil {
+			return img, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// writeImagePNG encodes image as PNG and writes it, creating parent directories.
+func writeImagePNG(fsys WritableFileSystem, name string, img image.Image) error {
+	fsys = writableFileSystemOrOS(fsys)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(name); dir != "" && dir != "." {
+		if err := fsys.MkdirAll(dir, 0o750); err != nil {
+			return err
+		}
+	}
+
+	return fsys.WriteFile(name, buf.Bytes(), 0o600)
+}
+
+// decodeTGA decodes uncompressed or RLE TGA image.
+func decodeTGA(name string, data []byte) (image.Image, error) {
+	if len(data) < 18 {
+		return nil, fmt.Errorf("%w: %s: truncated tga header", ErrInvalidTextureImage, name)
+	}
+
+	idLen := int(data[0])
+	colorMapType := data[1]
+	imageType := data[2]
+	width := int(binary.LittleEndian.Uint16(data[12:]))
+	height := int(binary.LittleEndian.Uint16(data[14:]))
+	bpp := int(data[16])
+	descriptor := data[17]
+
+	rle := imageType == 10 || imageType == 11
+	gray := imageType == 3 || imageType == 11
+	switch {
+	case colorMapType != 0:
+		return nil, fmt.Errorf("%w: %s: color-mapped tga", ErrInvalidTextureImage, name)
+	case imageType != 2 && imageType != 3 && imageType != 10 && imageType != 11:
+		return nil, fmt.Errorf("%w: %s: tga image type %d", ErrInvalidTextureImage, name, imageType)
+	case gray && bpp != 8, !gray && bpp != 24 && bpp != 32:
+		return nil, fmt.Errorf("%w: %s: tga depth %d", ErrInvalidTextureImage, name, bpp)
+	case width <= 0 || height <= 0 || width*height > maxTextureImagePixels:
+		return nil, fmt.Errorf("%w: %s: tga size %dx%d", ErrInvalidTextureImage, name, width, height)
+	}
+
+	pixelSize := bpp / 8
+	src := data[18+idLen:]
+	pixels := make([]byte, 0, width*height*pixelSize)
+	if !rle {
+		if len(src) < cap(pixels) {
+			return nil, fmt.Errorf("%w: %s: truncated tga data", ErrInvalidTextureImage, name)
+		}
+		pixels = append(pixels, src[:cap(pixels)]...)
+	}
+	for pos := 0; rle && len(pixels) < cap(pixels); {
+		if pos >= len(src) {
+			return nil, fmt.Errorf("%w: %s: truncated tga rle data", ErrInvalidTextureImage, name)
+		}
+
+		header := src[pos]
+		count := int(header&0x7F) + 1
+		pos++
+		if header&0x80 != 0 {
+			if pos+pixelSize > len(src) {
+				return nil, fmt.Errorf("%w: %s: truncated tga rle data", ErrInvalidTextureImage, name)
+			}
+			for range count {
+				pixels = append(pixels, src[pos:pos+pixelSize]...)
+			}
+			pos += pixelSize
+			continue
+		}
+
+		if pos+count*pixelSize > len(src) {
+			return nil, fmt.Errorf("%w: %s: truncated tga rle data", ErrInvalidTextureImage, name)
+		}
+		pixels = append(pixels, src[pos:pos+count*pixelSize]...)
+		pos += count * pixelSize
+	}
+	pixels = pixels[:min(len(pixels), width*height*pixelSize)]
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	topDown := descriptor&0x20 != 0
+	for y := range height {
+		row := y
+		if !topDown {
+			row = height - 1 - y
+		}
+		for x := range width {
+			p := pixels[(row*width+x)*pixelSize:]
+			c := color.NRGBA{A: 255}
+			switch pixelSize {
+			case 1:
+				c.R, c.G, c.B = p[0], p[0], p[0]
+			case 3:
+				c.R, c.G, c.B = p[2], p[1], p[0]
+			case 4:
+				c.R, c.G, c.B, c.A = p[2], p[1], p[0], p[3]
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img, nil
+}
+
+// decodePAA decodes top mipmap of PAA/PAX texture.
+func decodePAA(name string, data []byte) (image.Image, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("%w: %s: truncated paa header", ErrInvalidTextureImage, name)
+	}
+
+	typ := binary.LittleEndian.Uint16(data)
+	pos := 2
+
+	// Skip TAGG blocks ("GGAT" + 4-byte name + uint32 length + payload).
+	for pos+12 <= len(data) && string(data[pos:pos+4]) == "GGAT" {
+		size := int(binary.LittleEndian.Uint32(data[pos+8:]))
+		pos += 12 + size
+	}
+
+	// Skip palette (uint16 count of RGB triplets).
+	if pos+2 > len(data) {
+		return nil, fmt.Errorf("%w: %s: truncated paa palette", ErrInvalidTextureImage, name)
+	}
+	pos += 2 + int(binary.LittleEndian.Uint16(data[pos:]))*3
+
+	if pos+7 > len(data) {
+		return nil, fmt.Errorf("%w: %s: missing paa mipmap", ErrInvalidTextureImage, name)
+	}
+	rawWidth := binary.LittleEndian.Uint16(data[pos:])
+	height := int(binary.LittleEndian.Uint16(data[pos+2:]))
+	size := int(data[pos+4]) | int(data[pos+5])<<8 | int(data[pos+6])<<16
+	pos += 7
+
+	width := int(rawWidth &^ paaLZOFlag)
+	if width <= 0 || height <= 0 || width*height > maxTextureImagePixels || pos+size > len(data) {
+		return nil, fmt.Errorf("%w: %s: invalid paa mipmap %dx%d", ErrInvalidTextureImage, name, width, height)
+	}
+	payload := data[pos : pos+size]
+
+	var expected int
+	switch typ {
+	case paaTypeDXT1:
+		expected = max(width/4, 1) * max(height/4, 1) * 8
+	case paaTypeDXT5:
+		expected = max(width/4, 1) * max(height/4, 1) * 16
+	case paaTypeARGB4444, paaTypeARGB1555, paaTypeAI88:
+		expected = width * height * 2
+	case paaTypeARGB8888:
+		expected = width * height * 4
+	default:
+		return nil, fmt.Errorf("%w: %s: paa type %04x", ErrInvalidTextureImage, name, typ)
+	}
+
+	var err error
+	switch {
+	case typ == paaTypeDXT1 || typ == paaTypeDXT5:
+		if rawWidth&paaLZOFlag != 0 {
+			payload, err = decompressLZO(payload, expected)
+		}
+	case size != expected:
+		payload, err = decompressLZSS(payload, expected)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTextureImage, name, err)
+	}
+	if len(payload) < expected {
+		return nil, fmt.Errorf("%w: %s: truncated paa mipmap data", ErrInvalidTextureImage, name)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	switch typ {
+	case paaTypeDXT1:
+		decodeDXT(img, payload, false)
+	case paaTypeDXT5:
+		decodeDXT(img, payload, true)
+	default:
+		decodePAAPixels(img, payload, typ)
+	}
+
+	return img, nil
+}
+
+// decodePAAPixels decodes uncompressed 16/32-bit PAA pixels.
+func decodePAAPixels(img *image.NRGBA, data []byte, typ uint16) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	for i := range width * height {
+		var c color.NRGBA
+		switch typ {
+		case paaTypeARGB8888:
+			p := data[i*4:]
+			c = color.NRGBA{R: p[2], G: p[1], B: p[0], A: p[3]}
+		case paaTypeARGB4444:
+			v := binary.LittleEndian.Uint16(data[i*2:])
+			c = color.NRGBA{
+				R: uint8(v>>8&0xF) * 17,
+				G: uint8(v>>4&0xF) * 17,
+				B: uint8(v&0xF) * 17,
+				A: uint8(v>>12) * 17,
+			}
+		case paaTypeARGB1555:
+			v := binary.LittleEndian.Uint16(data[i*2:])
+			c = color.NRGBA{
+				R: expand5(v >> 10),
+				G: expand5(v >> 5),
+				B: expand5(v),
+				A: uint8(v>>15) * 255,
+			}
+		case paaTypeAI88:
+			c = color.NRGBA{R: data[i*2], G: data[i*2], B: data[i*2], A: data[i*2+1]}
+		}
+		img.SetNRGBA(i%width, i/width, c)
+	}
+}
+
+// decodeDXT decodes DXT1 or DXT5 blocks into image.
+func decodeDXT(img *image.NRGBA, data []byte, dxt5 bool) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	blockSize := 8
+	if dxt5 {
+		blockSize = 16
+	}
+
+	blocksX := max(width/4, 1)
+	for by := range max(height/4, 1) {
+		for bx := range blocksX {
+			block := data[(by*blocksX+bx)*blockSize:]
+
+			alphas := [16]uint8{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255}
+			if dxt5 {
+				alphas = decodeDXT5Alpha(block[:8])
+				block = block[8:]
+			}
+
+			c0 := binary.LittleEndian.Uint16(block)
+			c1 := binary.LittleEndian.Uint16(block[2:])
+			var palette [4]color.NRGBA
+			palette[0] = rgb565(c0)
+			palette[1] = rgb565(c1)
+			if c0 > c1 || dxt5 {
+				palette[2] = lerpNRGBA(palette[0], palette[1], 1, 3)
+				palette[3] = lerpNRGBA(palette[0], palette[1], 2, 3)
+			} else {
+				palette[2] = lerpNRGBA(palette[0], palette[1], 1, 2)
+				palette[3] = color.NRGBA{}
+			}
+
+			indices := binary.LittleEndian.Uint32(block[4:])
+			for i := range 16 {
+				x, y := bx*4+i%4, by*4+i/4
+				if x >= width || y >= height {
+					continue
+				}
+
+				c := palette[indices>>(2*i)&3]
+				if c.A != 0 {
+					c.A = alphas[i]
+				}
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// decodeDXT5Alpha decodes 16 interpolated alpha values of DXT5 block.
+func decodeDXT5Alpha(block []byte) [16]uint8 {
+	var table [8]uint8
+	a0, a1 := int(block[0]), int(block[1])
+	table[0], table[1] = uint8(a0), uint8(a1)
+	if a0 > a1 {
+		for i := 1; i < 7; i++ {
+			table[i+1] = uint8(((7-i)*a0 + i*a1) / 7)
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			table[i+1] = uint8(((5-i)*a0 + i*a1) / 5)
+		}
+		table[6], table[7] = 0, 255
+	}
+
+	var bits uint64
+	for i := 7; i >= 2; i-- {
+		bits = bits<<8 | uint64(block[i])
+	}
+
+	var out [16]uint8
+	for i := range out {
+		out[i] = table[bits>>(3*i)&7]
+	}
+
+	return out
+}
+
+// rgb565 expands 16-bit RGB 5:6:5 color to opaque NRGBA.
+func rgb565(v uint16) color.NRGBA {
+	return color.NRGBA{
+		R: expand5(v >> 11),
+		G: uint8(v>>5&0x3F)<<2 | uint8(v>>9&0x3),
+		B: expand5(v),
+		A: 255,
+	}
+}
+
+// expand5 expands low 5 bits of v to 8-bit channel.
+func expand5(v uint16) uint8 {
+	c := uint8(v & 0x1F)
+	return c<<3 | c>>2
+}
+
+// lerpNRGBA returns a + (b-a)*num/den per channel.
+func lerpNRGBA(a, b color.NRGBA, num, den int) color.NRGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8((int(x)*(den-num) + int(y)*num) / den)
+	}
+
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
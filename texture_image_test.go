@@ -0,0 +1,123 @@
+package rvmat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func buildTestPAA(typ uint16, width, height uint16, payload []byte) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, typ)
+	buf.WriteString("GGATCGVA")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(4))
+	buf.Write([]byte{1, 2, 3, 4})
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(0))
+	_ = binary.Write(&buf, binary.LittleEndian, width)
+	_ = binary.Write(&buf, binary.LittleEndian, height)
+	buf.Write([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16)})
+	buf.Write(payload)
+	buf.Write([]byte{0, 0, 0, 0})
+
+	return buf.Bytes()
+}
+
+func TestDecodeTextureImageTGA(t *testing.T) {
+	header := func(imageType, bpp, descriptor byte) []byte {
+		h := make([]byte, 18)
+		h[2], h[16], h[17] = imageType, bpp, descriptor
+		binary.LittleEndian.PutUint16(h[12:], 2)
+		binary.LittleEndian.PutUint16(h[14:], 1)
+		return h
+	}
+
+	// Bottom-up 24-bit BGR, single row.
+	img, err := DecodeTextureImage("a.tga", append(header(2, 24, 0), 0, 0, 255, 255, 0, 0))
+	if err != nil {
+		t.Fatalf("decode tga: %v", err)
+	}
+	if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c != (color.NRGBA{R: 255, A: 255}) {
+		t.Fatalf("unexpected tga pixel: %+v", c)
+	}
+
+	// RLE 32-bit: one repeated packet of 2 pixels.
+	img, err = DecodeTextureImage("a.TGA", append(header(10, 32, 0x20), 0x81, 10, 20, 30, 40))
+	if err != nil {
+		t.Fatalf("decode rle tga: %v", err)
+	}
+	if c := color.NRGBAModel.Convert(img.At(1, 0)).(color.NRGBA); c != (color.NRGBA{R: 30, G: 20, B: 10, A: 40}) {
+		t.Fatalf("unexpected rle tga pixel: %+v", c)
+	}
+
+	if _, err := DecodeTextureImage("a.tga", header(1, 8, 0)); !errors.Is(err, ErrInvalidTextureImage) {
+		t.Fatalf("expected ErrInvalidTextureImage, got %v", err)
+	}
+}
+
+func TestDecodeTextureImagePAA(t *testing.T) {
+	// DXT1 block: red/blue endpoints, first row uses indices 0,1,2,3.
+	block := []byte{0x00, 0xF8, 0x1F, 0x00, 0xE4, 0, 0, 0}
+	img, err := DecodeTextureImage(`mod\data\box_co.paa`, buildTestPAA(paaTypeDXT1, 4, 4, block))
+	if err != nil {
+		t.Fatalf("decode dxt1: %v", err)
+	}
+	nrgba := img.(interface{ NRGBAAt(x, y int) color.NRGBA })
+	if c := nrgba.NRGBAAt(0, 0); c != (color.NRGBA{R: 255, A: 255}) {
+		t.Fatalf("unexpected dxt1 color0: %+v", c)
+	}
+	if c := nrgba.NRGBAAt(1, 0); c != (color.NRGBA{B: 255, A: 255}) {
+		t.Fatalf("unexpected dxt1 color1: %+v", c)
+	}
+	if c := nrgba.NRGBAAt(2, 0); c.R != 170 || c.B != 85 {
+		t.Fatalf("unexpected dxt1 interpolated color: %+v", c)
+	}
+
+	// DXT5 alpha endpoints 255/0 with all indices 1 -> alpha 0.
+	dxt5 := append([]byte{255, 0, 0x49, 0x92, 0x24, 0x49, 0x92, 0x24}, block...)
+	img, err = DecodeTextureImage("a.paa", buildTestPAA(paaTypeDXT5, 4, 4, dxt5))
+	if err != nil {
+		t.Fatalf("decode dxt5: %v", err)
+	}
+	if c := img.(interface{ NRGBAAt(x, y int) color.NRGBA }).NRGBAAt(3, 3); c.A != 0 {
+		t.Fatalf("unexpected dxt5 alpha: %+v", c)
+	}
+
+	// ARGB4444 2x1, LZSS-compressed (all literals).
+	plain := []byte{0x21, 0xF3, 0x00, 0x80}
+	packed := lzssWithChecksum(append([]byte{0x0F}, plain...), plain)
+	img, err = DecodeTextureImage("a.pax", buildTestPAA(paaTypeARGB4444, 2, 1, packed))
+	if err != nil {
+		t.Fatalf("decode argb4444: %v", err)
+	}
+	if c := img.(interface{ NRGBAAt(x, y int) color.NRGBA }).NRGBAAt(0, 0); c != (color.NRGBA{R: 51, G: 34, B: 17, A: 255}) {
+		t.Fatalf("unexpected argb4444 pixel: %+v", c)
+	}
+
+	if _, err := DecodeTextureImage("a.paa", buildTestPAA(0x1234, 4, 4, block)); !errors.Is(err, ErrInvalidTextureImage) {
+		t.Fatalf("expected unsupported paa type error, got %v", err)
+	}
+}
+
+func TestDecompressLZO(t *testing.T) {
+	// 4 initial literals, M2 match (distance 4, length 4), literal run of 4, end marker.
+	stream := []byte{21, 'a', 'b', 'c', 'd', 0x6C, 0x00, 0x01, 'e', 'f', 'g', 'h', 0x11, 0x00, 0x00}
+	got, err := decompressLZO(stream, 12)
+	if err != nil || string(got) != "abcdabcdefgh" {
+		t.Fatalf("decompressLZO = %q, %v", got, err)
+	}
+
+	if _, err := decompressLZO(stream[:8], 12); err == nil {
+		t.Fatal("expected truncated stream error")
+	}
+
+	// Literal and M3 match with long zero-run length extension; match must
+	// be rejected before copying, not after running off truncated input.
+	long := append([]byte{18, 'a', 0x20}, make([]byte, 1000)...)
+	long = append(long, 1, 0x00, 0x00)
+	if _, err := decompressLZO(long, 16); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected match length to be capped by output size, got %v", err)
+	}
+}
@@ -6,14 +6,36 @@ package rvmat
 
 import (
 	"errors"
-	"os"
+	"fmt"
 	"strings"
 
 	"github.com/woozymasta/lintkit/lint"
 )
 
 // Validate validates a material and returns issues.
+//
+// Inline rvmat-ignore comments of parsed materials are honoured and unused
+// ones are reported as RVMAT2031. Fix suggestions for returned diagnostics
+// go to ValidateOptions.OnFix when set.
 func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
+	return reportFixes(m, applyInlineSuppressions(m, validateMaterial(m, opt), false), opt)
+}
+
+// reportFixes passes fix suggestions for diagnostics to ValidateOptions.OnFix.
+func reportFixes(m *Material, diagnostics []lint.Diagnostic, opt *ValidateOptions) []lint.Diagnostic {
+	if opt == nil || opt.OnFix == nil {
+		return diagnostics
+	}
+
+	for _, fix := range SuggestFixes(m, diagnostics, opt) {
+		opt.OnFix(fix)
+	}
+
+	return diagnostics
+}
+
+// validateMaterial runs material checks without inline suppressions.
+func validateMaterial(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 	vopt := opt.normalize()
 	var out []lint.Diagnostic
 
@@ -36,21 +58,37 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 
 	if !vopt.DisableShaderNameCheck {
 		if m.PixelShaderID != "" {
-			if !isKnownNameCI(knownPixelShaderID, m.PixelShaderID) {
-				out = append(out, warningDiagnostic(
+			canonical, ok := canonicalKnownName(knownPixelShaderID, m.PixelShaderID)
+			switch {
+			case !ok:
+				out = append(out, withSourceSpan(warningDiagnostic(
 					CodeValidateUnknownPixelShaderID,
 					"unknown PixelShaderID",
 					m.PixelShaderID,
-				))
+				), materialKeySpan(m, "PixelShaderID")))
+			case vopt.EnableShaderCaseCheck && canonical != m.PixelShaderID:
+				out = append(out, withSourceSpan(infoDiagnostic(
+					CodeValidatePixelShaderIDCase,
+					"PixelShaderID casing differs from "+canonical,
+					m.PixelShaderID,
+				), materialKeySpan(m, "PixelShaderID")))
 			}
 		}
 		if m.VertexShaderID != "" {
-			if !isKnownNameCI(knownVertexShaderID, m.VertexShaderID) {
-				out = append(out, warningDiagnostic(
+			canonical, ok := canonicalKnownName(knownVertexShaderID, m.VertexShaderID)
+			switch {
+			case !ok:
+				out = append(out, withSourceSpan(warningDiagnostic(
 					CodeValidateUnknownVertexShaderID,
 					"unknown VertexShaderID",
 					m.VertexShaderID,
-				))
+				), materialKeySpan(m, "VertexShaderID")))
+			case vopt.EnableShaderCaseCheck && canonical != m.VertexShaderID:
+				out = append(out, withSourceSpan(infoDiagnostic(
+					CodeValidateVertexShaderIDCase,
+					"VertexShaderID casing differs from "+canonical,
+					m.VertexShaderID,
+				), materialKeySpan(m, "VertexShaderID")))
 			}
 		}
 	}
@@ -64,40 +102,45 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 			continue
 		}
 
-		out = append(out, validateUVTransform(texGen.Name, texGen.UVTransform)...)
+		out = append(out, withSourceSpans(
+			validateUVTransform(texGen.Name, texGen.UVTransform),
+			texGen.source.keySpan("uvTransform"),
+		)...)
 	}
 
-	out = append(out, validateColor("ambient", m.Ambient)...)
-	out = append(out, validateColor("diffuse", m.Diffuse)...)
-	out = append(out, validateColor("forcedDiffuse", m.ForcedDiffuse)...)
-	out = append(out, validateColor("emissive", m.Emissive)...)
-	out = append(out, validateColor("specular", m.Specular)...)
+	out = append(out, withSourceSpans(validateColor("ambient", m.Ambient), materialKeySpan(m, "ambient"))...)
+	out = append(out, withSourceSpans(validateColor("diffuse", m.Diffuse), materialKeySpan(m, "diffuse"))...)
+	out = append(out, withSourceSpans(validateColor("forcedDiffuse", m.ForcedDiffuse), materialKeySpan(m, "forcedDiffuse"))...)
+	out = append(out, withSourceSpans(validateColor("emissive", m.Emissive), materialKeySpan(m, "emmisive"))...)
+	out = append(out, withSourceSpans(validateColor("specular", m.Specular), materialKeySpan(m, "specular"))...)
+	out = append(out, validateNumberLiterals(m)...)
 
 	// Check if path-mode validation or extension validation is enabled.
 	if vopt.TexturePathMode != TexturePathModeIgnore || !vopt.DisableExtensionsCheck {
-		resolver := PathResolver{GameRoot: vopt.GameRoot}
+		resolver := PathResolver{GameRoot: vopt.GameRoot, FileSystem: vopt.FileSystem}
 		for _, st := range m.Stages {
 			tex := st.Texture
 			if tex.Raw == "" || tex.IsProcedural() {
 				continue
 			}
 
+			textureSpan := st.source.keySpan("texture")
 			if !vopt.DisableExtensionsCheck {
 				if !hasAllowedExt(tex.Raw) {
-					out = append(out, warningDiagnostic(
+					out = append(out, withSourceSpan(warningDiagnostic(
 						CodeValidateUnexpectedTextureExtension,
 						"unexpected texture extension",
 						tex.Raw,
-					))
+					), textureSpan))
 				}
 			}
 
 			if strings.Contains(tex.Raw, "..") {
-				out = append(out, warningDiagnostic(
+				out = append(out, withSourceSpan(warningDiagnostic(
 					CodeValidateTexturePathParentTraversal,
 					"texture path contains '..'",
 					tex.Raw,
-				))
+				), textureSpan))
 			}
 
 			if vopt.TexturePathMode == TexturePathModeIgnore {
@@ -115,25 +158,28 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 
 			p := resolver.ResolvePath(tex.Raw)
 			if p != "" {
-				if _, err := os.Stat(p); err != nil {
-					out = append(out, warningDiagnostic(
+				if _, err := resolver.Stat(tex.Raw); err != nil {
+					out = append(out, withSourceSpan(warningDiagnostic(
 						CodeValidateTextureFileNotFound,
 						"texture file not found",
 						p,
-					))
+					), textureSpan))
 				}
 			}
 		}
 	}
 
+	out = append(out, validateSurfaceInfo(m, vopt)...)
+
 	for _, st := range m.Stages {
+		stageSpan := st.source.keySpan("")
 		if !vopt.DisableShaderNameCheck {
 			if !isKnownNameCI(knownStageNames, st.Name) {
-				out = append(out, warningDiagnostic(
+				out = append(out, withSourceSpan(warningDiagnostic(
 					CodeValidateUnknownStageName,
 					"unknown Stage name",
 					st.Name,
-				))
+				), stageSpan))
 			}
 		}
 
@@ -147,6 +193,7 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 		if st.TexGen != "" {
 			resolved, err := ResolveStageTexGen(m, st)
 			if err != nil {
+				texGenStart := len(out)
 				switch {
 				case errors.Is(err, ErrTexGenNotFound):
 					out = append(out, warningDiagnostic(
@@ -177,6 +224,7 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 					))
 				}
 
+				withSourceSpans(out[texGenStart:], st.source.keySpan("texGen"))
 				continue
 			}
 
@@ -188,7 +236,10 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 
 		// No UVs expected.
 		if uvTransform != nil {
-			out = append(out, validateUVTransform(st.Name, uvTransform)...)
+			out = append(out, withSourceSpans(
+				validateUVTransform(st.Name, uvTransform),
+				st.source.keySpan("uvTransform"),
+			)...)
 		}
 
 		if uvSource == "none" || uvSource == "WorldPos" {
@@ -197,25 +248,25 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 
 		// Check if effective uvSource/uvTransform are missing.
 		if uvSource == "" && uvTransform == nil {
-			out = append(out, warningDiagnostic(
+			out = append(out, withSourceSpan(warningDiagnostic(
 				CodeValidateStageMissingEffectiveUVSource,
 				"stage missing effective uvSource",
 				st.Name,
-			))
-			out = append(out, warningDiagnostic(
+			), stageSpan))
+			out = append(out, withSourceSpan(warningDiagnostic(
 				CodeValidateStageMissingEffectiveUVTransform,
 				"stage missing effective uvTransform",
 				st.Name,
-			))
+			), stageSpan))
 			continue
 		}
 
 		if uvTransform == nil {
-			out = append(out, warningDiagnostic(
+			out = append(out, withSourceSpan(warningDiagnostic(
 				CodeValidateStageMissingEffectiveUVTransform,
 				"stage missing effective uvTransform",
 				st.Name,
-			))
+			), stageSpan))
 		}
 	}
 
@@ -225,11 +276,11 @@ func Validate(m *Material, opt *ValidateOptions) []lint.Diagnostic {
 			continue
 		}
 		if _, ok := seen[st.Name]; ok {
-			out = append(out, errorDiagnostic(
+			out = append(out, withSourceSpan(errorDiagnostic(
 				CodeValidateDuplicateStageName,
 				"duplicate Stage name",
 				st.Name,
-			))
+			), st.source.keySpan("")))
 			continue
 		}
 		seen[st.Name] = struct{}{}
@@ -257,6 +308,39 @@ func isKnownNameCI(known map[string]struct{}, value string) bool {
 	return false
 }
 
+// canonicalKnownName returns canonical spelling for case-insensitive known name.
+//
+// When several spellings are known, the one starting with an upper-case
+// letter wins, so "super" maps to "Super".
+func canonicalKnownName(known map[string]struct{}, value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	canonical := ""
+	for k := range known {
+		if !strings.EqualFold(k, value) {
+			continue
+		}
+		if canonical == "" || isCanonicalNameBetter(k, canonical) {
+			canonical = k
+		}
+	}
+
+	return canonical, canonical != ""
+}
+
+// isCanonicalNameBetter reports whether candidate is preferred over current spelling.
+func isCanonicalNameBetter(candidate, current string) bool {
+	candidateUpper := candidate[0] >= 'A' && candidate[0] <= 'Z'
+	currentUpper := current[0] >= 'A' && current[0] <= 'Z'
+	if candidateUpper != currentUpper {
+		return candidateUpper
+	}
+
+	return candidate < current
+}
+
 // validateShaderProfiles performs soft expected-stage checks for known shaders.
 func validateShaderProfiles(m *Material) []lint.Diagnostic {
 	ps := strings.ToLower(strings.TrimSpace(m.PixelShaderID))
@@ -376,13 +460,15 @@ func validateExpectedStageSet(seen map[string]struct{}, required, optional []str
 }
 
 // ValidateWithTextureOptions validates a material and its textures.
+//
+// Fix suggestions go to ValidateOptions.OnFix like in Validate.
 func ValidateWithTextureOptions(m *Material, opt *ValidateOptions, texOpt *TextureValidateOptions) []lint.Diagnostic {
-	out := Validate(m, opt)
+	out := validateMaterial(m, opt)
 	if m == nil {
 		return out
 	}
 	if texOpt == nil {
-		return out
+		return reportFixes(m, applyInlineSuppressions(m, out, false), opt)
 	}
 
 	for _, st := range m.Stages {
@@ -390,10 +476,10 @@ func ValidateWithTextureOptions(m *Material, opt *ValidateOptions, texOpt *Textu
 		for i := range issues {
 			issues[i] = withStageContext(issues[i], st.Name)
 		}
-		out = append(out, issues...)
+		out = append(out, withSourceSpans(issues, st.source.keySpan("texture"))...)
 	}
 
-	return out
+	return reportFixes(m, applyInlineSuppressions(m, out, true), opt)
 }
 
 // validateColor validates a color.
@@ -412,6 +498,61 @@ func validateColor(name string, vals []float64) []lint.Diagnostic {
 	return nil
 }
 
+// validateNumberLiterals reports numeric tokens that ParseOptions.EvalExpressions
+// could not evaluate and parsed as 0.
+func validateNumberLiterals(m *Material) []lint.Diagnostic {
+	var out []lint.Diagnostic
+	for _, key := range []string{"ambient", "diffuse", "forcedDiffuse", "emmisive", "specular", "specularPower"} {
+		for _, d := range unresolvedLiteralDiagnostics(key, m.literals[strings.ToLower(key)]) {
+			out = append(out, withSourceSpan(d, materialKeySpan(m, key)))
+		}
+	}
+
+	uvKeys := []string{"aside", "up", "dir", "pos"}
+	for _, st := range m.Stages {
+		if st.UVTransform == nil {
+			continue
+		}
+		for _, key := range uvKeys {
+			out = append(out, withSourceSpans(
+				unresolvedLiteralDiagnostics(st.Name+".uvTransform."+key, st.UVTransform.literals[key]),
+				st.source.keySpan("uvTransform"),
+			)...)
+		}
+	}
+	for _, tg := range m.TexGens {
+		if tg.UVTransform == nil {
+			continue
+		}
+		for _, key := range uvKeys {
+			out = append(out, withSourceSpans(
+				unresolvedLiteralDiagnostics(tg.Name+".uvTransform."+key, tg.UVTransform.literals[key]),
+				tg.source.keySpan("uvTransform"),
+			)...)
+		}
+	}
+
+	return out
+}
+
+// unresolvedLiteralDiagnostics builds diagnostics for unresolved literals of key.
+func unresolvedLiteralDiagnostics(path string, lits []numberLiteral) []lint.Diagnostic {
+	var out []lint.Diagnostic
+	for i, lit := range lits {
+		if !lit.unresolved {
+			continue
+		}
+
+		out = append(out, warningDiagnostic(
+			CodeValidateUnresolvedNumericExpression,
+			"numeric expression could not be evaluated",
+			fmt.Sprintf("%s[%d]: %s", path, i, lit.text),
+		))
+	}
+
+	return out
+}
+
 // validateUVTransform validates uvTransform vectors layout.
 func validateUVTransform(path string, transform *UVTransform) []lint.Diagnostic {
 	if transform == nil {
@@ -603,7 +744,7 @@ func validateTexture(t TextureRef, opt TextureValidateOptions) []lint.Diagnostic
 		}
 	}
 
-	if !opt.DisableTextureTagCheck && fn == "color" {
+	if !opt.DisableTextureTagCheck && (fn == "color" || fn == "point") {
 		tag := ""
 		if pt.Color != nil {
 			tag = pt.Color.Tag
@@ -633,8 +774,12 @@ func proceduralArgsOK(fn string, args []string) bool {
 		return len(args) == 2
 	case "fresnelglass":
 		return len(args) == 0 || len(args) == 1 || len(args) == 2
-	case "irradiance":
+	case "irradiance", "waterirradiance", "treecrown", "treecrownamb":
 		return len(args) == 1
+	case "perlinnoise":
+		return len(args) == 4
+	case "point":
+		return len(args) == 4 || len(args) == 5
 	default:
 		return true
 	}
@@ -656,8 +801,18 @@ func proceduralNumericArgsOK(fn string, pt *ProceduralTexture, args []string) bo
 			return true
 		}
 		return pt.Fresnel != nil
-	case "irradiance":
+	case "irradiance", "waterirradiance":
 		return pt.Irradiance != nil
+	case "perlinnoise":
+		// Scales are noise periods in texels and must be positive.
+		return pt.PerlinNoise != nil &&
+			pt.PerlinNoise.XScale > 0 &&
+			pt.PerlinNoise.YScale > 0 &&
+			pt.PerlinNoise.Min <= pt.PerlinNoise.Max
+	case "treecrown", "treecrownamb":
+		return pt.TreeCrown != nil && pt.TreeCrown.Density >= 0
+	case "point":
+		return pt.Color != nil
 	default:
 		return true
 	}
@@ -102,16 +102,23 @@ var knownStageNames = map[string]struct{}{
 
 // Known procedural texture functions observed in game data.
 var knownProceduralFns = map[string]struct{}{
-	"color":        {},
-	"fresnel":      {},
-	"fresnelglass": {},
-	"irradiance":   {},
+	"color":           {},
+	"fresnel":         {},
+	"fresnelglass":    {},
+	"irradiance":      {},
+	"waterirradiance": {},
+	"perlinnoise":     {},
+	"treecrown":       {},
+	"treecrownamb":    {},
+	"point":           {},
 }
 
 // Known procedural texture header formats.
 var knownProceduralFormats = map[string]struct{}{
 	"argb": {},
 	"ai":   {},
+	"dxt1": {},
+	"dxt5": {},
 }
 
 // Known texture tags observed in procedural color() references.
@@ -21,6 +21,7 @@ const (
 // value represents a parsed value.
 type value struct {
 	Str   string    // String value
+	Lit   string    // Number source text when default formatting would change it
 	Array []value   // Array value
 	Kind  valueKind // Value kind
 	Num   float64   // Number value
@@ -8,32 +8,11 @@ import (
 	"bufio"
 	"bytes"
 	"io"
-	"math"
 	"os"
 	"strconv"
 	"strings"
 )
 
-const (
-	prettyFloatDigits = 4
-)
-
-// formatPrettyFloat formats float values without common IEEE tail noise.
-func formatPrettyFloat(v float64) string {
-	if math.IsNaN(v) || math.IsInf(v, 0) {
-		return strconv.FormatFloat(v, 'g', -1, 64)
-	}
-
-	short := strconv.FormatFloat(v, 'f', prettyFloatDigits, 64)
-	short = strings.TrimRight(short, "0")
-	short = strings.TrimRight(short, ".")
-	if short == "" || short == "-0" {
-		short = "0"
-	}
-
-	return short
-}
-
 // Encode writes a Material to writer.
 func Encode(w io.Writer, m *Material, opt *FormatOptions) error {
 	fopt := opt.normalize()
@@ -41,6 +20,7 @@ func Encode(w io.Writer, m *Material, opt *FormatOptions) error {
 	wr := &writer{
 		w:             bw,
 		indent:        fopt.Indent,
+		precision:     fopt.Precision,
 		compactStages: fopt.CompactStages,
 	}
 	if err := wr.writeMaterial(m); err != nil {
@@ -71,6 +51,7 @@ func Format(m *Material, opt *FormatOptions) ([]byte, error) {
 	wr := &writer{
 		w:             &buf,
 		indent:        fopt.Indent,
+		precision:     fopt.Precision,
 		compactStages: fopt.CompactStages,
 	}
 	if err := wr.writeMaterial(m); err != nil {
@@ -94,12 +75,14 @@ type writer struct {
 	w             io.Writer // Writer to write to
 	indent        string    // Indentation string
 	level         int       // Current nesting level
+	precision     int       // Fraction digits for numbers without source text
 	compactStages bool      // Compact formatting for simple StageN classes
 }
 
 // writeMaterial writes a Material to the writer.
 func (w *writer) writeMaterial(m *Material) error {
 	writeArray := func(name string, vals []float64) error {
+		lits := m.literals.lookup(name, len(vals))
 		if len(vals) == 0 {
 			return nil
 		}
@@ -109,7 +92,7 @@ func (w *writer) writeMaterial(m *Material) error {
 		if err := w.writeString("[]="); err != nil {
 			return err
 		}
-		if err := w.writeFloatArray(vals); err != nil {
+		if err := w.writeFloatArray(vals, lits); err != nil {
 			return err
 		}
 		return w.writeString(";\n")
@@ -137,7 +120,7 @@ func (w *writer) writeMaterial(m *Material) error {
 		if err := w.writeString("specularPower="); err != nil {
 			return err
 		}
-		if err := w.writeNumber(*m.SpecularPower); err != nil {
+		if err := w.writeLiteralNumber(*m.SpecularPower, m.literals.lookup("specularPower", 1), 0); err != nil {
 			return err
 		}
 		if err := w.writeString(";\n"); err != nil {
@@ -394,7 +377,7 @@ func (w *writer) writeUVTransform(uv UVTransform) error {
 		if err := w.writeString("aside[]="); err != nil {
 			return err
 		}
-		if err := w.writeFloatArray(uv.Aside); err != nil {
+		if err := w.writeFloatArray(uv.Aside, uv.literals.lookup("aside", len(uv.Aside))); err != nil {
 			return err
 		}
 		if err := w.writeString(";\n"); err != nil {
@@ -410,7 +393,7 @@ func (w *writer) writeUVTransform(uv UVTransform) error {
 		if err := w.writeString("up[]="); err != nil {
 			return err
 		}
-		if err := w.writeFloatArray(uv.Up); err != nil {
+		if err := w.writeFloatArray(uv.Up, uv.literals.lookup("up", len(uv.Up))); err != nil {
 			return err
 		}
 		if err := w.writeString(";\n"); err != nil {
@@ -426,7 +409,7 @@ func (w *writer) writeUVTransform(uv UVTransform) error {
 		if err := w.writeString("dir[]="); err != nil {
 			return err
 		}
-		if err := w.writeFloatArray(uv.Dir); err != nil {
+		if err := w.writeFloatArray(uv.Dir, uv.literals.lookup("dir", len(uv.Dir))); err != nil {
 			return err
 		}
 		if err := w.writeString(";\n"); err != nil {
@@ -442,7 +425,7 @@ func (w *writer) writeUVTransform(uv UVTransform) error {
 		if err := w.writeString("pos[]="); err != nil {
 			return err
 		}
-		if err := w.writeFloatArray(uv.Pos); err != nil {
+		if err := w.writeFloatArray(uv.Pos, uv.literals.lookup("pos", len(uv.Pos))); err != nil {
 			return err
 		}
 		if err := w.writeString(";\n"); err != nil {
@@ -582,21 +565,28 @@ func (w *writer) writeIndent() error {
 
 // writeNumber writes a float64 value to the writer.
 func (w *writer) writeNumber(v float64) error {
-	_, err := io.WriteString(w.w, formatPrettyFloat(v))
+	_, err := io.WriteString(w.w, formatFloatPrecision(v, w.precision))
 
 	return err
 }
 
-// writeQuoted writes a quoted string to the writer.
-func (w *writer) writeQuoted(s string) error {
-	if err := w.writeString("\""); err != nil {
-		return err
-	}
-	if err := w.writeString(s); err != nil {
-		return err
+// writeLiteralNumber writes element i source text while unchanged, or v.
+func (w *writer) writeLiteralNumber(v float64, lits []numberLiteral, i int) error {
+	if text, ok := literalText(lits, i, v); ok {
+		return w.writeString(text)
 	}
 
-	return w.writeString("\"")
+	return w.writeNumber(v)
+}
+
+// writeQuoted writes a quoted config string to the writer.
+func (w *writer) writeQuoted(s string) error {
+	return w.writeString(quoteConfigString(s))
+}
+
+// quoteConfigString wraps s in double quotes, doubling embedded quotes.
+func quoteConfigString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
 }
 
 // writeString writes a string to the writer.
@@ -609,6 +599,9 @@ func (w *writer) writeString(s string) error {
 func (w *writer) writeValue(v value) error {
 	switch v.Kind {
 	case valueNumber:
+		if v.Lit != "" {
+			return w.writeString(v.Lit)
+		}
 		return w.writeNumber(v.Num)
 	case valueString:
 		return w.writeQuoted(v.Str)
@@ -643,8 +636,9 @@ func (w *writer) writeArray(vals []value) error {
 	return w.writeString("}")
 }
 
-// writeFloatArray writes a slice of float64 values to the writer.
-func (w *writer) writeFloatArray(vals []float64) error {
+// writeFloatArray writes a slice of float64 values to the writer,
+// keeping source text of unchanged elements.
+func (w *writer) writeFloatArray(vals []float64, lits []numberLiteral) error {
 	if err := w.writeString("{"); err != nil {
 		return err
 	}
@@ -656,7 +650,7 @@ func (w *writer) writeFloatArray(vals []float64) error {
 				return err
 			}
 		}
-		if err := w.writeNumber(v); err != nil {
+		if err := w.writeLiteralNumber(v, lits, i); err != nil {
 			return err
 		}
 	}